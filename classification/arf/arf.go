@@ -0,0 +1,3 @@
+// Package arf implements adaptive random forests of Hoeffding trees for
+// classification.
+package arf
@@ -0,0 +1,21 @@
+package arf
+
+import (
+	"github.com/bsm/reason/classification/hoeffding"
+	common "github.com/bsm/reason/common/arf"
+)
+
+// Config configures behaviour
+type Config struct {
+	common.Config
+
+	// The configuration of member trees. Each member is seeded
+	// individually.
+	// Default: FeatureSubspace is √M+1, where M is the number of features
+	Tree hoeffding.Config
+}
+
+// Norm inits and normalizes the config
+func (c *Config) Norm() {
+	c.Config.Norm()
+}
@@ -0,0 +1,173 @@
+package arf
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/bsm/reason/classification"
+	"github.com/bsm/reason/classification/hoeffding"
+	"github.com/bsm/reason/core"
+	"github.com/bsm/reason/internal/forest"
+)
+
+// Forest is an adaptive random forest of Hoeffding trees.
+type Forest struct {
+	forest *forest.Forest
+	mu     sync.RWMutex
+}
+
+// New inits a new forest using a model, a target feature and a config.
+func New(model *core.Model, target string, config *Config) (*Forest, error) {
+	config = normConfig(config)
+
+	f, err := forest.New(model, target, newFactory(config), config.Config)
+	if err != nil {
+		return nil, err
+	}
+	return &Forest{forest: f}, nil
+}
+
+// Load loads a forest from a reader. The number of members is
+// determined by the stored forest.
+func Load(r io.Reader, config *Config) (*Forest, error) {
+	config = normConfig(config)
+
+	f, err := forest.Load(r, newFactory(config), config.Config)
+	if err != nil {
+		return nil, err
+	}
+	return &Forest{forest: f}, nil
+}
+
+func normConfig(c *Config) *Config {
+	var config Config
+	if c != nil {
+		config = *c
+	}
+	config.Norm()
+	return &config
+}
+
+// Size returns the number of members.
+func (f *Forest) Size() int {
+	return len(f.forest.Members)
+}
+
+// Predict appends the aggregated prediction of all members for the given
+// example x to dst. Member predictions are normalised and weighted by the
+// estimated accuracy of each member.
+func (f *Forest) Predict(dst classification.Predictions, x core.Example) classification.Predictions {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	var res classification.Prediction
+	for _, m := range f.forest.Members {
+		best := m.Tree.(member).Predict(nil, x).Best()
+		if best == nil {
+			continue
+		}
+
+		sum := best.Weight()
+		if sum <= 0 {
+			continue
+		}
+
+		accuracy := 1 - m.ErrorRate()
+		best.ForEach(func(i int, w float64) bool {
+			res.Add(i, accuracy*w/sum)
+			return true
+		})
+	}
+	return append(dst, res)
+}
+
+// Train passes an example x with a weight (usually 1.0) to the forest
+// for training.
+func (f *Forest) Train(x core.Example, weight float64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.forest.Train(x, weight)
+}
+
+// WriteTo implements io.WriterTo
+func (f *Forest) WriteTo(w io.Writer) (int64, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.forest.WriteTo(w)
+}
+
+// --------------------------------------------------------------------
+
+type factory struct {
+	model  *core.Model
+	target *core.Feature
+	config hoeffding.Config
+}
+
+func newFactory(config *Config) forest.NewFactory {
+	return func(model *core.Model, target string) (forest.Factory, error) {
+		feat := model.Feature(target)
+		if feat == nil {
+			return nil, fmt.Errorf("arf: unknown feature %q", target)
+		} else if !feat.Kind.IsCategorical() {
+			return nil, fmt.Errorf("arf: feature %q is not categorical", target)
+		}
+
+		treeConfig := config.Tree
+		if treeConfig.FeatureSubspace == 0 {
+			treeConfig.FeatureSubspace = forest.SubspaceSize(model)
+		}
+		return &factory{model: model, target: feat, config: treeConfig}, nil
+	}
+}
+
+func (f *factory) New(seed int64) (forest.Tree, error) {
+	config := f.config
+	config.Seed = seed
+
+	tree, err := hoeffding.New(f.model, f.target.Name, &config)
+	if err != nil {
+		return nil, err
+	}
+	return member{Tree: tree, target: f.target}, nil
+}
+
+func (f *factory) Load(r io.Reader, seed int64) (forest.Tree, error) {
+	config := f.config
+	config.Seed = seed
+
+	tree, err := hoeffding.Load(r, &config)
+	if err != nil {
+		return nil, err
+	}
+	return member{Tree: tree, target: f.target}, nil
+}
+
+// --------------------------------------------------------------------
+
+type member struct {
+	*hoeffding.Tree
+	target *core.Feature
+}
+
+func (m member) Train(x core.Example, weight float64) {
+	m.Tree.Train(x, weight)
+}
+
+func (m member) Error(x core.Example) float64 {
+	targetCat := m.target.Category(x)
+	if !core.IsCat(targetCat) {
+		return -1
+	}
+
+	// ties including the target category are considered correct
+	if best := m.Predict(nil, x).Best(); best != nil {
+		if _, w := best.TopW(); w > 0 && best.W(targetCat) == w {
+			return 0
+		}
+	}
+	return 1
+}
@@ -0,0 +1,134 @@
+package arf_test
+
+import (
+	"bytes"
+	"math/rand"
+
+	"github.com/bsm/reason/classification/arf"
+	"github.com/bsm/reason/classification/hoeffding"
+	common "github.com/bsm/reason/common/hoeffding"
+	"github.com/bsm/reason/core"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Forest", func() {
+	var features = []string{"a", "b", "c", "d", "e"}
+	var model = core.NewModel(
+		core.NewCategoricalFeature("a", []string{"x", "y"}),
+		core.NewCategoricalFeature("b", []string{"x", "y"}),
+		core.NewCategoricalFeature("c", []string{"x", "y"}),
+		core.NewCategoricalFeature("d", []string{"x", "y"}),
+		core.NewCategoricalFeature("e", []string{"x", "y"}),
+		core.NewCategoricalFeature("target", []string{"x", "y"}),
+	)
+	var stream = func(rnd *rand.Rand, n int, concept string) []core.Example {
+		examples := make([]core.Example, 0, n)
+		for i := 0; i < n; i++ {
+			x := core.MapExample{}
+			for _, name := range features {
+				x[name] = "x"
+				if rnd.Intn(2) == 0 {
+					x[name] = "y"
+				}
+			}
+			x["target"] = x[concept]
+			if rnd.Float64() < 0.1 {
+				x["target"] = x["e"]
+			}
+			examples = append(examples, x)
+		}
+		return examples
+	}
+	var accuracy = func(subject *arf.Forest, examples []core.Example) float64 {
+		n := 0
+		for _, x := range examples {
+			if cat, _ := subject.Predict(nil, x).Best().Top(); cat == model.Feature("target").Category(x) {
+				n++
+			}
+		}
+		return float64(n) / float64(len(examples))
+	}
+	var treeConfig = hoeffding.Config{
+		Config: common.Config{GracePeriod: 50, Deterministic: true},
+	}
+
+	It("should validate", func() {
+		_, err := arf.New(model, "z", nil)
+		Expect(err).To(MatchError(`arf: unknown feature "z"`))
+
+		_, err = arf.New(core.NewModel(core.NewNumericalFeature("target")), "target", nil)
+		Expect(err).To(MatchError(`arf: feature "target" is not categorical`))
+	})
+
+	It("should init", func() {
+		subject, err := arf.New(model, "target", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(subject.Size()).To(Equal(10))
+
+		predictions := subject.Predict(nil, core.MapExample{"a": "x"})
+		Expect(predictions).To(HaveLen(1))
+		Expect(predictions.Best().Weight()).To(Equal(0.0))
+	})
+
+	It("should aggregate predictions", func() {
+		rnd := rand.New(rand.NewSource(1))
+		subject, err := arf.New(model, "target", &arf.Config{Tree: treeConfig})
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, x := range stream(rnd, 3000, "a") {
+			subject.Train(x, 1.0)
+		}
+
+		prediction := subject.Predict(nil, core.MapExample{"a": "x", "b": "y", "c": "y", "d": "y", "e": "y"}).Best()
+		Expect(prediction.Weight()).To(BeNumerically("~", 9.490, 0.001))
+		Expect(prediction.P(0)).To(BeNumerically("~", 0.932, 0.001))
+		Expect(accuracy(subject, stream(rnd, 1000, "a"))).To(BeNumerically("~", 0.954, 0.001))
+	})
+
+	It("should adapt to drift", func() {
+		rnd := rand.New(rand.NewSource(1))
+		subject, err := arf.New(model, "target", &arf.Config{Tree: treeConfig})
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, x := range stream(rnd, 3000, "a") {
+			subject.Train(x, 1.0)
+		}
+		Expect(accuracy(subject, stream(rnd, 1000, "b"))).To(BeNumerically("~", 0.5, 0.01))
+
+		for _, x := range stream(rnd, 2000, "b") {
+			subject.Train(x, 1.0)
+		}
+		Expect(accuracy(subject, stream(rnd, 1000, "b"))).To(BeNumerically("~", 0.956, 0.001))
+	})
+
+	It("should dump/load", func() {
+		rnd := rand.New(rand.NewSource(1))
+		config := &arf.Config{Tree: treeConfig}
+		config.Size = 4
+		f1, err := arf.New(model, "target", config)
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, x := range stream(rnd, 3000, "a") {
+			f1.Train(x, 1.0)
+		}
+		for _, x := range stream(rnd, 500, "b") {
+			f1.Train(x, 1.0)
+		}
+
+		b1 := new(bytes.Buffer)
+		Expect(f1.WriteTo(b1)).To(Equal(int64(b1.Len())))
+
+		f2, err := arf.Load(bytes.NewReader(b1.Bytes()), config)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f2.Size()).To(Equal(4))
+
+		for _, x := range stream(rnd, 100, "b") {
+			Expect(f2.Predict(nil, x)).To(Equal(f1.Predict(nil, x)))
+		}
+
+		b2 := new(bytes.Buffer)
+		Expect(f2.WriteTo(b2)).To(Equal(int64(b2.Len())))
+		Expect(b2.Bytes()).To(Equal(b1.Bytes()))
+	})
+})
@@ -0,0 +1,290 @@
+package ensemble
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand"
+	"sync"
+
+	"github.com/bsm/reason/classification"
+	"github.com/bsm/reason/classification/hoeffding"
+	"github.com/bsm/reason/core"
+	"github.com/bsm/reason/internal/bagging"
+	"github.com/bsm/reason/internal/iocount"
+	"github.com/bsm/reason/internal/protoio"
+	"github.com/bsm/reason/util"
+	"github.com/gogo/protobuf/proto"
+)
+
+// Bagging is an online bagging ensemble of Hoeffding trees.
+type Bagging struct {
+	model  *core.Model
+	target *core.Feature
+
+	members   []*hoeffding.Tree
+	detectors []*util.ADWIN
+
+	config Config
+	rnd    *rand.Rand
+	mu     sync.RWMutex
+}
+
+// New inits a new ensemble using a model, a target feature and a config.
+func New(model *core.Model, target string, config *Config) (*Bagging, error) {
+	b, err := newBagging(model, target, config)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < b.config.Size; i++ {
+		member, err := b.newMember()
+		if err != nil {
+			return nil, err
+		}
+		b.members = append(b.members, member)
+	}
+	b.initDetectors()
+	return b, nil
+}
+
+// Load loads an ensemble from a reader. The number of members is
+// determined by the stored ensemble.
+func Load(r io.Reader, config *Config) (*Bagging, error) {
+	rp := &protoio.Reader{Reader: bufio.NewReader(r)}
+
+	var (
+		model     *core.Model
+		target    string
+		members   [][]byte
+		detectors []*util.ADWIN
+	)
+
+	for {
+		tag, wire, err := rp.ReadField()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if wire != proto.WireBytes {
+			return nil, proto.ErrInternalBadWireType
+		}
+
+		switch tag {
+		case 1: // model
+			model = new(core.Model)
+			if err := rp.ReadMessage(model); err != nil {
+				return nil, err
+			}
+		case 2: // target
+			str, err := rp.ReadString()
+			if err != nil {
+				return nil, err
+			}
+			target = str
+		case 3: // members
+			str, err := rp.ReadString()
+			if err != nil {
+				return nil, err
+			}
+			members = append(members, []byte(str))
+		case 4: // detectors
+			detector := new(util.ADWIN)
+			if err := rp.ReadMessage(detector); err != nil {
+				return nil, err
+			}
+			detectors = append(detectors, detector)
+		default:
+			return nil, fmt.Errorf("ensemble: unexpected field tag %d", tag)
+		}
+	}
+
+	if model == nil || len(members) == 0 {
+		return nil, fmt.Errorf("ensemble: invalid ensemble")
+	}
+
+	b, err := newBagging(model, target, config)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, data := range members {
+		member, err := hoeffding.Load(bytes.NewReader(data), &b.config.Tree)
+		if err != nil {
+			return nil, err
+		}
+		b.members = append(b.members, member)
+	}
+	b.config.Size = len(b.members)
+
+	if len(detectors) == len(b.members) {
+		b.detectors = detectors
+	} else {
+		b.initDetectors()
+	}
+	return b, nil
+}
+
+func newBagging(model *core.Model, target string, c *Config) (*Bagging, error) {
+	var config Config
+	if c != nil {
+		config = *c
+	}
+	config.Norm()
+
+	feat := model.Feature(target)
+	if feat == nil {
+		return nil, fmt.Errorf("ensemble: unknown feature %q", target)
+	} else if !feat.Kind.IsCategorical() {
+		return nil, fmt.Errorf("ensemble: feature %q is not categorical", target)
+	}
+
+	return &Bagging{
+		model:  model,
+		target: feat,
+		config: config,
+		rnd:    rand.New(rand.NewSource(config.Seed)),
+	}, nil
+}
+
+// Size returns the number of members.
+func (b *Bagging) Size() int {
+	return len(b.members)
+}
+
+// Predict aggregates the predictions of all members for the given example x.
+// Member predictions are normalised and summed up.
+func (b *Bagging) Predict(x core.Example) *classification.Prediction {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	res := new(classification.Prediction)
+	for _, member := range b.members {
+		b.aggregate(&res.Vector, member, x)
+	}
+	return res
+}
+
+// Train passes an example x with a weight (usually 1.0) to the ensemble
+// for training. Each member receives a Poisson-weighted copy of x.
+func (b *Bagging) Train(x core.Example, weight float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.config.Method == LeveragingBag {
+		b.detectChanges(x)
+	}
+
+	for _, member := range b.members {
+		if k := bagging.Poisson(b.rnd, b.config.Lambda); k > 0 {
+			member.Train(x, weight*float64(k))
+		}
+	}
+}
+
+// WriteTo implements io.WriterTo
+func (b *Bagging) WriteTo(w io.Writer) (int64, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	wc := &iocount.Writer{W: w}
+	wp := &protoio.Writer{Writer: bufio.NewWriter(wc)}
+
+	if err := wp.WriteMessageField(1, b.model); err != nil {
+		return wc.N, err
+	}
+	if err := wp.WriteStringField(2, b.target.Name); err != nil {
+		return wc.N, err
+	}
+
+	buf := new(bytes.Buffer)
+	for _, member := range b.members {
+		buf.Reset()
+		if _, err := member.WriteTo(buf); err != nil {
+			return wc.N, err
+		}
+		if err := wp.WriteStringField(3, buf.String()); err != nil {
+			return wc.N, err
+		}
+	}
+	for _, detector := range b.detectors {
+		if err := wp.WriteMessageField(4, detector); err != nil {
+			return wc.N, err
+		}
+	}
+	return wc.N, wp.Flush()
+}
+
+func (b *Bagging) newMember() (*hoeffding.Tree, error) {
+	return hoeffding.New(b.model, b.target.Name, &b.config.Tree)
+}
+
+func (b *Bagging) initDetectors() {
+	b.detectors = b.detectors[:0]
+	if b.config.Method != LeveragingBag {
+		return
+	}
+
+	for range b.members {
+		b.detectors = append(b.detectors, util.NewADWIN(b.config.DriftConfidence))
+	}
+}
+
+// detectChanges tracks the error of each member and replaces the
+// member with the highest error once a change has been detected.
+func (b *Bagging) detectChanges(x core.Example) {
+	targetCat := b.target.Category(x)
+	if !core.IsCat(targetCat) {
+		return
+	}
+
+	changed := false
+	for i, member := range b.members {
+		errValue := 1.0
+		if best := member.Predict(nil, x).Best(); best != nil {
+			if cat, _ := best.Top(); cat == targetCat {
+				errValue = 0.0
+			}
+		}
+		if b.detectors[i].Add(errValue) {
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+
+	worst := 0
+	for i, detector := range b.detectors {
+		if detector.Mean() > b.detectors[worst].Mean() {
+			worst = i
+		}
+	}
+
+	// keep the current member if no replacement can be built
+	member, err := b.newMember()
+	if err != nil {
+		return
+	}
+	b.members[worst] = member
+	b.detectors[worst] = util.NewADWIN(b.config.DriftConfidence)
+}
+
+func (b *Bagging) aggregate(dst *util.Vector, member *hoeffding.Tree, x core.Example) {
+	best := member.Predict(nil, x).Best()
+	if best == nil {
+		return
+	}
+
+	sum := best.Weight()
+	if sum <= 0 {
+		return
+	}
+
+	best.ForEach(func(i int, w float64) bool {
+		dst.Add(i, w/sum)
+		return true
+	})
+}
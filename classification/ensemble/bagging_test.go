@@ -0,0 +1,135 @@
+package ensemble_test
+
+import (
+	"bytes"
+	"math/rand"
+
+	"github.com/bsm/reason/classification/ensemble"
+	"github.com/bsm/reason/classification/hoeffding"
+	common "github.com/bsm/reason/common/hoeffding"
+	"github.com/bsm/reason/core"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Bagging", func() {
+	var model = core.NewModel(
+		core.NewCategoricalFeature("a", []string{"x", "y"}),
+		core.NewCategoricalFeature("b", []string{"x", "y"}),
+		core.NewCategoricalFeature("c", []string{"x", "y"}),
+		core.NewCategoricalFeature("target", []string{"x", "y"}),
+	)
+	var stream = func(rnd *rand.Rand, n int, concept string) []core.Example {
+		examples := make([]core.Example, 0, n)
+		for i := 0; i < n; i++ {
+			x := core.MapExample{}
+			for _, name := range []string{"a", "b", "c"} {
+				x[name] = "x"
+				if rnd.Intn(2) == 0 {
+					x[name] = "y"
+				}
+			}
+			x["target"] = x[concept]
+			if rnd.Float64() < 0.1 {
+				x["target"] = x["c"]
+			}
+			examples = append(examples, x)
+		}
+		return examples
+	}
+	var accuracy = func(subject *ensemble.Bagging, examples []core.Example) float64 {
+		n := 0
+		for _, x := range examples {
+			if cat, _ := subject.Predict(x).Top(); cat == model.Feature("target").Category(x) {
+				n++
+			}
+		}
+		return float64(n) / float64(len(examples))
+	}
+	var treeConfig = hoeffding.Config{
+		Config: common.Config{GracePeriod: 50},
+	}
+
+	It("should validate", func() {
+		_, err := ensemble.New(model, "z", nil)
+		Expect(err).To(MatchError(`ensemble: unknown feature "z"`))
+
+		_, err = ensemble.New(core.NewModel(core.NewNumericalFeature("target")), "target", nil)
+		Expect(err).To(MatchError(`ensemble: feature "target" is not categorical`))
+	})
+
+	It("should init", func() {
+		subject, err := ensemble.New(model, "target", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(subject.Size()).To(Equal(10))
+		Expect(subject.Predict(core.MapExample{"a": "x"}).Weight()).To(Equal(0.0))
+	})
+
+	It("should aggregate predictions", func() {
+		rnd := rand.New(rand.NewSource(1))
+		subject, err := ensemble.New(model, "target", &ensemble.Config{Size: 5, Tree: treeConfig})
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, x := range stream(rnd, 3000, "a") {
+			subject.Train(x, 1.0)
+		}
+
+		prediction := subject.Predict(core.MapExample{"a": "x", "b": "y", "c": "y"})
+		Expect(prediction.Weight()).To(BeNumerically("~", 5.0, 0.001))
+		Expect(prediction.P(0)).To(BeNumerically("~", 0.939, 0.001))
+		Expect(accuracy(subject, stream(rnd, 1000, "a"))).To(BeNumerically("~", 0.956, 0.001))
+	})
+
+	DescribeTable("should adapt to drift",
+		func(method ensemble.Method, expAccuracy float64) {
+			rnd := rand.New(rand.NewSource(1))
+			subject, err := ensemble.New(model, "target", &ensemble.Config{Method: method, Tree: treeConfig})
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, x := range stream(rnd, 3000, "a") {
+				subject.Train(x, 1.0)
+			}
+			Expect(accuracy(subject, stream(rnd, 1000, "b"))).To(BeNumerically("~", 0.5, 0.01))
+
+			for _, x := range stream(rnd, 1000, "b") {
+				subject.Train(x, 1.0)
+			}
+			Expect(accuracy(subject, stream(rnd, 1000, "b"))).To(BeNumerically("~", expAccuracy, 0.001))
+		},
+
+		Entry("OzaBag", ensemble.OzaBag, 0.504),
+		Entry("LeveragingBag", ensemble.LeveragingBag, 0.869),
+	)
+
+	DescribeTable("should dump/load",
+		func(method ensemble.Method) {
+			rnd := rand.New(rand.NewSource(1))
+			config := &ensemble.Config{Method: method, Size: 4, Tree: treeConfig}
+			e1, err := ensemble.New(model, "target", config)
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, x := range stream(rnd, 2000, "a") {
+				e1.Train(x, 1.0)
+			}
+
+			b1 := new(bytes.Buffer)
+			Expect(e1.WriteTo(b1)).To(Equal(int64(b1.Len())))
+
+			e2, err := ensemble.Load(bytes.NewReader(b1.Bytes()), config)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(e2.Size()).To(Equal(4))
+
+			for _, x := range stream(rnd, 100, "a") {
+				Expect(e2.Predict(x)).To(Equal(e1.Predict(x)))
+			}
+
+			b2 := new(bytes.Buffer)
+			Expect(e2.WriteTo(b2)).To(Equal(int64(b2.Len())))
+			Expect(b2.Bytes()).To(Equal(b1.Bytes()))
+		},
+
+		Entry("OzaBag", ensemble.OzaBag),
+		Entry("LeveragingBag", ensemble.LeveragingBag),
+	)
+})
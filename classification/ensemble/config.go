@@ -0,0 +1,62 @@
+package ensemble
+
+import "github.com/bsm/reason/classification/hoeffding"
+
+// Method determines the bagging method.
+type Method int
+
+const (
+	// OzaBag trains each member on a Poisson(1)-weighted copy of every
+	// example, simulating bootstrap sampling on a stream.
+	OzaBag Method = iota
+	// LeveragingBag increases the resampling weight to Poisson(6) and
+	// monitors the error of each member with an ADWIN change detector.
+	// Once a change has been detected, the member with the highest
+	// error is replaced by a new tree.
+	LeveragingBag
+)
+
+// Config configures behaviour
+type Config struct {
+	// The bagging method.
+	// Default: OzaBag
+	Method Method
+
+	// The number of ensemble members.
+	// Default: 10
+	Size int
+
+	// The lambda parameter of the Poisson distribution used to weight
+	// examples for each member.
+	// Default: 1 (OzaBag), 6 (LeveragingBag)
+	Lambda float64
+
+	// The confidence of the ADWIN change detectors, used by
+	// LeveragingBag only.
+	// Default: 0.002
+	DriftConfidence float64
+
+	// The seed of the random number generator.
+	// Default: 0
+	Seed int64
+
+	// The configuration of member trees.
+	Tree hoeffding.Config
+}
+
+// Norm inits and normalizes the config
+func (c *Config) Norm() {
+	if c.Size <= 0 {
+		c.Size = 10
+	}
+	if c.Lambda <= 0 {
+		if c.Method == LeveragingBag {
+			c.Lambda = 6
+		} else {
+			c.Lambda = 1
+		}
+	}
+	if c.DriftConfidence <= 0 {
+		c.DriftConfidence = 0.002
+	}
+}
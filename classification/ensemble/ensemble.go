@@ -0,0 +1,2 @@
+// Package ensemble implements online bagging ensembles of Hoeffding trees.
+package ensemble
@@ -35,6 +35,10 @@ func Load(r io.Reader, config *Config) (*Optimizer, error) {
 
 // New inits a new Optimizer using a model, a target feature and a config.
 func New(model *core.Model, target string, config *Config) (*Optimizer, error) {
+	if err := validateFeatures(model); err != nil {
+		return nil, err
+	}
+
 	predictors, offsets, size := parseFeatures(model.Features, target)
 	opt := internal.NewOptimizer(model, target, size)
 	return newOptimizer(opt, predictors, offsets, config)
@@ -45,10 +49,8 @@ func newOptimizer(opt *internal.Optimizer, predictors []string, offsets []int, c
 	if feat == nil {
 		return nil, fmt.Errorf("ftrl: unknown feature %q", opt.Target)
 	}
-	for _, feat := range opt.Model.Features {
-		if feat.Strategy != core.Feature_VOCABULARY {
-			return nil, fmt.Errorf("ftrl: feature's %q strategy %q is not supported", feat.Name, feat.Strategy.String())
-		}
+	if err := validateFeatures(opt.Model); err != nil {
+		return nil, err
 	}
 
 	var config Config
@@ -66,6 +68,15 @@ func newOptimizer(opt *internal.Optimizer, predictors []string, offsets []int, c
 	}, nil
 }
 
+func validateFeatures(model *core.Model) error {
+	for _, feat := range model.Features {
+		if feat.Strategy != core.Feature_VOCABULARY && !(feat.Strategy == core.Feature_IDENTITY && feat.HashBuckets != 0) {
+			return fmt.Errorf("ftrl: feature's %q strategy %q is not supported", feat.Name, feat.Strategy.String())
+		}
+	}
+	return nil
+}
+
 // Predict performs prediction
 func (o *Optimizer) Predict(x core.Example) float64 {
 	o.mu.RLock()
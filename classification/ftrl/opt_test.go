@@ -45,6 +45,23 @@ var _ = Describe("Optimizer", func() {
 		Expect(t2.Predict(examples[4001])).To(BeNumerically("~", 0.213, 0.001))
 	})
 
+	It("should support bounded identity features", func() {
+		target := core.NewCategoricalFeature("target", []string{"x", "y"})
+
+		_, err := ftrl.New(core.NewModel(core.NewCategoricalFeatureIdentity("id"), target), "target", nil)
+		Expect(err).To(MatchError(`ftrl: feature's "id" strategy "IDENTITY" is not supported`))
+
+		opt, err := ftrl.New(core.NewModel(core.NewCategoricalFeatureIdentityBuckets("id", 4), target), "target", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		for i := 0; i < 100; i++ {
+			opt.Train(core.MapExample{"id": 1, "target": "x"}, 1.0)
+			opt.Train(core.MapExample{"id": 2, "target": "y"}, 1.0)
+		}
+		Expect(opt.Predict(core.MapExample{"id": 1})).To(BeNumerically("<", opt.Predict(core.MapExample{"id": 6})))
+		Expect(opt.Predict(core.MapExample{"id": 2})).To(Equal(opt.Predict(core.MapExample{"id": 6})))
+	})
+
 	DescribeTable("should train & predict",
 		func(n int, exp *testdata.RegressionScore) {
 			opt, model, examples := train(n)
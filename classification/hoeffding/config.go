@@ -5,6 +5,21 @@ import (
 	common "github.com/bsm/reason/common/hoeffding"
 )
 
+// LeafPrediction determines how predictions are made at the leaves.
+type LeafPrediction int
+
+const (
+	// LeafPredictionMajorityClass predicts the distribution of target
+	// categories observed at the leaf.
+	LeafPredictionMajorityClass LeafPrediction = iota
+	// LeafPredictionNaiveBayes applies naive-bayes to the feature stats
+	// observed at the leaf.
+	LeafPredictionNaiveBayes
+	// LeafPredictionNBAdaptive uses naive-bayes predictions only if they have
+	// been more accurate than majority-class predictions at the leaf.
+	LeafPredictionNBAdaptive
+)
+
 // Config configures behaviour
 type Config struct {
 	common.Config
@@ -12,6 +27,29 @@ type Config struct {
 	// The split criterion to use for evaluating splits
 	// Default: classification.DefaultSplitCriterion()
 	SplitCriterion classification.SplitCriterion
+
+	// The prediction strategy at the leaves.
+	// Default: LeafPredictionMajorityClass
+	LeafPrediction LeafPrediction
+
+	// Class weights by target category value. Training examples are weighted
+	// by the class of their target, which affects split decisions as well as
+	// leaf predictions. Classes without an explicit weight default to 1.
+	// Weights are stored with the tree and replaced if set on Load.
+	// Default: nil (unweighted)
+	ClassWeights map[string]float64
+
+	// Enables adaptive behaviour (HAT). Adaptive trees monitor the error
+	// of each split node and grow alternate subtrees once a change has been
+	// detected, replacing the original subtree when the alternate becomes
+	// more accurate.
+	// Default: false
+	Adaptive bool
+
+	// The confidence of the ADWIN error change detectors, used by
+	// adaptive trees only.
+	// Default: 0.002
+	DriftConfidence float64
 }
 
 // Norm inits and normalizes the config
@@ -21,4 +59,7 @@ func (c *Config) Norm() {
 	if c.SplitCriterion == nil {
 		c.SplitCriterion = classification.DefaultSplitCriterion()
 	}
+	if c.DriftConfidence <= 0 {
+		c.DriftConfidence = 0.002
+	}
 }
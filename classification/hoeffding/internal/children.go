@@ -1,6 +1,8 @@
 package internal
 
 import (
+	"sort"
+
 	"github.com/bsm/reason/internal/sparsedense"
 )
 
@@ -23,6 +25,26 @@ func (m *SplitNode_Children) ForEach(iter func(int, int64) bool) {
 	}
 }
 
+// ForEachOrdered iterates over a node-set in index order
+func (m *SplitNode_Children) ForEachOrdered(iter func(int, int64) bool) {
+	if m.Sparse == nil {
+		m.ForEach(iter)
+		return
+	}
+
+	indices := make([]int, 0, len(m.Sparse))
+	for i := range m.Sparse {
+		indices = append(indices, int(i))
+	}
+	sort.Ints(indices)
+
+	for _, i := range indices {
+		if !iter(i, m.Sparse[int64(i)]) {
+			break
+		}
+	}
+}
+
 // Len returns the size
 func (m *SplitNode_Children) Len() int {
 	if m.Dense != nil {
@@ -33,6 +55,21 @@ func (m *SplitNode_Children) Len() int {
 	return len(m.Sparse)
 }
 
+// clone creates a copy of the node-set
+func (m *SplitNode_Children) clone() *SplitNode_Children {
+	nm := &SplitNode_Children{SparseCap: m.SparseCap}
+	if m.Dense != nil {
+		nm.Dense = make([]int64, len(m.Dense))
+		copy(nm.Dense, m.Dense)
+	} else if m.Sparse != nil {
+		nm.Sparse = make(map[int64]int64, len(m.Sparse))
+		for i, nodeRef := range m.Sparse {
+			nm.Sparse[i] = nodeRef
+		}
+	}
+	return nm
+}
+
 // GetRef returns a single nodeRef at index
 func (m *SplitNode_Children) GetRef(index int) int64 {
 	if index < 0 {
@@ -70,6 +107,25 @@ func (m *SplitNode_Children) SetRef(index int, nodeRef int64) {
 	}
 }
 
+// remap replaces all node references, using a lookup table indexed by the
+// original references. Children without a new reference are removed.
+func (m *SplitNode_Children) remap(refs []int64) {
+	if m.Dense != nil {
+		for i, nodeRef := range m.Dense {
+			m.Dense[i] = lookupRef(refs, nodeRef)
+		}
+		return
+	}
+
+	for i, nodeRef := range m.Sparse {
+		if newRef := lookupRef(refs, nodeRef); newRef > 0 {
+			m.Sparse[i] = newRef
+		} else {
+			delete(m.Sparse, i)
+		}
+	}
+}
+
 func (m *SplitNode_Children) setDense(index int, nodeRef int64) {
 	if n := index + 1; n > cap(m.Dense) {
 		dense := make([]int64, n, 2*n)
@@ -91,3 +147,10 @@ func (m *SplitNode_Children) convertToDense() {
 	m.Sparse = nil
 	m.SparseCap = 0
 }
+
+func lookupRef(refs []int64, nodeRef int64) int64 {
+	if nodeRef > 0 && nodeRef < int64(len(refs)) {
+		return refs[nodeRef]
+	}
+	return 0
+}
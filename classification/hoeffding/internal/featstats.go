@@ -1,11 +1,38 @@
 package internal
 
 import (
+	"math"
+	"sort"
+
+	common "github.com/bsm/reason/common/hoeffding"
 	"github.com/bsm/reason/core"
 	"github.com/bsm/reason/internal/hoeffding"
 	"github.com/bsm/reason/util"
+	"github.com/gogo/protobuf/proto"
+)
+
+// Estimated byte sizes of the feature stats structures, excluding the
+// vectors/distributions they contain.
+const (
+	featureStatsByteSize      = 3 * hoeffding.WordByteSize
+	featureStatsEntryByteSize = 4 * hoeffding.WordByteSize
+	numericalByteSize         = 3 * hoeffding.WordByteSize
+	rangeByteSize             = hoeffding.MapEntryByteSize + 2*hoeffding.WordByteSize
+	ebstByteSize              = 3 * hoeffding.WordByteSize
+	ebstNodeByteSize          = 3 * hoeffding.WordByteSize
+	sketchByteSize            = 3 * hoeffding.WordByteSize
+	sketchBinByteSize         = hoeffding.WordByteSize
 )
 
+// featureStatsMapByteSize estimates the byte size of all feature stats.
+func featureStatsMapByteSize(featureStats map[string]*FeatureStats) int {
+	size := 0
+	for _, stats := range featureStats {
+		size += featureStatsEntryByteSize + stats.byteSize()
+	}
+	return size
+}
+
 // FetchCategorical fetches categorical stats.
 func (s *FeatureStats) FetchCategorical() *FeatureStats_Categorical {
 	stats := s.GetCategorical()
@@ -16,71 +43,230 @@ func (s *FeatureStats) FetchCategorical() *FeatureStats_Categorical {
 	return stats
 }
 
-// FetchNumerical fetches numerical stats.
-func (s *FeatureStats) FetchNumerical() *FeatureStats_Numerical {
+// FetchNumerical fetches numerical stats. New stats are initialised
+// using the given observer kind.
+func (s *FeatureStats) FetchNumerical(kind common.NumericObserver) *FeatureStats_Numerical {
 	stats := s.GetNumerical()
 	if stats == nil {
 		stats = new(FeatureStats_Numerical)
+		switch kind {
+		case common.NumericObserverEBST:
+			stats.Ebst = new(FeatureStats_Numerical_EBST)
+		case common.NumericObserverQuantileSketch:
+			stats.Sketch = new(FeatureStats_Numerical_QuantileSketch)
+		}
 		s.Kind = &FeatureStats_Numerical_{Numerical: stats}
 	}
 	return stats
 }
 
+// observe observes the value of a feature and returns the growth of the
+// estimated byte size.
+func (s *FeatureStats) observe(feat *core.Feature, x core.Example, targetCat core.Category, weight float64, kind common.NumericObserver) int {
+	switch feat.Kind {
+	case core.Feature_CATEGORICAL:
+		if cat := feat.Category(x); core.IsCat(cat) {
+			return s.FetchCategorical().Add(cat, targetCat, weight)
+		}
+	case core.Feature_NUMERICAL:
+		if num := feat.Number(x); core.IsNum(num) {
+			return s.FetchNumerical(kind).Add(num, targetCat, weight)
+		}
+	default:
+		return 0
+	}
+
+	s.MissingWeight += weight
+	return 0
+}
+
+// byteSize estimates the byte size of the feature stats.
+func (s *FeatureStats) byteSize() int {
+	size := featureStatsByteSize
+	switch kind := s.Kind.(type) {
+	case *FeatureStats_Categorical_:
+		size += hoeffding.VectorDistributionByteSize(&kind.Categorical.VectorDistribution)
+	case *FeatureStats_Numerical_:
+		size += kind.Numerical.byteSize()
+	}
+	return size
+}
+
+// presentFraction returns the fraction of the total weight that was
+// observed with a feature value.
+func (s *FeatureStats) presentFraction(total float64) float64 {
+	if total <= 0 || s.MissingWeight <= 0 {
+		return 1.0
+	} else if s.MissingWeight >= total {
+		return 0.0
+	}
+	return 1.0 - s.MissingWeight/total
+}
+
+// weight returns the total weight of the observations, including the
+// observations with a missing feature value.
+func (s *FeatureStats) weight() float64 {
+	sum := s.MissingWeight
+	switch kind := s.Kind.(type) {
+	case *FeatureStats_Categorical_:
+		kind.Categorical.ForEach(func(_ int, vv *util.Vector) bool {
+			sum += vv.Weight()
+			return true
+		})
+	case *FeatureStats_Numerical_:
+		kind.Numerical.Stats.ForEach(func(_ int, s *util.StreamStats) bool {
+			sum += s.Weight
+			return true
+		})
+	}
+	return sum
+}
+
 // --------------------------------------------------------------------
 
+// Size implements proto.Sizer. The distribution is embedded, so its own
+// Size must not be promoted.
+func (s *FeatureStats_Categorical) Size() int {
+	n := s.VectorDistribution.Size()
+	return 1 + proto.SizeVarint(uint64(n)) + n
+}
+
+// Marshal implements proto.Marshaler. The distribution is embedded, so its
+// own Marshal must not be promoted.
+func (s *FeatureStats_Categorical) Marshal() ([]byte, error) {
+	b := proto.NewBuffer(make([]byte, 0, s.Size()))
+	if err := b.EncodeVarint(1<<3 | proto.WireBytes); err != nil {
+		return nil, err
+	}
+	if err := b.EncodeMessage(&s.VectorDistribution); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
 // PostSplit calculates a post-split distribution from previous observations.
 func (s *FeatureStats_Categorical) PostSplit() *util.VectorDistribution {
 	return &s.VectorDistribution
 }
 
-// Add adds an observation
-func (s *FeatureStats_Categorical) Add(featCat, targetCat core.Category, weight float64) {
-	s.VectorDistribution.Add(int(featCat), int(targetCat), weight)
+// PostSplitSubset calculates a binary post-split distribution for a
+// category subset.
+func (s *FeatureStats_Categorical) PostSplitSubset(subset []int64) *util.VectorDistribution {
+	res := new(util.VectorDistribution)
+	s.ForEach(func(i int, vv *util.Vector) bool {
+		nodeIndex := 1
+		if hoeffding.SubsetContains(subset, core.Category(i)) {
+			nodeIndex = 0
+		}
+		vv.ForEach(func(j int, w float64) bool {
+			res.Add(nodeIndex, j, w)
+			return true
+		})
+		return true
+	})
+	return res
+}
+
+// Subsets returns candidate subsets for binary splits. Each of the observed
+// categories is split from the rest (one-vs-rest).
+func (s *FeatureStats_Categorical) Subsets() [][]int64 {
+	cats := make([]int, 0, s.Len())
+	s.ForEach(func(i int, _ *util.Vector) bool {
+		cats = append(cats, i)
+		return true
+	})
+	sort.Ints(cats)
+
+	subsets := make([][]int64, 0, len(cats))
+	for _, i := range cats {
+		subsets = append(subsets, []int64{int64(i)})
+	}
+	return subsets
+}
+
+// Add adds an observation and returns the growth of the estimated byte size.
+func (s *FeatureStats_Categorical) Add(featCat, targetCat core.Category, weight float64) int {
+	return hoeffding.AddToVectorDistribution(&s.VectorDistribution, int(featCat), int(targetCat), weight)
+}
+
+// Likelihood returns the (laplace-smoothed) probability of observing
+// a feature category, given a target category.
+func (s *FeatureStats_Categorical) Likelihood(featCat, targetCat core.Category) float64 {
+	var obs, sum float64
+	var num int
+
+	s.ForEach(func(i int, vv *util.Vector) bool {
+		w := vv.Get(int(targetCat))
+		if i == int(featCat) {
+			obs = w
+		}
+		sum += w
+		num++
+		return true
+	})
+	return (obs + 1) / (sum + float64(num))
 }
 
 // --------------------------------------------------------------------
 
-// Add adds an observation
-func (s *FeatureStats_Numerical) Add(featVal float64, targetCat core.Category, weight float64) {
-	targetPos := int(targetCat)
-	if v := s.Min.Get(targetPos); v == 0 || featVal < v {
-		s.Min.Set(targetPos, featVal)
+// Observer returns the numeric observer.
+func (s *FeatureStats_Numerical) Observer() NumericObserver {
+	if s.Ebst != nil {
+		return s.Ebst
+	} else if s.Sketch != nil {
+		return s.Sketch
+	}
+	return (*gaussianObserver)(s)
+}
+
+// Add adds an observation and returns the growth of the estimated byte size.
+func (s *FeatureStats_Numerical) Add(featVal float64, targetCat core.Category, weight float64) int {
+	// gaussian stats are always maintained, they are required for likelihoods
+	growth := (*gaussianObserver)(s).Add(featVal, targetCat, weight)
+
+	if s.Ebst != nil {
+		growth += s.Ebst.Add(featVal, targetCat, weight)
+	} else if s.Sketch != nil {
+		growth += s.Sketch.Add(featVal, targetCat, weight)
+	}
+	return growth
+}
+
+// byteSize estimates the byte size of the numerical stats.
+func (s *FeatureStats_Numerical) byteSize() int {
+	size := numericalByteSize + (*gaussianObserver)(s).byteSize()
+	if s.Ebst != nil {
+		size += s.Ebst.byteSize()
 	}
-	if v := s.Max.Get(targetPos); v == 0 || featVal > v {
-		s.Max.Set(targetPos, featVal)
+	if s.Sketch != nil {
+		size += s.Sketch.byteSize()
 	}
-	s.Stats.Add(targetPos, featVal, weight)
+	return size
+}
+
+// Likelihood returns the probability density of a feature value,
+// given a target category.
+func (s *FeatureStats_Numerical) Likelihood(featVal float64, targetCat core.Category) float64 {
+	stats := s.Stats.Get(int(targetCat))
+	if stats == nil || stats.IsZero() {
+		return 0.0
+	}
+
+	if sd := stats.StdDev(); sd == 0 || math.IsNaN(sd) {
+		if featVal == stats.Mean() {
+			return 1.0
+		}
+		return 0.0
+	}
+	return stats.Prob(featVal)
 }
 
 // PivotPoints determines the optimum split points for the range of values.
 func (s *FeatureStats_Numerical) PivotPoints() []float64 {
-	var tmin, tmax float64
-	s.Min.ForEach(func(i int, min float64) bool {
-		if tmin == 0 || min < tmin {
-			tmin = min
-		}
-		if max := s.Max.Get(i); tmax == 0 || max > tmax {
-			tmax = max
-		}
-		return true
-	})
-	return hoeffding.PivotPoints(tmin, tmax)
+	return s.Observer().PivotPoints()
 }
 
 // PostSplit calculates a post-split distribution from previous observations
 func (s *FeatureStats_Numerical) PostSplit(pivot float64) *util.VectorDistribution {
-	res := new(util.VectorDistribution)
-	s.Stats.ForEach(func(i int, x *util.StreamStats) bool {
-		if min := s.Min.Get(i); min > 0 && pivot < min {
-			res.Add(1, i, x.Weight)
-		} else if max := s.Max.Get(i); max > 0 && pivot >= max {
-			res.Add(0, i, x.Weight)
-		} else {
-			lt, eq, gt := x.Estimate(pivot)
-			res.Add(0, i, lt+eq)
-			res.Add(1, i, gt)
-		}
-		return true
-	})
-	return res
+	return s.Observer().PostSplit(pivot)
 }
@@ -2,6 +2,7 @@ package internal_test
 
 import (
 	"github.com/bsm/reason/classification/hoeffding/internal"
+	"github.com/bsm/reason/util"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -26,11 +27,60 @@ var _ = Describe("FeatureStats_Numerical", func() {
 	})
 
 	It("should add", func() {
-		Expect(subject.Min.Sparse).To(Equal(map[int64]float64{0: 1.3, 1: 3.3, 2: 5.1}))
-		Expect(subject.Max.Sparse).To(Equal(map[int64]float64{0: 1.5, 1: 4.9, 2: 6.3}))
+		Expect(subject.Ranges).To(Equal(map[int64]*internal.FeatureStats_Numerical_Range{
+			0: {Min: 1.3, Max: 1.5},
+			1: {Min: 3.3, Max: 4.9},
+			2: {Min: 5.1, Max: 6.3},
+		}))
 		Expect(subject.Stats.Len()).To(Equal(3))
 	})
 
+	It("should handle zero and negative values", func() {
+		subject = new(internal.FeatureStats_Numerical)
+		subject.Add(-2.0, 0, 1.0)
+		subject.Add(-1.0, 0, 1.0)
+		subject.Add(0.0, 0, 1.0)
+		subject.Add(0.0, 1, 1.0)
+		subject.Add(1.0, 1, 1.0)
+		subject.Add(2.0, 1, 1.0)
+		Expect(subject.Ranges).To(Equal(map[int64]*internal.FeatureStats_Numerical_Range{
+			0: {Min: -2, Max: 0},
+			1: {Min: 0, Max: 2},
+		}))
+
+		pp := subject.PivotPoints()
+		Expect(pp).To(HaveLen(11))
+		Expect(pp[0]).To(BeNumerically("~", -1.67, 0.01))
+		Expect(pp[10]).To(BeNumerically("~", 1.67, 0.01))
+
+		s := subject.PostSplit(-1.5)
+		Expect(s.Get(0).Get(1)).To(Equal(0.0))
+		Expect(s.Get(1).Get(1)).To(Equal(3.0))
+
+		s = subject.PostSplit(0.5)
+		Expect(s.Get(0).Get(0)).To(Equal(3.0))
+		Expect(s.Get(1).Get(0)).To(Equal(0.0))
+	})
+
+	It("should support legacy min/max values", func() {
+		legacy := &internal.FeatureStats_Numerical{
+			Min:   util.Vector{Sparse: map[int64]float64{0: 1.3, 1: 3.3, 2: 5.1}},
+			Max:   util.Vector{Sparse: map[int64]float64{0: 1.5, 1: 4.9, 2: 6.3}},
+			Stats: subject.Stats,
+		}
+		Expect(legacy.PivotPoints()).To(Equal(subject.PivotPoints()))
+		Expect(legacy.PostSplit(4.8)).To(Equal(subject.PostSplit(4.8)))
+
+		legacy.Add(7.0, 2, 1.0)
+		Expect(legacy.Min.Len()).To(Equal(0))
+		Expect(legacy.Max.Len()).To(Equal(0))
+		Expect(legacy.Ranges).To(Equal(map[int64]*internal.FeatureStats_Numerical_Range{
+			0: {Min: 1.3, Max: 1.5},
+			1: {Min: 3.3, Max: 4.9},
+			2: {Min: 5.1, Max: 7.0},
+		}))
+	})
+
 	It("should calculate pivot points", func() {
 		pp := subject.PivotPoints()
 		Expect(pp).To(HaveLen(11))
@@ -42,6 +92,13 @@ var _ = Describe("FeatureStats_Numerical", func() {
 		Expect(new(internal.FeatureStats_Numerical).PivotPoints()).To(BeEmpty())
 	})
 
+	It("should calculate likelihoods", func() {
+		Expect(subject.Likelihood(1.4, 0)).To(BeNumerically("~", 3.989, 0.001))
+		Expect(subject.Likelihood(1.5, 0)).To(BeNumerically("~", 2.420, 0.001))
+		Expect(subject.Likelihood(4.2, 1)).To(BeNumerically("~", 0.637, 0.001))
+		Expect(subject.Likelihood(1.4, 3)).To(Equal(0.0))
+	})
+
 	It("should calculate post-splits", func() {
 		s1 := subject.PostSplit(2.4)
 		Expect(s1.Len()).To(Equal(2))
@@ -83,6 +140,13 @@ var _ = Describe("FeatureStats_Categorical", func() {
 		Expect(subject.Len()).To(Equal(3))
 	})
 
+	It("should calculate likelihoods", func() {
+		Expect(subject.Likelihood(2, 1)).To(BeNumerically("~", 0.375, 0.001))
+		Expect(subject.Likelihood(1, 1)).To(BeNumerically("~", 0.125, 0.001))
+		Expect(subject.Likelihood(1, 0)).To(BeNumerically("~", 0.417, 0.001))
+		Expect(subject.Likelihood(7, 0)).To(BeNumerically("~", 0.083, 0.001))
+	})
+
 	It("should calculate post-splits", func() {
 		s := subject.PostSplit()
 		Expect(s.Len()).To(Equal(3))
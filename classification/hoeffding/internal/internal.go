@@ -1,6 +1,12 @@
 package internal
 
-import "github.com/bsm/reason/util"
+import (
+	"math"
+	"sort"
+
+	"github.com/bsm/reason/internal/hoeffding"
+	"github.com/bsm/reason/util"
+)
 
 // SplitCandidate is a candidate for a split decision
 type SplitCandidate struct {
@@ -8,6 +14,7 @@ type SplitCandidate struct {
 	Merit   float64 // the split merit
 	Range   float64 // the split range
 	Pivot   float64 // the split pivot, for binary splits
+	Subset  []int64 // the category subset, for binary categorical splits
 
 	// Pre-split stats
 	PreSplit *util.Vector
@@ -15,9 +22,55 @@ type SplitCandidate struct {
 	PostSplit *util.VectorDistribution
 }
 
+// MinChildWeight returns the lowest weight any of the children
+// would receive from the split.
+func (c *SplitCandidate) MinChildWeight() float64 {
+	if c.PostSplit == nil {
+		return 0
+	}
+
+	min := math.Inf(1)
+	c.PostSplit.ForEach(func(_ int, vv *util.Vector) bool {
+		if w := vv.Weight(); w < min {
+			min = w
+		}
+		return true
+	})
+	if math.IsInf(min, 1) {
+		return 0
+	}
+	return min
+}
+
 // SplitCandidates are a sortable collection of split candidates
 type SplitCandidates []SplitCandidate
 
 func (p SplitCandidates) Len() int           { return len(p) }
 func (p SplitCandidates) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
 func (p SplitCandidates) Less(i, j int) bool { return p[i].Merit < p[j].Merit }
+
+// Sort sorts candidates by merit, highest first. In deterministic mode,
+// candidates with equal merits are ordered by feature name, which
+// places the null split (if present) first.
+func (p SplitCandidates) Sort(deterministic bool) {
+	if !deterministic {
+		sort.Stable(sort.Reverse(p))
+		return
+	}
+
+	sort.SliceStable(p, func(i, j int) bool {
+		if mi, mj := p[i].Merit, p[j].Merit; hoeffding.MeritLess(mj, mi) {
+			return true
+		} else if hoeffding.MeritLess(mi, mj) {
+			return false
+		}
+		return p[i].Feature < p[j].Feature
+	})
+}
+
+// IsTie returns true if the best two candidates of a sorted collection are
+// tied, i.e. if their merits are equal, ignoring rounding errors. Ties with
+// the null split are not considered.
+func (p SplitCandidates) IsTie() bool {
+	return len(p) > 2 && p[0].Feature != "" && p[1].Feature != "" && !hoeffding.MeritLess(p[1].Merit, p[0].Merit)
+}
@@ -9,6 +9,7 @@ It is generated from these files:
 
 It has these top-level messages:
 	Tree
+	Snapshot
 	FeatureStats
 	Node
 	SplitNode
@@ -44,6 +45,8 @@ type Tree struct {
 	Root int64 `protobuf:"varint,3,opt,name=root,proto3" json:"root,omitempty"`
 	// The node registry.
 	Nodes []*Node `protobuf:"bytes,4,rep,name=nodes" json:"nodes,omitempty"`
+	// The class weights, indexed by target category.
+	ClassWeights []float64 `protobuf:"fixed64,5,rep,packed,name=class_weights,json=classWeights" json:"class_weights,omitempty"`
 }
 
 func (m *Tree) Reset()                    { *m = Tree{} }
@@ -51,6 +54,42 @@ func (m *Tree) String() string            { return proto.CompactTextString(m) }
 func (*Tree) ProtoMessage()               {}
 func (*Tree) Descriptor() ([]byte, []int) { return fileDescriptorInternal, []int{0} }
 
+// Snapshot is a flattened, read-only representation of a tree, used
+// for inference. Nodes are referenced by their index, the root node
+// has index 0.
+type Snapshot struct {
+	// The underlying model.
+	Model *blacksquaremedia_reason_core.Model `protobuf:"bytes,1,opt,name=model" json:"model,omitempty"`
+	// The target feature.
+	Target string `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`
+	// The names of the split features.
+	FeatureNames []string `protobuf:"bytes,3,rep,name=feature_names,json=featureNames" json:"feature_names,omitempty"`
+	// The split feature of each node, as an index of feature_names.
+	// Leaves are marked as -1.
+	Features []int64 `protobuf:"varint,4,rep,packed,name=features" json:"features,omitempty"`
+	// The pivot value of each node (numerical predictors).
+	Pivots []float64 `protobuf:"fixed64,5,rep,packed,name=pivots" json:"pivots,omitempty"`
+	// The default child index of each node, -1 if none.
+	DefaultChildren []int64 `protobuf:"varint,6,rep,packed,name=default_children,json=defaultChildren" json:"default_children,omitempty"`
+	// The offsets of the child indices of each node within children,
+	// followed by the total number of children.
+	ChildOffsets []int64 `protobuf:"varint,7,rep,packed,name=child_offsets,json=childOffsets" json:"child_offsets,omitempty"`
+	// The node indices of all children, -1 if missing.
+	Children []int64 `protobuf:"varint,8,rep,packed,name=children" json:"children,omitempty"`
+	// The offsets of the category subsets of each node within subsets,
+	// followed by the total number of subset categories.
+	SubsetOffsets []int64 `protobuf:"varint,9,rep,packed,name=subset_offsets,json=subsetOffsets" json:"subset_offsets,omitempty"`
+	// The (sorted) category subsets of binary categorical splits.
+	Subsets []int64 `protobuf:"varint,10,rep,packed,name=subsets" json:"subsets,omitempty"`
+	// The prediction stats of each node.
+	Predictions []blacksquaremedia_reason_util.Vector `protobuf:"bytes,11,rep,name=predictions" json:"predictions"`
+}
+
+func (m *Snapshot) Reset()                    { *m = Snapshot{} }
+func (m *Snapshot) String() string            { return proto.CompactTextString(m) }
+func (*Snapshot) ProtoMessage()               {}
+func (*Snapshot) Descriptor() ([]byte, []int) { return fileDescriptorInternal, []int{1} }
+
 // FeatureStats instances maintain stats based on
 // observation of a particular feature.
 type FeatureStats struct {
@@ -58,12 +97,14 @@ type FeatureStats struct {
 	//	*FeatureStats_Numerical_
 	//	*FeatureStats_Categorical_
 	Kind isFeatureStats_Kind `protobuf_oneof:"kind"`
+	// Weight of observations with a missing feature value.
+	MissingWeight float64 `protobuf:"fixed64,3,opt,name=missing_weight,json=missingWeight,proto3" json:"missing_weight,omitempty"`
 }
 
 func (m *FeatureStats) Reset()                    { *m = FeatureStats{} }
 func (m *FeatureStats) String() string            { return proto.CompactTextString(m) }
 func (*FeatureStats) ProtoMessage()               {}
-func (*FeatureStats) Descriptor() ([]byte, []int) { return fileDescriptorInternal, []int{1} }
+func (*FeatureStats) Descriptor() ([]byte, []int) { return fileDescriptorInternal, []int{2} }
 
 type isFeatureStats_Kind interface {
 	isFeatureStats_Kind()
@@ -175,16 +216,98 @@ func _FeatureStats_OneofSizer(msg proto.Message) (n int) {
 }
 
 type FeatureStats_Numerical struct {
+	// Deprecated: legacy min/max values by target category, which
+	// cannot distinguish unset values from zero. Superseded by ranges.
 	Min   blacksquaremedia_reason_util.Vector                  `protobuf:"bytes,1,opt,name=min" json:"min"`
 	Max   blacksquaremedia_reason_util.Vector                  `protobuf:"bytes,2,opt,name=max" json:"max"`
 	Stats blacksquaremedia_reason_util.StreamStatsDistribution `protobuf:"bytes,3,opt,name=stats" json:"stats"`
+	// Observed value ranges by target category.
+	Ranges map[int64]*FeatureStats_Numerical_Range `protobuf:"bytes,6,rep,name=ranges" json:"ranges,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value"`
+	// Exhaustive observations, E-BST observers only.
+	Ebst *FeatureStats_Numerical_EBST `protobuf:"bytes,4,opt,name=ebst" json:"ebst,omitempty"`
+	// Quantile sketch, quantile-sketch observers only.
+	Sketch *FeatureStats_Numerical_QuantileSketch `protobuf:"bytes,5,opt,name=sketch" json:"sketch,omitempty"`
 }
 
 func (m *FeatureStats_Numerical) Reset()         { *m = FeatureStats_Numerical{} }
 func (m *FeatureStats_Numerical) String() string { return proto.CompactTextString(m) }
 func (*FeatureStats_Numerical) ProtoMessage()    {}
 func (*FeatureStats_Numerical) Descriptor() ([]byte, []int) {
-	return fileDescriptorInternal, []int{1, 0}
+	return fileDescriptorInternal, []int{2, 0}
+}
+
+// Range of observed values.
+type FeatureStats_Numerical_Range struct {
+	Min float64 `protobuf:"fixed64,1,opt,name=min,proto3" json:"min,omitempty"`
+	Max float64 `protobuf:"fixed64,2,opt,name=max,proto3" json:"max,omitempty"`
+}
+
+func (m *FeatureStats_Numerical_Range) Reset()         { *m = FeatureStats_Numerical_Range{} }
+func (m *FeatureStats_Numerical_Range) String() string { return proto.CompactTextString(m) }
+func (*FeatureStats_Numerical_Range) ProtoMessage()    {}
+func (*FeatureStats_Numerical_Range) Descriptor() ([]byte, []int) {
+	return fileDescriptorInternal, []int{2, 0, 0}
+}
+
+// EBST is an exhaustive binary search tree of observed values.
+type FeatureStats_Numerical_EBST struct {
+	Nodes []FeatureStats_Numerical_EBST_Node `protobuf:"bytes,1,rep,name=nodes" json:"nodes"`
+}
+
+func (m *FeatureStats_Numerical_EBST) Reset()         { *m = FeatureStats_Numerical_EBST{} }
+func (m *FeatureStats_Numerical_EBST) String() string { return proto.CompactTextString(m) }
+func (*FeatureStats_Numerical_EBST) ProtoMessage()    {}
+func (*FeatureStats_Numerical_EBST) Descriptor() ([]byte, []int) {
+	return fileDescriptorInternal, []int{2, 0, 2}
+}
+
+type FeatureStats_Numerical_EBST_Node struct {
+	// The observed value.
+	Value float64 `protobuf:"fixed64,1,opt,name=value,proto3" json:"value,omitempty"`
+	// Weights by target category of values <= value.
+	Lte blacksquaremedia_reason_util.Vector `protobuf:"bytes,2,opt,name=lte" json:"lte"`
+	// Weights by target category of values > value.
+	Gt blacksquaremedia_reason_util.Vector `protobuf:"bytes,3,opt,name=gt" json:"gt"`
+	// References to the child nodes (1-based, 0 if none).
+	Left  int64 `protobuf:"varint,4,opt,name=left,proto3" json:"left,omitempty"`
+	Right int64 `protobuf:"varint,5,opt,name=right,proto3" json:"right,omitempty"`
+}
+
+func (m *FeatureStats_Numerical_EBST_Node) Reset()         { *m = FeatureStats_Numerical_EBST_Node{} }
+func (m *FeatureStats_Numerical_EBST_Node) String() string { return proto.CompactTextString(m) }
+func (*FeatureStats_Numerical_EBST_Node) ProtoMessage()    {}
+func (*FeatureStats_Numerical_EBST_Node) Descriptor() ([]byte, []int) {
+	return fileDescriptorInternal, []int{2, 0, 2, 0}
+}
+
+// QuantileSketch is a streaming histogram of observed values.
+type FeatureStats_Numerical_QuantileSketch struct {
+	Bins []FeatureStats_Numerical_QuantileSketch_Bin `protobuf:"bytes,1,rep,name=bins" json:"bins"`
+}
+
+func (m *FeatureStats_Numerical_QuantileSketch) Reset()         { *m = FeatureStats_Numerical_QuantileSketch{} }
+func (m *FeatureStats_Numerical_QuantileSketch) String() string { return proto.CompactTextString(m) }
+func (*FeatureStats_Numerical_QuantileSketch) ProtoMessage()    {}
+func (*FeatureStats_Numerical_QuantileSketch) Descriptor() ([]byte, []int) {
+	return fileDescriptorInternal, []int{2, 0, 3}
+}
+
+type FeatureStats_Numerical_QuantileSketch_Bin struct {
+	// The (centroid) value of the bin.
+	Value float64 `protobuf:"fixed64,1,opt,name=value,proto3" json:"value,omitempty"`
+	// Weights by target category.
+	Weights blacksquaremedia_reason_util.Vector `protobuf:"bytes,2,opt,name=weights" json:"weights"`
+}
+
+func (m *FeatureStats_Numerical_QuantileSketch_Bin) Reset() {
+	*m = FeatureStats_Numerical_QuantileSketch_Bin{}
+}
+func (m *FeatureStats_Numerical_QuantileSketch_Bin) String() string {
+	return proto.CompactTextString(m)
+}
+func (*FeatureStats_Numerical_QuantileSketch_Bin) ProtoMessage() {}
+func (*FeatureStats_Numerical_QuantileSketch_Bin) Descriptor() ([]byte, []int) {
+	return fileDescriptorInternal, []int{2, 0, 3, 0}
 }
 
 type FeatureStats_Categorical struct {
@@ -195,7 +318,7 @@ func (m *FeatureStats_Categorical) Reset()         { *m = FeatureStats_Categoric
 func (m *FeatureStats_Categorical) String() string { return proto.CompactTextString(m) }
 func (*FeatureStats_Categorical) ProtoMessage()    {}
 func (*FeatureStats_Categorical) Descriptor() ([]byte, []int) {
-	return fileDescriptorInternal, []int{1, 1}
+	return fileDescriptorInternal, []int{2, 1}
 }
 
 // Node is a tree node
@@ -208,12 +331,14 @@ type Node struct {
 	//	*Node_Leaf
 	//	*Node_Split
 	Kind isNode_Kind `protobuf_oneof:"kind"`
+	// Error change detector, maintained by adaptive trees only.
+	ErrorDetector *blacksquaremedia_reason_util.ADWIN `protobuf:"bytes,4,opt,name=error_detector,json=errorDetector" json:"error_detector,omitempty"`
 }
 
 func (m *Node) Reset()                    { *m = Node{} }
 func (m *Node) String() string            { return proto.CompactTextString(m) }
 func (*Node) ProtoMessage()               {}
-func (*Node) Descriptor() ([]byte, []int) { return fileDescriptorInternal, []int{2} }
+func (*Node) Descriptor() ([]byte, []int) { return fileDescriptorInternal, []int{3} }
 
 type isNode_Kind interface {
 	isNode_Kind()
@@ -330,14 +455,32 @@ type SplitNode struct {
 	Feature string `protobuf:"bytes,1,opt,name=feature,proto3" json:"feature,omitempty"`
 	// The pivot value for binary splits (numerical predictors).
 	Pivot float64 `protobuf:"fixed64,2,opt,name=pivot,proto3" json:"pivot,omitempty"`
+	// The (sorted) category subset for binary splits (categorical predictors).
+	// Examples with a category in the subset are routed to the first child,
+	// all others to the second.
+	Subset []int64 `protobuf:"varint,7,rep,packed,name=subset" json:"subset,omitempty"`
 	// The child references.
 	Children SplitNode_Children `protobuf:"bytes,3,opt,name=children" json:"children"`
+	// Reference to an alternate subtree, grown by adaptive
+	// trees once a change in error has been detected.
+	Alternate int64 `protobuf:"varint,4,opt,name=alternate,proto3" json:"alternate,omitempty"`
+	// Observation stats, by feature, maintained in EFDT mode only.
+	FeatureStats map[string]*FeatureStats `protobuf:"bytes,5,rep,name=feature_stats,json=featureStats" json:"feature_stats,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value"`
+	// Weight at the time of the last split re-evaluation.
+	WeightAtLastEval float64 `protobuf:"fixed64,6,opt,name=weight_at_last_eval,json=weightAtLastEval,proto3" json:"weight_at_last_eval,omitempty"`
+	// The default child for examples with a missing feature value,
+	// stored as child index + 1 (0 if none).
+	DefaultChild int64 `protobuf:"varint,8,opt,name=default_child,json=defaultChild,proto3" json:"default_child,omitempty"`
+	// Weight of examples routed to each child, by child index.
+	ChildWeights blacksquaremedia_reason_util.Vector `protobuf:"bytes,9,opt,name=child_weights,json=childWeights" json:"child_weights"`
+	// Weight of examples with a missing feature value.
+	MissingWeight float64 `protobuf:"fixed64,10,opt,name=missing_weight,json=missingWeight,proto3" json:"missing_weight,omitempty"`
 }
 
 func (m *SplitNode) Reset()                    { *m = SplitNode{} }
 func (m *SplitNode) String() string            { return proto.CompactTextString(m) }
 func (*SplitNode) ProtoMessage()               {}
-func (*SplitNode) Descriptor() ([]byte, []int) { return fileDescriptorInternal, []int{3} }
+func (*SplitNode) Descriptor() ([]byte, []int) { return fileDescriptorInternal, []int{4} }
 
 // Children is a collection of child node references.
 type SplitNode_Children struct {
@@ -349,7 +492,7 @@ type SplitNode_Children struct {
 func (m *SplitNode_Children) Reset()                    { *m = SplitNode_Children{} }
 func (m *SplitNode_Children) String() string            { return proto.CompactTextString(m) }
 func (*SplitNode_Children) ProtoMessage()               {}
-func (*SplitNode_Children) Descriptor() ([]byte, []int) { return fileDescriptorInternal, []int{3, 0} }
+func (*SplitNode_Children) Descriptor() ([]byte, []int) { return fileDescriptorInternal, []int{4, 0} }
 
 // LeafNode instances are the leaves within the tree.
 type LeafNode struct {
@@ -359,17 +502,32 @@ type LeafNode struct {
 	WeightAtLastEval float64 `protobuf:"fixed64,2,opt,name=weight_at_last_eval,json=weightAtLastEval,proto3" json:"weight_at_last_eval,omitempty"`
 	// Status indicator.
 	IsDisabled bool `protobuf:"varint,3,opt,name=is_disabled,json=isDisabled,proto3" json:"is_disabled,omitempty"`
+	// Weight of correct majority-class predictions, used by
+	// adaptive leaf predictions.
+	McCorrectWeight float64 `protobuf:"fixed64,4,opt,name=mc_correct_weight,json=mcCorrectWeight,proto3" json:"mc_correct_weight,omitempty"`
+	// Weight of correct naive-bayes predictions, used by
+	// adaptive leaf predictions.
+	NbCorrectWeight float64 `protobuf:"fixed64,5,opt,name=nb_correct_weight,json=nbCorrectWeight,proto3" json:"nb_correct_weight,omitempty"`
+	// Features that are no longer observed, because their merit was
+	// found to be confidently below the best candidate.
+	IgnoredFeatures []string `protobuf:"bytes,6,rep,name=ignored_features,json=ignoredFeatures" json:"ignored_features,omitempty"`
 }
 
 func (m *LeafNode) Reset()                    { *m = LeafNode{} }
 func (m *LeafNode) String() string            { return proto.CompactTextString(m) }
 func (*LeafNode) ProtoMessage()               {}
-func (*LeafNode) Descriptor() ([]byte, []int) { return fileDescriptorInternal, []int{4} }
+func (*LeafNode) Descriptor() ([]byte, []int) { return fileDescriptorInternal, []int{5} }
 
 func init() {
 	proto.RegisterType((*Tree)(nil), "blacksquaremedia.reason.classification.hoeffding.Tree")
+	proto.RegisterType((*Snapshot)(nil), "blacksquaremedia.reason.classification.hoeffding.Snapshot")
 	proto.RegisterType((*FeatureStats)(nil), "blacksquaremedia.reason.classification.hoeffding.FeatureStats")
 	proto.RegisterType((*FeatureStats_Numerical)(nil), "blacksquaremedia.reason.classification.hoeffding.FeatureStats.Numerical")
+	proto.RegisterType((*FeatureStats_Numerical_Range)(nil), "blacksquaremedia.reason.classification.hoeffding.FeatureStats.Numerical.Range")
+	proto.RegisterType((*FeatureStats_Numerical_EBST)(nil), "blacksquaremedia.reason.classification.hoeffding.FeatureStats.Numerical.EBST")
+	proto.RegisterType((*FeatureStats_Numerical_EBST_Node)(nil), "blacksquaremedia.reason.classification.hoeffding.FeatureStats.Numerical.EBST.Node")
+	proto.RegisterType((*FeatureStats_Numerical_QuantileSketch)(nil), "blacksquaremedia.reason.classification.hoeffding.FeatureStats.Numerical.QuantileSketch")
+	proto.RegisterType((*FeatureStats_Numerical_QuantileSketch_Bin)(nil), "blacksquaremedia.reason.classification.hoeffding.FeatureStats.Numerical.QuantileSketch.Bin")
 	proto.RegisterType((*FeatureStats_Categorical)(nil), "blacksquaremedia.reason.classification.hoeffding.FeatureStats.Categorical")
 	proto.RegisterType((*Node)(nil), "blacksquaremedia.reason.classification.hoeffding.Node")
 	proto.RegisterType((*SplitNode)(nil), "blacksquaremedia.reason.classification.hoeffding.SplitNode")
@@ -382,55 +540,89 @@ func init() {
 }
 
 var fileDescriptorInternal = []byte{
-	// 785 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x95, 0xdf, 0x8a, 0x23, 0x45,
-	0x14, 0xc6, 0xd3, 0xe9, 0xce, 0x98, 0x9c, 0x5e, 0x71, 0x2d, 0x45, 0x42, 0xc0, 0x99, 0x21, 0x2a,
-	0x04, 0x61, 0x3b, 0x43, 0x44, 0x71, 0x47, 0x11, 0xcc, 0xec, 0x4a, 0x90, 0xb8, 0xc4, 0xca, 0xe2,
-	0x85, 0x37, 0xa1, 0xd2, 0x5d, 0x9d, 0x14, 0xd3, 0xdd, 0x95, 0xad, 0xaa, 0x8e, 0xbb, 0x57, 0xde,
-	0x7b, 0xe5, 0x93, 0xf8, 0x02, 0xbe, 0xc0, 0x5e, 0x89, 0x97, 0xe2, 0xc5, 0xc2, 0xa2, 0x2f, 0xe0,
-	0x1b, 0x48, 0xfd, 0xe9, 0x4c, 0x07, 0x19, 0x99, 0x38, 0xde, 0x34, 0x75, 0xba, 0xeb, 0xfc, 0xce,
-	0x77, 0xbe, 0xaa, 0xae, 0x82, 0xb3, 0x38, 0x23, 0x52, 0xb2, 0x94, 0xc5, 0x44, 0x31, 0x5e, 0x0c,
-	0xd7, 0x9c, 0xa6, 0x69, 0xc2, 0x8a, 0xd5, 0x90, 0x15, 0x8a, 0x8a, 0x82, 0x64, 0xbb, 0x41, 0xb4,
-	0x11, 0x5c, 0x71, 0x74, 0xb6, 0xcc, 0x48, 0x7c, 0x29, 0x9f, 0x94, 0x44, 0xd0, 0x9c, 0x26, 0x8c,
-	0x44, 0x82, 0x12, 0xc9, 0x8b, 0x68, 0x9f, 0x14, 0xed, 0x48, 0xbd, 0xf7, 0x56, 0x4c, 0xad, 0xcb,
-	0x65, 0x14, 0xf3, 0x7c, 0xb8, 0x94, 0xf9, 0xd0, 0xce, 0x1f, 0xc6, 0x5c, 0x50, 0xf3, 0xb0, 0xe0,
-	0xeb, 0xa6, 0x95, 0x8a, 0x65, 0xe6, 0xe1, 0xa6, 0xdd, 0xab, 0x4d, 0x5b, 0xf1, 0x15, 0x1f, 0x9a,
-	0xd7, 0xcb, 0x32, 0x35, 0x91, 0x09, 0xcc, 0xc8, 0x4e, 0xef, 0xff, 0xec, 0x41, 0xf0, 0x58, 0x50,
-	0x8a, 0xee, 0x43, 0x2b, 0xe7, 0x09, 0xcd, 0xba, 0xde, 0xa9, 0x37, 0x08, 0x47, 0xef, 0x44, 0xd7,
-	0xf6, 0xa1, 0x25, 0x7d, 0xa5, 0xa7, 0x62, 0x9b, 0x81, 0xde, 0x82, 0x23, 0x45, 0xc4, 0x8a, 0xaa,
-	0x6e, 0xf3, 0xd4, 0x1b, 0x74, 0xb0, 0x8b, 0x10, 0x82, 0x40, 0x70, 0xae, 0xba, 0xfe, 0xa9, 0x37,
-	0xf0, 0xb1, 0x19, 0xa3, 0x29, 0xb4, 0x0a, 0x9e, 0x50, 0xd9, 0x0d, 0x4e, 0xfd, 0x41, 0x38, 0xfa,
-	0x28, 0x3a, 0xd4, 0xae, 0xe8, 0x11, 0x4f, 0x28, 0xb6, 0x90, 0xfe, 0x4f, 0x01, 0xdc, 0xf9, 0x82,
-	0x12, 0x55, 0x0a, 0x3a, 0x57, 0x44, 0x49, 0xb4, 0x86, 0x4e, 0x51, 0xe6, 0x54, 0xb0, 0x98, 0x54,
-	0x9d, 0x4c, 0x0e, 0x2f, 0x51, 0x47, 0x46, 0x8f, 0x2a, 0xde, 0xa4, 0x81, 0xaf, 0xe0, 0xa8, 0x80,
-	0x30, 0x26, 0x8a, 0xae, 0xb8, 0xad, 0xd5, 0x34, 0xb5, 0xbe, 0xbc, 0x65, 0xad, 0x8b, 0x2b, 0xe2,
-	0xa4, 0x81, 0xeb, 0x05, 0x7a, 0xbf, 0x7b, 0xd0, 0xd9, 0x49, 0x41, 0x9f, 0x82, 0x9f, 0xb3, 0xc2,
-	0x75, 0xf8, 0xee, 0xb5, 0x55, 0xcd, 0xbe, 0xf8, 0x86, 0xc6, 0x8a, 0x8b, 0x71, 0xf0, 0xfc, 0xc5,
-	0x49, 0x03, 0xeb, 0x34, 0x93, 0x4d, 0x9e, 0x3a, 0xcd, 0x87, 0x65, 0x93, 0xa7, 0xe8, 0x6b, 0x68,
-	0x49, 0xad, 0xd6, 0xac, 0x6b, 0x38, 0xfa, 0xf0, 0xdf, 0xf3, 0xe7, 0x4a, 0x50, 0x92, 0x9b, 0xf6,
-	0x1e, 0x30, 0xa9, 0x04, 0x5b, 0x96, 0xda, 0x01, 0x07, 0xb4, 0xa4, 0xde, 0x02, 0xc2, 0x5a, 0xeb,
-	0x68, 0x56, 0x55, 0xb0, 0xfd, 0x9d, 0xdd, 0x44, 0xe1, 0x1e, 0xbc, 0xad, 0xe1, 0xbf, 0xbe, 0x38,
-	0xf1, 0x5c, 0x81, 0xf1, 0x11, 0x04, 0x97, 0xac, 0x48, 0xfa, 0x7f, 0x79, 0x10, 0xe8, 0x0d, 0x84,
-	0xce, 0xf7, 0x4b, 0xdc, 0xc8, 0x04, 0x07, 0x43, 0x33, 0x08, 0x32, 0x4a, 0x52, 0xe7, 0xdf, 0xf9,
-	0xe1, 0x6b, 0x3e, 0xa5, 0x24, 0xd5, 0x2a, 0x26, 0x0d, 0x6c, 0x48, 0x68, 0x0e, 0x2d, 0xb9, 0xc9,
-	0x98, 0x72, 0x96, 0x7e, 0x72, 0x38, 0x72, 0xae, 0xd3, 0x1d, 0xd3, 0xb2, 0x76, 0x3d, 0xff, 0xe0,
-	0x43, 0x67, 0xf7, 0x19, 0x75, 0xe1, 0x95, 0xd4, 0x6e, 0x39, 0xd3, 0x7a, 0x07, 0x57, 0x21, 0x7a,
-	0x13, 0x5a, 0x1b, 0xb6, 0xe5, 0xf6, 0x2f, 0xf6, 0xb0, 0x0d, 0x50, 0x0a, 0xed, 0x78, 0xcd, 0xb2,
-	0x44, 0xd0, 0xc2, 0xa9, 0x7b, 0x70, 0x0b, 0x75, 0xd1, 0x85, 0x63, 0xb9, 0xf5, 0xdf, 0xb1, 0x7b,
-	0x7f, 0x7a, 0xd0, 0xae, 0x3e, 0x6a, 0x29, 0x09, 0x2d, 0xa4, 0x96, 0xe8, 0x0f, 0x7c, 0x6c, 0x03,
-	0xb4, 0x86, 0x23, 0xb9, 0x21, 0x42, 0xd2, 0x6e, 0xd3, 0x1c, 0x1e, 0xb3, 0xff, 0x43, 0x48, 0x34,
-	0x37, 0xc8, 0x87, 0x85, 0x12, 0xcf, 0xb0, 0xe3, 0xa3, 0xb7, 0x01, 0xec, 0x68, 0x11, 0x93, 0x8d,
-	0x3b, 0xbf, 0x3a, 0xf6, 0xcd, 0x05, 0xd9, 0xf4, 0xee, 0x43, 0x58, 0xcb, 0x42, 0x77, 0xc1, 0xbf,
-	0xa4, 0xcf, 0x8c, 0x9d, 0x3e, 0xd6, 0x43, 0xad, 0x7f, 0x4b, 0xb2, 0x92, 0x1a, 0x2b, 0x7d, 0x6c,
-	0x83, 0xf3, 0xe6, 0xc7, 0x5e, 0xff, 0x97, 0x26, 0xb4, 0xab, 0xe5, 0x47, 0x4f, 0xe0, 0x55, 0x67,
-	0xfe, 0xa2, 0xda, 0x8c, 0xba, 0xaf, 0xe9, 0x7f, 0xdf, 0x51, 0x7b, 0xc7, 0x89, 0xed, 0xe9, 0x4e,
-	0x5a, 0x3f, 0x20, 0xef, 0xc1, 0x1b, 0xdf, 0x51, 0xb6, 0x5a, 0xab, 0x05, 0x51, 0x8b, 0x8c, 0x48,
-	0xb5, 0xa0, 0x5b, 0x77, 0x7c, 0x79, 0xf8, 0xae, 0xfd, 0xf4, 0xb9, 0x9a, 0x12, 0xa9, 0x1e, 0x6e,
-	0x49, 0x86, 0x4e, 0x20, 0x64, 0x72, 0x91, 0x30, 0x49, 0x96, 0x19, 0x4d, 0x8c, 0x13, 0x6d, 0x0c,
-	0x4c, 0xff, 0xca, 0xe6, 0x4d, 0xef, 0x7b, 0x78, 0xfd, 0x1f, 0x25, 0xeb, 0x86, 0x74, 0xac, 0x21,
-	0x8f, 0xeb, 0x86, 0x84, 0xa3, 0xcf, 0x6e, 0x77, 0x4e, 0xd6, 0x0c, 0x1d, 0xcf, 0x9f, 0xbf, 0x3c,
-	0x6e, 0xfc, 0xf6, 0xf2, 0xd8, 0xfb, 0xf1, 0x8f, 0xe3, 0x06, 0xbc, 0x1f, 0xf3, 0xfc, 0x86, 0xec,
-	0xf1, 0x6b, 0x93, 0x0a, 0x3e, 0xd3, 0x57, 0xa1, 0xfc, 0xb6, 0x5d, 0x5d, 0xe5, 0xcb, 0x23, 0x73,
-	0x39, 0x7e, 0xf0, 0x77, 0x00, 0x00, 0x00, 0xff, 0xff, 0x94, 0xf4, 0x01, 0xcc, 0xff, 0x07, 0x00,
-	0x00,
+	// 1344 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x58, 0x41, 0x6f, 0x1b, 0xd5,
+	0x13, 0xcf, 0x7a, 0x6d, 0xc7, 0x1e, 0x27, 0x4d, 0xfa, 0xfe, 0xd5, 0x5f, 0x2b, 0x0b, 0x5a, 0x2b,
+	0xa5, 0x52, 0x5a, 0x54, 0xa7, 0x0a, 0x02, 0xd1, 0x80, 0x90, 0xea, 0xa4, 0x28, 0x54, 0x69, 0x9a,
+	0xae, 0x23, 0x2a, 0xc1, 0x61, 0xf5, 0xbc, 0xfb, 0xd6, 0x7e, 0xca, 0x7a, 0xd7, 0x7d, 0xef, 0x39,
+	0xb4, 0x27, 0xc4, 0x91, 0x1b, 0x5f, 0x85, 0x23, 0x07, 0x24, 0x8e, 0x3d, 0xc2, 0x8d, 0x53, 0x45,
+	0x05, 0x27, 0xbe, 0x01, 0x17, 0x84, 0xde, 0xbc, 0xb7, 0xf6, 0xba, 0x34, 0x50, 0xa7, 0xa9, 0xb8,
+	0x44, 0x6f, 0x26, 0x33, 0xbf, 0x99, 0x37, 0x33, 0x6f, 0x66, 0xd6, 0x70, 0x23, 0x4c, 0xa8, 0x94,
+	0x3c, 0xe6, 0x21, 0x55, 0x3c, 0x4b, 0x37, 0x06, 0x19, 0x8b, 0xe3, 0x88, 0xa7, 0xfd, 0x0d, 0x9e,
+	0x2a, 0x26, 0x52, 0x9a, 0x4c, 0x0e, 0xed, 0x91, 0xc8, 0x54, 0x46, 0x6e, 0xf4, 0x12, 0x1a, 0x1e,
+	0xc9, 0x87, 0x63, 0x2a, 0xd8, 0x90, 0x45, 0x9c, 0xb6, 0x05, 0xa3, 0x32, 0x4b, 0xdb, 0xb3, 0x48,
+	0xed, 0x09, 0x52, 0xf3, 0x4a, 0x9f, 0xab, 0xc1, 0xb8, 0xd7, 0x0e, 0xb3, 0xe1, 0x46, 0x4f, 0x0e,
+	0x37, 0x8c, 0xfc, 0x46, 0x98, 0x09, 0x86, 0x7f, 0x0c, 0xf0, 0x49, 0x62, 0x63, 0xc5, 0x13, 0xfc,
+	0x63, 0xc5, 0xae, 0x17, 0xc4, 0xfa, 0x59, 0x3f, 0xdb, 0x40, 0x76, 0x6f, 0x1c, 0x23, 0x85, 0x04,
+	0x9e, 0x8c, 0xf8, 0xda, 0x2f, 0x0e, 0x94, 0x0f, 0x05, 0x63, 0xe4, 0x26, 0x54, 0x86, 0x59, 0xc4,
+	0x12, 0xcf, 0x69, 0x39, 0xeb, 0x8d, 0xcd, 0xcb, 0xed, 0x13, 0xef, 0xa1, 0x5d, 0xba, 0xab, 0x45,
+	0x7d, 0xa3, 0x41, 0xfe, 0x0f, 0x55, 0x45, 0x45, 0x9f, 0x29, 0xaf, 0xd4, 0x72, 0xd6, 0xeb, 0xbe,
+	0xa5, 0x08, 0x81, 0xb2, 0xc8, 0x32, 0xe5, 0xb9, 0x2d, 0x67, 0xdd, 0xf5, 0xf1, 0x4c, 0xf6, 0xa0,
+	0x92, 0x66, 0x11, 0x93, 0x5e, 0xb9, 0xe5, 0xae, 0x37, 0x36, 0xdf, 0x6b, 0xcf, 0x1b, 0xae, 0xf6,
+	0x7e, 0x16, 0x31, 0xdf, 0x80, 0x90, 0xcb, 0xb0, 0x8c, 0x72, 0xc1, 0x17, 0x8c, 0xf7, 0x07, 0x4a,
+	0x7a, 0x95, 0x96, 0xbb, 0xee, 0xf8, 0x4b, 0xc8, 0x7c, 0x60, 0x78, 0x6b, 0xdf, 0xba, 0x50, 0xeb,
+	0xa6, 0x74, 0x24, 0x07, 0x99, 0x7a, 0x1d, 0xd7, 0xbc, 0x0c, 0xcb, 0x31, 0xa3, 0x6a, 0x2c, 0x58,
+	0x90, 0xd2, 0x21, 0x93, 0x9e, 0xdb, 0x72, 0xd7, 0xeb, 0xfe, 0x92, 0x65, 0xee, 0x6b, 0x1e, 0x69,
+	0x42, 0xcd, 0xd2, 0xe6, 0xea, 0xae, 0x3f, 0xa1, 0x35, 0xf0, 0x88, 0x1f, 0x67, 0x13, 0xf7, 0x2d,
+	0x45, 0xae, 0xc2, 0x6a, 0xc4, 0x62, 0x3a, 0x4e, 0x54, 0x10, 0x0e, 0x78, 0x12, 0x09, 0x96, 0x7a,
+	0x55, 0xd4, 0x5d, 0xb1, 0xfc, 0x6d, 0xcb, 0xc6, 0x40, 0xe8, 0x73, 0x90, 0xc5, 0xb1, 0x64, 0x4a,
+	0x7a, 0x8b, 0x28, 0xb7, 0x84, 0xcc, 0x7b, 0x86, 0xa7, 0x7d, 0x98, 0xe0, 0xd4, 0x8c, 0x0f, 0x39,
+	0x4d, 0xae, 0xc0, 0x39, 0x39, 0xee, 0x49, 0xa6, 0x26, 0x08, 0x75, 0x94, 0x58, 0x36, 0xdc, 0x1c,
+	0xc2, 0x83, 0x45, 0xc3, 0x90, 0x1e, 0xe0, 0xff, 0x73, 0x92, 0xec, 0x41, 0x63, 0x24, 0x58, 0xc4,
+	0x43, 0x9d, 0x2e, 0xe9, 0x35, 0x30, 0xbd, 0x6f, 0x9d, 0x18, 0x5e, 0xac, 0xd8, 0x4f, 0x59, 0xa8,
+	0x32, 0xd1, 0x29, 0x3f, 0x79, 0x7a, 0x69, 0xc1, 0x2f, 0xaa, 0xaf, 0xfd, 0xb1, 0x04, 0x4b, 0x1f,
+	0x9b, 0xf8, 0x74, 0x15, 0x55, 0x92, 0x0c, 0xa0, 0x9e, 0x8e, 0x87, 0x4c, 0xf0, 0x90, 0xe6, 0xb9,
+	0xdb, 0x9d, 0xbf, 0x76, 0x8a, 0x90, 0xed, 0xfd, 0x1c, 0x6f, 0x77, 0xc1, 0x9f, 0x82, 0x93, 0x14,
+	0x1a, 0x21, 0x55, 0xac, 0x9f, 0x19, 0x5b, 0x25, 0xb4, 0x75, 0xe7, 0x15, 0x6d, 0x6d, 0x4f, 0x11,
+	0x77, 0x17, 0xfc, 0xa2, 0x01, 0x1d, 0xf9, 0x21, 0x97, 0x92, 0xa7, 0x7d, 0x5b, 0xc5, 0xf8, 0x5e,
+	0x1c, 0x7f, 0xd9, 0x72, 0x4d, 0x19, 0x37, 0xff, 0xac, 0x43, 0x7d, 0xe2, 0x31, 0xf9, 0x10, 0xdc,
+	0x21, 0x4f, 0x6d, 0x20, 0xe6, 0x89, 0xb2, 0x56, 0x43, 0x6d, 0xfa, 0xc8, 0x2b, 0x9d, 0x42, 0x9b,
+	0x3e, 0x22, 0xf7, 0xa1, 0x22, 0xf5, 0xa5, 0xd0, 0xcf, 0xc6, 0xe6, 0xbb, 0xff, 0xac, 0xdf, 0x55,
+	0x82, 0xd1, 0x21, 0x46, 0x61, 0x87, 0x4b, 0x25, 0x78, 0x6f, 0xac, 0x03, 0x65, 0x01, 0x0d, 0x12,
+	0x49, 0xa0, 0x2a, 0x68, 0xda, 0x67, 0x12, 0xeb, 0xbb, 0xb1, 0x79, 0x78, 0x56, 0xa9, 0x6d, 0xfb,
+	0x08, 0x7b, 0x3b, 0x55, 0xe2, 0xb1, 0x6f, 0x6d, 0x10, 0x0a, 0x65, 0xd6, 0x93, 0xca, 0x2b, 0xa3,
+	0xff, 0x77, 0xcf, 0xcc, 0xd6, 0xed, 0x4e, 0xf7, 0xd0, 0x47, 0x68, 0x92, 0x41, 0x55, 0x1e, 0x31,
+	0x15, 0x0e, 0xbc, 0x0a, 0x1a, 0x79, 0x70, 0x66, 0x46, 0xee, 0x8f, 0x69, 0xaa, 0x78, 0xc2, 0xba,
+	0x08, 0xef, 0x5b, 0x33, 0xcd, 0xb7, 0xa1, 0x82, 0x57, 0x25, 0xab, 0xd3, 0xca, 0x70, 0x4c, 0xb6,
+	0x57, 0xa7, 0xd9, 0x76, 0x30, 0x83, 0xcd, 0xaf, 0x1d, 0x68, 0x14, 0x02, 0xa3, 0x25, 0x8e, 0xd8,
+	0x63, 0xd4, 0x71, 0x7d, 0x7d, 0x24, 0x11, 0x54, 0x8e, 0x69, 0x32, 0x66, 0xb6, 0x46, 0xf6, 0xcf,
+	0x36, 0x1f, 0xbe, 0x01, 0xdf, 0x2a, 0xbd, 0xef, 0x34, 0xbf, 0x2b, 0x41, 0x59, 0x07, 0x8e, 0xa4,
+	0xf9, 0x64, 0x70, 0xb0, 0x04, 0xfc, 0x33, 0x4d, 0x0b, 0x4e, 0x8d, 0xbc, 0xe6, 0xd0, 0x4c, 0xf3,
+	0x07, 0x07, 0xca, 0x9a, 0x4b, 0x2e, 0xe4, 0x77, 0x35, 0x31, 0x33, 0x84, 0x7e, 0x23, 0x89, 0x62,
+	0xa7, 0x79, 0x23, 0x89, 0x62, 0x64, 0x0b, 0x4a, 0x7d, 0xe5, 0xb9, 0x73, 0x2b, 0x97, 0xfa, 0x38,
+	0x36, 0x13, 0x16, 0x9b, 0xf2, 0x74, 0x7d, 0x3c, 0x6b, 0x1f, 0x05, 0xf6, 0x86, 0x0a, 0x32, 0x0d,
+	0xd1, 0xfc, 0xdd, 0x81, 0x73, 0xb3, 0xf5, 0x40, 0xc6, 0x50, 0xee, 0xf1, 0x34, 0x0f, 0xe2, 0xe7,
+	0xaf, 0xa9, 0xec, 0xda, 0x1d, 0x9e, 0xbf, 0x60, 0x34, 0xd7, 0xa4, 0xe0, 0x76, 0x78, 0x7a, 0x42,
+	0x28, 0x77, 0x60, 0x31, 0x9f, 0xcf, 0xf3, 0x87, 0x33, 0x57, 0x6d, 0x06, 0xd0, 0x28, 0x74, 0x51,
+	0x72, 0x90, 0x77, 0x21, 0xd3, 0x03, 0x6f, 0xbc, 0x0c, 0xe4, 0x4c, 0x03, 0xaa, 0x69, 0xf8, 0x1f,
+	0x9f, 0x5e, 0x72, 0x6c, 0x13, 0xea, 0x54, 0xa1, 0x7c, 0xc4, 0xd3, 0x68, 0xed, 0xfb, 0x92, 0x2d,
+	0x8c, 0xad, 0x59, 0x13, 0x2f, 0xe5, 0x75, 0xde, 0xd1, 0x0e, 0x74, 0x12, 0x69, 0x6c, 0x2f, 0xbc,
+	0x35, 0x7f, 0x1e, 0xf6, 0x18, 0x8d, 0xb5, 0x17, 0xbb, 0x0b, 0x3e, 0x22, 0x91, 0x2e, 0x54, 0xe4,
+	0x28, 0xe1, 0x79, 0x55, 0x7d, 0x30, 0x3f, 0x64, 0x57, 0xab, 0x5b, 0x4c, 0x83, 0x45, 0xee, 0xc0,
+	0x39, 0x26, 0x44, 0x26, 0x82, 0x88, 0x29, 0xf4, 0xdf, 0x2b, 0xff, 0xcb, 0x5e, 0x84, 0x77, 0xbd,
+	0xb5, 0xf3, 0xe0, 0x93, 0x7d, 0x7f, 0x19, 0x55, 0x77, 0xac, 0xe6, 0x24, 0x7e, 0x5f, 0x2d, 0x42,
+	0x7d, 0x62, 0x4a, 0x6f, 0x0c, 0x76, 0xd1, 0xc1, 0x30, 0xd6, 0xfd, 0x9c, 0xd4, 0xc5, 0x82, 0x8b,
+	0x8e, 0xed, 0x4c, 0x86, 0xd0, 0xcb, 0x90, 0x59, 0x29, 0xec, 0x0a, 0x63, 0x29, 0x12, 0x17, 0x96,
+	0x17, 0x13, 0x81, 0x9d, 0x57, 0x88, 0x40, 0x3b, 0xdf, 0x9c, 0x6c, 0x95, 0x4d, 0x17, 0xa1, 0x37,
+	0xa0, 0x4e, 0x13, 0xdc, 0xe8, 0x15, 0xb3, 0x4f, 0x70, 0xca, 0x20, 0x62, 0xba, 0xeb, 0x99, 0xd2,
+	0xa8, 0xb4, 0xdc, 0xd3, 0xcd, 0x90, 0xa9, 0x2b, 0xc5, 0x17, 0x67, 0x06, 0xd5, 0x52, 0x5c, 0x60,
+	0x91, 0xeb, 0xf0, 0x3f, 0xf3, 0x06, 0x02, 0xaa, 0x82, 0x84, 0x4a, 0x15, 0xb0, 0x63, 0x9a, 0x78,
+	0x55, 0x8c, 0xda, 0xaa, 0xf9, 0xd7, 0x2d, 0xb5, 0x47, 0xa5, 0xba, 0x7d, 0x4c, 0x13, 0xbd, 0x0a,
+	0xce, 0x6c, 0x8d, 0x5e, 0x0d, 0x2f, 0xb1, 0x54, 0x5c, 0x19, 0xc9, 0xbd, 0x7c, 0x5f, 0xcc, 0x1f,
+	0x66, 0x7d, 0xee, 0x87, 0x69, 0x76, 0x4b, 0xbb, 0x64, 0xbf, 0x60, 0x8b, 0x81, 0x17, 0x6d, 0x31,
+	0xbf, 0x39, 0x50, 0x9b, 0x2c, 0xad, 0x17, 0xa0, 0x12, 0xb1, 0x54, 0x32, 0x6c, 0x56, 0xae, 0x6f,
+	0x08, 0x32, 0x80, 0xaa, 0x1c, 0x51, 0x21, 0x75, 0xef, 0xd5, 0xb1, 0x3d, 0x38, 0x8b, 0x34, 0xb7,
+	0xbb, 0x08, 0x69, 0xf7, 0x00, 0x83, 0x4f, 0xde, 0x04, 0x30, 0xa7, 0x20, 0xa4, 0x23, 0xfb, 0x95,
+	0x52, 0x37, 0x9c, 0x6d, 0x3a, 0x6a, 0xde, 0x84, 0x46, 0x41, 0xeb, 0x05, 0x43, 0xf2, 0x42, 0x71,
+	0x48, 0xba, 0xc5, 0xa1, 0xf6, 0x25, 0x9c, 0xff, 0x5b, 0x56, 0x8b, 0x00, 0x75, 0x03, 0x70, 0x38,
+	0x3b, 0x65, 0x3f, 0x7a, 0xb5, 0x6e, 0x5d, 0x70, 0x60, 0xed, 0x27, 0x17, 0x6a, 0x79, 0x07, 0x21,
+	0x0f, 0x9f, 0x2f, 0x5a, 0x33, 0x1c, 0xf6, 0x4e, 0xdf, 0x94, 0x4e, 0x5b, 0xb3, 0xa5, 0x13, 0x6a,
+	0xf6, 0x12, 0x34, 0xb8, 0x0c, 0x22, 0x2e, 0x69, 0x2f, 0x61, 0x11, 0xa6, 0xa2, 0xe6, 0x03, 0x97,
+	0x3b, 0x96, 0x43, 0xae, 0xc1, 0xf9, 0x61, 0x18, 0x84, 0x99, 0x10, 0x2c, 0x54, 0x79, 0x85, 0x95,
+	0x11, 0x6d, 0x65, 0x18, 0x6e, 0x1b, 0xbe, 0xa9, 0x31, 0x2d, 0x9b, 0xf6, 0x9e, 0x97, 0xad, 0x18,
+	0xd9, 0xb4, 0x37, 0x2b, 0x7b, 0x15, 0x56, 0x79, 0x3f, 0xcd, 0x04, 0x8b, 0x82, 0xc9, 0xe7, 0x59,
+	0x15, 0x3f, 0xdf, 0x56, 0x2c, 0xdf, 0xde, 0x54, 0xfe, 0xe7, 0x39, 0xed, 0x74, 0x9f, 0x3c, 0xbb,
+	0xb8, 0xf0, 0xf3, 0xb3, 0x8b, 0xce, 0x37, 0xbf, 0x5e, 0x5c, 0x80, 0x6b, 0x61, 0x36, 0x7c, 0x49,
+	0xec, 0xce, 0xca, 0x6e, 0x0e, 0x7e, 0xa0, 0x3f, 0xfa, 0xe5, 0x67, 0xb5, 0xfc, 0x47, 0x8b, 0x5e,
+	0x15, 0x7f, 0x06, 0x78, 0xe7, 0xaf, 0x01, 0x00, 0x23, 0x81, 0xf1, 0xe6, 0xe9, 0x10, 0x00, 0x00,
 }
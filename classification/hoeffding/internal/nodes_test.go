@@ -3,6 +3,8 @@ package internal_test
 import (
 	"github.com/bsm/reason/classification"
 	"github.com/bsm/reason/classification/hoeffding/internal"
+	common "github.com/bsm/reason/common/hoeffding"
+	"github.com/bsm/reason/core"
 	"github.com/bsm/reason/testdata"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -21,7 +23,7 @@ var _ = Describe("LeafNode", func() {
 
 		target := model.Feature("play")
 		for _, x := range examples {
-			subject.Observe(model, target, x, 1.0, wrapper)
+			subject.Observe(model, target, x, 1.0, common.NumericObserverGaussian, wrapper)
 		}
 	})
 
@@ -38,15 +40,98 @@ var _ = Describe("LeafNode", func() {
 
 	It("should evaluate splits", func() {
 		crit := classification.DefaultSplitCriterion()
-		Expect(subject.EvaluateSplit("unknown", crit, wrapper)).To(BeNil())
+		Expect(subject.EvaluateSplit("unknown", crit, false, wrapper)).To(BeNil())
 
-		cat := subject.EvaluateSplit("outlook", crit, wrapper)
+		cat := subject.EvaluateSplit("outlook", crit, false, wrapper)
 		Expect(cat.Feature).To(Equal("outlook"))
 		Expect(cat.Merit).To(BeNumerically("~", 0.247, 0.001))
 		Expect(cat.Range).To(Equal(1.0))
 		Expect(cat.Pivot).To(Equal(0.0))
 		Expect(cat.PreSplit.Weight()).To(Equal(14.0))
 		Expect(cat.PostSplit.Len()).To(Equal(3))
+
+		bin := subject.EvaluateSplit("outlook", crit, true, wrapper)
+		Expect(bin.Feature).To(Equal("outlook"))
+		Expect(bin.Merit).To(BeNumerically("~", 0.226, 0.001))
+		Expect(bin.Subset).To(Equal([]int64{1}))
+		Expect(bin.PostSplit.Len()).To(Equal(2))
+		Expect(bin.PostSplit.Get(0).Sparse).To(Equal(map[int64]float64{0: 4}))
+		Expect(bin.PostSplit.Get(1).Sparse).To(Equal(map[int64]float64{0: 5, 1: 5}))
+	})
+
+	It("should evaluate splits concurrently", func() {
+		crit := classification.DefaultSplitCriterion()
+		sequential := subject.EvaluateSplits(crit, true, 1, wrapper)
+		Expect(sequential).To(HaveLen(4))
+
+		for _, workers := range []int{2, 3, 8} {
+			Expect(subject.EvaluateSplits(crit, true, workers, wrapper)).To(ConsistOf(sequential))
+		}
+
+		subject.IsDisabled = true
+		Expect(subject.EvaluateSplits(crit, true, 2, wrapper)).To(BeEmpty())
+	})
+
+	It("should account for missing values", func() {
+		crit := classification.DefaultSplitCriterion()
+		subject.FeatureStats["outlook"].MissingWeight = 7
+
+		cat := subject.EvaluateSplit("outlook", crit, false, wrapper)
+		Expect(cat.Merit).To(BeNumerically("~", 0.123, 0.001))
+
+		subject.Observe(model, model.Feature("play"), core.MapExample{"play": "yes"}, 1.0, common.NumericObserverGaussian, wrapper)
+		Expect(subject.FeatureStats["outlook"].MissingWeight).To(Equal(8.0))
+		Expect(subject.FeatureStats["windy"].MissingWeight).To(Equal(1.0))
+	})
+
+	It("should predict using naive-bayes", func() {
+		x := core.MapExample{"outlook": "sunny", "temp": "cool", "humidity": "high", "windy": "true"}
+		nb := subject.PredictNaiveBayes(model, x, wrapper)
+		Expect(nb).NotTo(BeNil())
+		Expect(nb.Weight()).To(BeNumerically("~", 14.0, 0.001))
+		Expect(nb.Get(0)).To(BeNumerically("~", 5.722, 0.001))
+		Expect(nb.Get(1)).To(BeNumerically("~", 8.278, 0.001))
+
+		subject.Disable()
+		Expect(subject.PredictNaiveBayes(model, x, wrapper)).To(BeNil())
+	})
+
+	It("should track predictions", func() {
+		target := model.Feature("play")
+		Expect(subject.PrefersNaiveBayes()).To(BeTrue())
+
+		x := core.MapExample{"outlook": "sunny", "temp": "cool", "humidity": "high", "windy": "true", "play": "no"}
+		subject.TrackPredictions(model, target, x, 1.0, wrapper)
+		Expect(subject.McCorrectWeight).To(Equal(0.0))
+		Expect(subject.NbCorrectWeight).To(Equal(1.0))
+
+		x = core.MapExample{"outlook": "overcast", "temp": "mild", "humidity": "normal", "windy": "false", "play": "yes"}
+		subject.TrackPredictions(model, target, x, 2.0, wrapper)
+		Expect(subject.McCorrectWeight).To(Equal(2.0))
+		Expect(subject.NbCorrectWeight).To(Equal(3.0))
+		Expect(subject.PrefersNaiveBayes()).To(BeTrue())
+	})
+
+	It("should estimate promise and byte size", func() {
+		Expect(wrapper.Promise()).To(Equal(5.0))
+
+		size := wrapper.ByteSize()
+		Expect(size).To(BeNumerically(">", 100))
+		subject.Disable()
+		Expect(wrapper.ByteSize()).To(BeNumerically("<", size/4))
+	})
+
+	It("should ignore features", func() {
+		subject.IgnoreFeature("outlook")
+		subject.IgnoreFeature("outlook")
+		Expect(subject.IgnoredFeatures).To(Equal([]string{"outlook"}))
+		Expect(subject.FeatureStats).To(HaveLen(3))
+		Expect(subject.FeatureStats).NotTo(HaveKey("outlook"))
+
+		subject.Observe(model, model.Feature("play"), core.MapExample{"outlook": "sunny", "temp": "cool", "play": "yes"}, 1.0, common.NumericObserverGaussian, wrapper)
+		Expect(wrapper.Weight()).To(Equal(15.0))
+		Expect(subject.FeatureStats).To(HaveLen(3))
+		Expect(subject.FeatureStats).NotTo(HaveKey("outlook"))
 	})
 
 	It("should allow to disable/enable", func() {
@@ -0,0 +1,326 @@
+package internal
+
+import (
+	"math"
+	"sort"
+
+	"github.com/bsm/reason/core"
+	"github.com/bsm/reason/internal/hoeffding"
+	"github.com/bsm/reason/util"
+)
+
+// NumericObserver instances observe the values of a numerical feature
+// and propose split pivots. Observers are serialized with the feature
+// stats and selected via common.NumericObserver.
+type NumericObserver interface {
+	// Add adds an observation and returns the growth of the estimated
+	// byte size.
+	Add(featVal float64, targetCat core.Category, weight float64) int
+	// PivotPoints returns the candidate split points.
+	PivotPoints() []float64
+	// PostSplit calculates a post-split distribution for a pivot.
+	PostSplit(pivot float64) *util.VectorDistribution
+}
+
+// --------------------------------------------------------------------
+
+// gaussianObserver uses the ranges/stats of the numerical feature stats,
+// evaluates equally spaced pivots and estimates post-split weights.
+type gaussianObserver FeatureStats_Numerical
+
+// Add implements NumericObserver.
+func (o *gaussianObserver) Add(featVal float64, targetCat core.Category, weight float64) int {
+	before := o.byteSize()
+	o.migrate()
+
+	targetPos := int64(targetCat)
+	if r, ok := o.Ranges[targetPos]; !ok {
+		if o.Ranges == nil {
+			o.Ranges = make(map[int64]*FeatureStats_Numerical_Range)
+		}
+		o.Ranges[targetPos] = &FeatureStats_Numerical_Range{Min: featVal, Max: featVal}
+	} else if featVal < r.Min {
+		r.Min = featVal
+	} else if featVal > r.Max {
+		r.Max = featVal
+	}
+	o.Stats.Add(int(targetCat), featVal, weight)
+	return o.byteSize() - before
+}
+
+// PivotPoints implements NumericObserver.
+func (o *gaussianObserver) PivotPoints() []float64 {
+	var tmin, tmax float64
+	var found bool
+	o.Stats.ForEach(func(i int, _ *util.StreamStats) bool {
+		min, max, ok := o.rangeOf(i)
+		if !ok {
+			return true
+		}
+		if !found || min < tmin {
+			tmin = min
+		}
+		if !found || max > tmax {
+			tmax = max
+		}
+		found = true
+		return true
+	})
+	return hoeffding.PivotPoints(tmin, tmax)
+}
+
+// PostSplit implements NumericObserver.
+func (o *gaussianObserver) PostSplit(pivot float64) *util.VectorDistribution {
+	res := new(util.VectorDistribution)
+	o.Stats.ForEach(func(i int, x *util.StreamStats) bool {
+		min, max, ok := o.rangeOf(i)
+		if ok && pivot < min {
+			res.Add(1, i, x.Weight)
+		} else if ok && pivot >= max {
+			res.Add(0, i, x.Weight)
+		} else {
+			lt, eq, gt := x.Estimate(pivot)
+			res.Add(0, i, lt+eq)
+			res.Add(1, i, gt)
+		}
+		return true
+	})
+	return res
+}
+
+// rangeOf returns the range of values observed for a target category.
+// It falls back on legacy min/max values for stats that were created before
+// ranges were introduced.
+func (o *gaussianObserver) rangeOf(targetPos int) (min, max float64, ok bool) {
+	if r, ok := o.Ranges[int64(targetPos)]; ok {
+		return r.Min, r.Max, true
+	}
+	if len(o.Ranges) == 0 {
+		if x := o.Stats.Get(targetPos); x != nil && !x.IsZero() {
+			return o.Min.Get(targetPos), o.Max.Get(targetPos), true
+		}
+	}
+	return 0, 0, false
+}
+
+// byteSize estimates the byte size of the ranges/stats.
+func (o *gaussianObserver) byteSize() int {
+	return hoeffding.VectorByteSize(&o.Min) + hoeffding.VectorByteSize(&o.Max) +
+		hoeffding.StreamStatsDistributionByteSize(&o.Stats) + len(o.Ranges)*rangeByteSize
+}
+
+// migrate converts legacy min/max values to ranges.
+func (o *gaussianObserver) migrate() {
+	if len(o.Ranges) != 0 || o.Stats.Len() == 0 {
+		return
+	}
+
+	ranges := make(map[int64]*FeatureStats_Numerical_Range)
+	o.Stats.ForEach(func(i int, _ *util.StreamStats) bool {
+		if min, max, ok := o.rangeOf(i); ok {
+			ranges[int64(i)] = &FeatureStats_Numerical_Range{Min: min, Max: max}
+		}
+		return true
+	})
+	o.Ranges = ranges
+	o.Min = util.Vector{}
+	o.Max = util.Vector{}
+}
+
+// --------------------------------------------------------------------
+
+// Add implements NumericObserver.
+func (o *FeatureStats_Numerical_EBST) Add(featVal float64, targetCat core.Category, weight float64) int {
+	targetPos := int(targetCat)
+
+	ref := int64(0)
+	if len(o.Nodes) != 0 {
+		ref = 1
+	}
+
+	growth := 0
+	for ref != 0 {
+		node := &o.Nodes[ref-1]
+		if featVal == node.Value {
+			return growth + hoeffding.AddToVector(&node.Lte, targetPos, weight)
+		} else if featVal < node.Value {
+			growth += hoeffding.AddToVector(&node.Lte, targetPos, weight)
+			if node.Left == 0 {
+				childRef := o.add(featVal, targetPos, weight)
+				o.Nodes[ref-1].Left = childRef
+				return growth + o.Nodes[childRef-1].byteSize()
+			}
+			ref = node.Left
+		} else {
+			growth += hoeffding.AddToVector(&node.Gt, targetPos, weight)
+			if node.Right == 0 {
+				childRef := o.add(featVal, targetPos, weight)
+				o.Nodes[ref-1].Right = childRef
+				return growth + o.Nodes[childRef-1].byteSize()
+			}
+			ref = node.Right
+		}
+	}
+	return o.Nodes[o.add(featVal, targetPos, weight)-1].byteSize()
+}
+
+// PivotPoints implements NumericObserver. It returns the mid-points
+// between all adjacent observed values.
+func (o *FeatureStats_Numerical_EBST) PivotPoints() []float64 {
+	values := make([]float64, 0, len(o.Nodes))
+	for _, node := range o.Nodes {
+		values = append(values, node.Value)
+	}
+	sort.Float64s(values)
+	return hoeffding.MidPivotPoints(values)
+}
+
+// PostSplit implements NumericObserver.
+func (o *FeatureStats_Numerical_EBST) PostSplit(pivot float64) *util.VectorDistribution {
+	res := new(util.VectorDistribution)
+	if len(o.Nodes) == 0 {
+		return res
+	}
+
+	// accumulate weights of values <= pivot
+	lte := new(util.Vector)
+	for ref := int64(1); ref != 0; {
+		node := &o.Nodes[ref-1]
+		if pivot < node.Value {
+			ref = node.Left
+			continue
+		}
+
+		node.Lte.ForEach(func(i int, w float64) bool {
+			lte.Add(i, w)
+			return true
+		})
+		ref = node.Right
+	}
+
+	// calculate weights of values > pivot from the totals
+	root := &o.Nodes[0]
+	total := root.Lte.Clone()
+	root.Gt.ForEach(func(i int, w float64) bool {
+		total.Add(i, w)
+		return true
+	})
+	total.ForEach(func(i int, w float64) bool {
+		if lw := lte.Get(i); lw > 0 {
+			res.Add(0, i, lw)
+		}
+		if gw := w - lte.Get(i); gw > 0 {
+			res.Add(1, i, gw)
+		}
+		return true
+	})
+	return res
+}
+
+// byteSize estimates the byte size of the tree.
+func (o *FeatureStats_Numerical_EBST) byteSize() int {
+	size := ebstByteSize
+	for i := range o.Nodes {
+		size += o.Nodes[i].byteSize()
+	}
+	return size
+}
+
+func (o *FeatureStats_Numerical_EBST) add(featVal float64, targetPos int, weight float64) int64 {
+	node := FeatureStats_Numerical_EBST_Node{Value: featVal}
+	node.Lte.Add(targetPos, weight)
+	o.Nodes = append(o.Nodes, node)
+	return int64(len(o.Nodes))
+}
+
+func (n *FeatureStats_Numerical_EBST_Node) byteSize() int {
+	return ebstNodeByteSize + hoeffding.VectorByteSize(&n.Lte) + hoeffding.VectorByteSize(&n.Gt)
+}
+
+// --------------------------------------------------------------------
+
+const sketchMaxBins = 64
+
+// Add implements NumericObserver.
+func (o *FeatureStats_Numerical_QuantileSketch) Add(featVal float64, targetCat core.Category, weight float64) int {
+	targetPos := int(targetCat)
+
+	// find position, add to an existing bin if the value is already known
+	pos := sort.Search(len(o.Bins), func(i int) bool { return o.Bins[i].Value >= featVal })
+	if pos < len(o.Bins) && o.Bins[pos].Value == featVal {
+		return hoeffding.AddToVector(&o.Bins[pos].Weights, targetPos, weight)
+	}
+	before := o.byteSize()
+
+	// insert new bin
+	o.Bins = append(o.Bins, FeatureStats_Numerical_QuantileSketch_Bin{})
+	copy(o.Bins[pos+1:], o.Bins[pos:])
+	o.Bins[pos] = FeatureStats_Numerical_QuantileSketch_Bin{Value: featVal}
+	o.Bins[pos].Weights.Add(targetPos, weight)
+
+	if len(o.Bins) > sketchMaxBins {
+		o.compress()
+	}
+	return o.byteSize() - before
+}
+
+// PivotPoints implements NumericObserver. It returns the boundaries
+// between the bins that are closest to the data quantiles.
+func (o *FeatureStats_Numerical_QuantileSketch) PivotPoints() []float64 {
+	values := make([]float64, 0, len(o.Bins))
+	weights := make([]float64, 0, len(o.Bins))
+	for _, bin := range o.Bins {
+		values = append(values, bin.Value)
+		weights = append(weights, bin.Weights.Weight())
+	}
+	return hoeffding.QuantilePivotPoints(values, weights)
+}
+
+// PostSplit implements NumericObserver.
+func (o *FeatureStats_Numerical_QuantileSketch) PostSplit(pivot float64) *util.VectorDistribution {
+	res := new(util.VectorDistribution)
+	for _, bin := range o.Bins {
+		nodeIndex := 0
+		if bin.Value > pivot {
+			nodeIndex = 1
+		}
+		bin.Weights.ForEach(func(i int, w float64) bool {
+			res.Add(nodeIndex, i, w)
+			return true
+		})
+	}
+	return res
+}
+
+// byteSize estimates the byte size of the sketch.
+func (o *FeatureStats_Numerical_QuantileSketch) byteSize() int {
+	size := sketchByteSize
+	for i := range o.Bins {
+		size += sketchBinByteSize + hoeffding.VectorByteSize(&o.Bins[i].Weights)
+	}
+	return size
+}
+
+// compress merges the two closest adjacent bins.
+func (o *FeatureStats_Numerical_QuantileSketch) compress() {
+	pos, min := -1, math.Inf(1)
+	for i := 1; i < len(o.Bins); i++ {
+		if d := o.Bins[i].Value - o.Bins[i-1].Value; d < min {
+			pos, min = i-1, d
+		}
+	}
+	if pos < 0 {
+		return
+	}
+
+	b1, b2 := &o.Bins[pos], &o.Bins[pos+1]
+	w1, w2 := b1.Weights.Weight(), b2.Weights.Weight()
+	if sum := w1 + w2; sum > 0 {
+		b1.Value = (b1.Value*w1 + b2.Value*w2) / sum
+	}
+	b2.Weights.ForEach(func(i int, w float64) bool {
+		b1.Weights.Add(i, w)
+		return true
+	})
+	o.Bins = append(o.Bins[:pos+1], o.Bins[pos+2:]...)
+}
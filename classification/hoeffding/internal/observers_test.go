@@ -0,0 +1,125 @@
+package internal_test
+
+import (
+	"github.com/bsm/reason/classification/hoeffding/internal"
+	"github.com/gogo/protobuf/proto"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FeatureStats_Numerical_EBST", func() {
+	var subject *internal.FeatureStats_Numerical_EBST
+
+	BeforeEach(func() {
+		subject = new(internal.FeatureStats_Numerical_EBST)
+		subject.Add(1.4, 0, 1.0)
+		subject.Add(1.3, 0, 1.0)
+		subject.Add(1.5, 0, 1.0)
+		subject.Add(4.1, 1, 1.0)
+		subject.Add(3.7, 1, 1.0)
+		subject.Add(4.9, 1, 1.0)
+		subject.Add(4.0, 1, 1.0)
+		subject.Add(3.3, 1, 1.0)
+		subject.Add(6.3, 2, 1.0)
+		subject.Add(5.8, 2, 1.0)
+		subject.Add(5.1, 2, 1.0)
+		subject.Add(5.3, 2, 1.0)
+		subject.Add(1.4, 0, 1.0)
+	})
+
+	It("should add", func() {
+		Expect(subject.Nodes).To(HaveLen(12))
+		Expect(subject.Nodes[0].Value).To(Equal(1.4))
+		Expect(subject.Nodes[0].Lte.Sparse).To(Equal(map[int64]float64{0: 3}))
+		Expect(subject.Nodes[0].Gt.Sparse).To(Equal(map[int64]float64{0: 1, 1: 5, 2: 4}))
+	})
+
+	It("should calculate pivot points", func() {
+		pp := subject.PivotPoints()
+		Expect(pp).To(HaveLen(11))
+		Expect(pp[0]).To(BeNumerically("~", 1.35, 0.001))
+		Expect(pp[2]).To(BeNumerically("~", 2.40, 0.001))
+		Expect(pp[10]).To(BeNumerically("~", 6.05, 0.001))
+
+		Expect(new(internal.FeatureStats_Numerical_EBST).PivotPoints()).To(BeEmpty())
+	})
+
+	It("should calculate post-splits", func() {
+		s1 := subject.PostSplit(2.4)
+		Expect(s1.Len()).To(Equal(2))
+		Expect(s1.Get(0).Sparse).To(Equal(map[int64]float64{0: 4}))
+		Expect(s1.Get(1).Sparse).To(Equal(map[int64]float64{1: 5, 2: 4}))
+
+		s2 := subject.PostSplit(4.8)
+		Expect(s2.Len()).To(Equal(2))
+		Expect(s2.Get(0).Sparse).To(Equal(map[int64]float64{0: 4, 1: 4}))
+		Expect(s2.Get(1).Sparse).To(Equal(map[int64]float64{1: 1, 2: 4}))
+	})
+
+	It("should marshal", func() {
+		data, err := proto.Marshal(subject)
+		Expect(err).NotTo(HaveOccurred())
+
+		restored := new(internal.FeatureStats_Numerical_EBST)
+		Expect(proto.Unmarshal(data, restored)).To(Succeed())
+		Expect(restored.PostSplit(4.8)).To(Equal(subject.PostSplit(4.8)))
+	})
+})
+
+var _ = Describe("FeatureStats_Numerical_QuantileSketch", func() {
+	var subject *internal.FeatureStats_Numerical_QuantileSketch
+
+	BeforeEach(func() {
+		subject = new(internal.FeatureStats_Numerical_QuantileSketch)
+		for i := 0; i < 1000; i++ {
+			v := float64(i * i)
+			if i < 800 {
+				subject.Add(v, 0, 1.0)
+			} else {
+				subject.Add(v, 1, 1.0)
+			}
+		}
+	})
+
+	It("should add", func() {
+		Expect(subject.Bins).To(HaveLen(64))
+
+		sum := 0.0
+		for i, bin := range subject.Bins {
+			if i > 0 {
+				Expect(bin.Value).To(BeNumerically(">", subject.Bins[i-1].Value))
+			}
+			sum += bin.Weights.Weight()
+		}
+		Expect(sum).To(Equal(1000.0))
+	})
+
+	It("should calculate pivot points", func() {
+		pp := subject.PivotPoints()
+		Expect(len(pp)).To(BeNumerically(">", 8))
+		Expect(len(pp)).To(BeNumerically("<=", 11))
+
+		// pivots are placed at quantiles, not equally spaced
+		Expect(pp[0]).To(BeNumerically("<", 20000))
+		Expect(pp[len(pp)-1]).To(BeNumerically(">", 700000))
+
+		Expect(new(internal.FeatureStats_Numerical_QuantileSketch).PivotPoints()).To(BeEmpty())
+	})
+
+	It("should calculate post-splits", func() {
+		s := subject.PostSplit(640000)
+		Expect(s.Len()).To(Equal(2))
+		Expect(s.Get(0).Get(0)).To(BeNumerically("~", 800, 20))
+		Expect(s.Get(1).Get(1)).To(BeNumerically("~", 200, 20))
+		Expect(s.Get(0).Weight() + s.Get(1).Weight()).To(Equal(1000.0))
+	})
+
+	It("should marshal", func() {
+		data, err := proto.Marshal(subject)
+		Expect(err).NotTo(HaveOccurred())
+
+		restored := new(internal.FeatureStats_Numerical_QuantileSketch)
+		Expect(proto.Unmarshal(data, restored)).To(Succeed())
+		Expect(restored.PivotPoints()).To(Equal(subject.PivotPoints()))
+	})
+})
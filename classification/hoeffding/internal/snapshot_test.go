@@ -0,0 +1,78 @@
+package internal_test
+
+import (
+	"bytes"
+
+	"github.com/bsm/reason/classification/hoeffding/internal"
+	"github.com/bsm/reason/core"
+	"github.com/bsm/reason/testdata"
+	"github.com/bsm/reason/util"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Snapshot", func() {
+	var tree *internal.Tree
+	var features []*core.Feature
+
+	model := testdata.ClassificationModel()
+	pre := &util.Vector{Sparse: map[int64]float64{0: 9.0, 1: 5.0}}
+	post := &util.VectorDistribution{
+		Sparse: map[int64]*util.Vector{
+			0: &util.Vector{Sparse: map[int64]float64{0: 2, 1: 3}},
+			1: &util.Vector{Sparse: map[int64]float64{0: 4}},
+			2: &util.Vector{Sparse: map[int64]float64{0: 3, 1: 2}},
+		},
+	}
+
+	BeforeEach(func() {
+		tree = internal.NewTree(model, "play")
+		tree.Split(1, "outlook", pre, post, 0)
+		features = []*core.Feature{model.Feature("outlook")}
+	})
+
+	It("should flatten trees", func() {
+		subject := tree.Snapshot()
+		Expect(subject.FeatureNames).To(Equal([]string{"outlook"}))
+		Expect(subject.Features).To(Equal([]int64{0, -1, -1, -1}))
+		Expect(subject.DefaultChildren).To(Equal([]int64{0, -1, -1, -1}))
+		Expect(subject.ChildOffsets).To(Equal([]int64{0, 3, 3, 3, 3}))
+		Expect(subject.Children).To(Equal([]int64{1, 2, 3}))
+		Expect(subject.SubsetOffsets).To(Equal([]int64{0, 0, 0, 0, 0}))
+		Expect(subject.Predictions).To(HaveLen(4))
+		Expect(subject.Prediction(2).Weight()).To(Equal(4.0))
+	})
+
+	It("should traverse", func() {
+		subject := tree.Snapshot()
+		Expect(subject.Traverse(features, core.MapExample{"outlook": "overcast"})).To(Equal(2))
+		Expect(subject.Traverse(features, core.MapExample{"outlook": "sunny"})).To(Equal(3))
+		Expect(subject.Traverse(features, core.MapExample{})).To(Equal(1))
+
+		subject.DefaultChildren[0] = -1
+		Expect(subject.Traverse(features, core.MapExample{})).To(Equal(0))
+
+		subject.Children[1] = -1
+		Expect(subject.Traverse(features, core.MapExample{"outlook": "overcast"})).To(Equal(0))
+	})
+
+	It("should traverse binary categorical splits", func() {
+		tree.Get(1).GetSplit().Subset = []int64{1}
+		subject := tree.Snapshot()
+		Expect(subject.SubsetOffsets).To(Equal([]int64{0, 1, 1, 1, 1}))
+		Expect(subject.Traverse(features, core.MapExample{"outlook": "overcast"})).To(Equal(1))
+		Expect(subject.Traverse(features, core.MapExample{"outlook": "sunny"})).To(Equal(2))
+	})
+
+	It("should write/read", func() {
+		subject := tree.Snapshot()
+
+		buf := new(bytes.Buffer)
+		Expect(subject.WriteTo(buf)).To(Equal(int64(buf.Len())))
+
+		loaded := new(internal.Snapshot)
+		Expect(loaded.ReadFrom(buf)).To(BeNumerically(">", 0))
+		Expect(loaded).To(Equal(subject))
+	})
+
+})
@@ -4,10 +4,12 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"sort"
 
+	"github.com/bsm/reason/classification"
 	common "github.com/bsm/reason/common/hoeffding"
 	"github.com/bsm/reason/core"
-	internal "github.com/bsm/reason/internal/hoeffding"
+	"github.com/bsm/reason/internal/hoeffding"
 	"github.com/bsm/reason/internal/iocount"
 	"github.com/bsm/reason/internal/protoio"
 	"github.com/bsm/reason/util"
@@ -24,6 +26,27 @@ func NewTree(model *core.Model, target string) *Tree {
 	return t
 }
 
+// CloneForReading creates a copy of the tree for read-only access. Error
+// detectors and the feature stats of split nodes are not copied, the feature
+// stats of leaves are only copied if leafStats is set, e.g. for naive-bayes
+// predictions.
+func (t *Tree) CloneForReading(leafStats bool) *Tree {
+	nodes := make([]*Node, len(t.Nodes))
+	for i, node := range t.Nodes {
+		if node != nil {
+			nodes[i] = node.cloneForReading(leafStats)
+		}
+	}
+
+	return &Tree{
+		Model:        proto.Clone(t.Model).(*core.Model),
+		Target:       t.Target,
+		Root:         t.Root,
+		Nodes:        nodes,
+		ClassWeights: append([]float64(nil), t.ClassWeights...),
+	}
+}
+
 // Get retrieves a node by its reference
 func (t *Tree) Get(nodeRef int64) *Node {
 	pos := int(nodeRef - 1)
@@ -46,6 +69,26 @@ func (t *Tree) Len() int {
 	return len(t.Nodes)
 }
 
+// ClassWeight returns the weight of a target category, 1 by default.
+func (t *Tree) ClassWeight(cat core.Category) float64 {
+	if pos := int(cat); pos > -1 && pos < len(t.ClassWeights) {
+		return t.ClassWeights[pos]
+	}
+	return 1
+}
+
+// ByteSize returns the estimated byte size of all registered nodes. It
+// visits all feature stats and is intended to initialise running totals.
+func (t *Tree) ByteSize() int {
+	size := 0
+	for _, node := range t.Nodes {
+		if node != nil {
+			size += node.ByteSize()
+		}
+	}
+	return size
+}
+
 // Add adds a new leaf node
 func (t *Tree) Add(stats *util.Vector) int64 {
 	if stats == nil {
@@ -59,31 +102,53 @@ func (t *Tree) Add(stats *util.Vector) int64 {
 	return int64(len(t.Nodes))
 }
 
-// Split splits an existing leaf node
-func (t *Tree) Split(leafRef int64, feature string, pre *util.Vector, post *util.VectorDistribution, pivot float64) {
-	if orig := t.Get(leafRef); orig == nil || orig.GetLeaf() == nil {
-		return
+// Split splits an existing leaf node. Returns the growth of the estimated
+// byte size.
+func (t *Tree) Split(leafRef int64, feature string, pre *util.Vector, post *util.VectorDistribution, pivot float64) int {
+	orig := t.Get(leafRef)
+	if orig == nil || orig.GetLeaf() == nil {
+		return 0
 	}
 
 	split := &SplitNode{
-		Feature: feature,
-		Pivot:   pivot,
+		Feature:          feature,
+		Pivot:            pivot,
+		WeightAtLastEval: pre.Weight(),
 	}
 
-	post.ForEach(func(i int, stats *util.Vector) bool {
-		split.Children.SetRef(i, t.Add(stats))
+	// add children in index order, to keep node references reproducible
+	indices := make([]int, 0, post.Len())
+	post.ForEach(func(i int, _ *util.Vector) bool {
+		indices = append(indices, i)
 		return true
 	})
+	sort.Ints(indices)
+
+	// copy child stats, post-split distributions may be retained with the
+	// feature stats of the split node
+	growth := -orig.ByteSize()
+	for _, i := range indices {
+		stats := post.Get(i).Clone()
+		childRef := t.Add(stats)
+		split.Children.SetRef(i, childRef)
+		split.addChildWeight(i, stats.Weight())
+		growth += t.Get(childRef).ByteSize()
+	}
 
-	kind := &Node_Split{Split: split}
-	t.Set(leafRef, &Node{Kind: kind, Stats: pre})
+	node := &Node{Kind: &Node_Split{Split: split}, Stats: pre}
+	t.Set(leafRef, node)
+	return growth + node.ByteSize()
 }
 
-// Traverse traverses the tree starting at the given node ID
-func (t *Tree) Traverse(x core.Example, nodeRef int64, parent *Node, parentIndex int, forEach func(*Node)) (*Node, int64, *Node, int) {
+// Traverse traverses the tree starting at the given node ID. It returns the
+// node reached, its ID, its parent and its index within the parent, as well
+// as its depth relative to the starting node, which is at depth 1. When the
+// matching child of a split node does not exist, the returned node is nil and
+// the depth is that of the missing child.
+func (t *Tree) Traverse(x core.Example, nodeRef int64, parent *Node, parentIndex int, forEach func(*Node)) (*Node, int64, *Node, int, int) {
 	node := t.Get(nodeRef)
 	if node == nil {
-		return node, nodeRef, parent, parentIndex
+		return node, nodeRef, parent, parentIndex, 1
 	}
 	if forEach != nil {
 		forEach(node)
@@ -92,14 +157,15 @@ func (t *Tree) Traverse(x core.Example, nodeRef int64, parent *Node, parentIndex
 	if split := node.GetSplit(); split != nil {
 		feature := t.Model.Feature(split.Feature)
 
-		if nodeIndex := int(split.childCat(feature, x)); nodeIndex > -1 {
+		if nodeIndex := split.childIndex(feature, x); nodeIndex > -1 {
 			if childRef := split.Children.GetRef(nodeIndex); childRef > 0 {
-				return t.Traverse(x, childRef, node, nodeIndex, forEach)
+				node, nodeRef, parent, parentIndex, depth := t.Traverse(x, childRef, node, nodeIndex, forEach)
+				return node, nodeRef, parent, parentIndex, depth + 1
 			}
-			return nil, nodeRef, node, nodeIndex
+			return nil, nodeRef, node, nodeIndex, 2
 		}
 	}
-	return node, nodeRef, parent, parentIndex
+	return node, nodeRef, parent, parentIndex, 1
 }
 
 // Prune prunes the leaves of a node recursively
@@ -151,9 +217,9 @@ func (t *Tree) WriteText(w io.Writer, nodeRef int64, indent, name string) (nw in
 		}
 
 		subIndent := indent + "\t"
-		split.Children.ForEach(func(i int, childRef int64) bool {
+		split.Children.ForEachOrdered(func(i int, childRef int64) bool {
 			var nn int64
-			nn, err = t.WriteText(w, childRef, subIndent, internal.FormatNodeCondition(feat, i, split.Pivot))
+			nn, err = t.WriteText(w, childRef, subIndent, split.formatCondition(feat, i))
 			nw += nn
 			return err == nil
 		})
@@ -187,7 +253,7 @@ func (t *Tree) WriteDOT(w io.Writer, nodeRef int64, name, label string) (nw int6
 			return
 		}
 
-		split.Children.ForEach(func(i int, childRef int64) bool {
+		split.Children.ForEachOrdered(func(i int, childRef int64) bool {
 			subName := fmt.Sprintf("%s_%d", name, i)
 
 			n, err = fmt.Fprintf(w, "  %s -> %s;\n", name, subName)
@@ -197,7 +263,7 @@ func (t *Tree) WriteDOT(w io.Writer, nodeRef int64, name, label string) (nw int6
 			}
 
 			var nn int64
-			nn, err = t.WriteDOT(w, childRef, subName, internal.FormatNodeCondition(feat, i, split.Pivot)+`\n`)
+			nn, err = t.WriteDOT(w, childRef, subName, split.formatCondition(feat, i)+`\n`)
 			nw += nn
 			return err == nil
 		})
@@ -208,12 +274,226 @@ func (t *Tree) WriteDOT(w io.Writer, nodeRef int64, name, label string) (nw int6
 	return
 }
 
-// FilterLeaves finds all leaf-nodes and appends them to dst
+// FilterLeaves finds all reachable leaf-nodes, including the leaves of
+// alternate subtrees, and appends them to dst
 func (t *Tree) FilterLeaves(dst []*Node) []*Node {
-	for _, n := range t.Nodes {
-		switch n.GetKind().(type) {
-		case *Node_Leaf:
-			dst = append(dst, n)
+	return t.filterLeaves(t.Root, dst)
+}
+
+func (t *Tree) filterLeaves(nodeRef int64, dst []*Node) []*Node {
+	node := t.Get(nodeRef)
+	if node == nil {
+		return dst
+	}
+
+	switch kind := node.GetKind().(type) {
+	case *Node_Leaf:
+		dst = append(dst, node)
+	case *Node_Split:
+		kind.Split.Children.ForEachOrdered(func(_ int, childRef int64) bool {
+			dst = t.filterLeaves(childRef, dst)
+			return true
+		})
+		if kind.Split.Alternate > 0 {
+			dst = t.filterLeaves(kind.Split.Alternate, dst)
+		}
+	}
+	return dst
+}
+
+// Path traverses the tree for example x, starting at the given node ID, and
+// appends the references of all visited nodes to dst.
+func (t *Tree) Path(x core.Example, nodeRef int64, dst []int64) []int64 {
+	for node := t.Get(nodeRef); node != nil; node = t.Get(nodeRef) {
+		dst = append(dst, nodeRef)
+
+		split := node.GetSplit()
+		if split == nil {
+			break
+		}
+
+		nodeIndex := split.childIndex(t.Model.Feature(split.Feature), x)
+		if nodeIndex < 0 {
+			break
+		}
+		nodeRef = split.Children.GetRef(nodeIndex)
+	}
+	return dst
+}
+
+// Leaf traverses the tree for example x, starting at the given node ID,
+// and returns the ID and the depth of the last node reached.
+func (t *Tree) Leaf(x core.Example, nodeRef int64) (int64, int) {
+	depth := 0
+	for node := t.Get(nodeRef); node != nil; node = t.Get(nodeRef) {
+		depth++
+
+		split := node.GetSplit()
+		if split == nil {
+			break
+		}
+
+		nodeIndex := split.childIndex(t.Model.Feature(split.Feature), x)
+		if nodeIndex < 0 {
+			break
+		}
+
+		childRef := split.Children.GetRef(nodeIndex)
+		if t.Get(childRef) == nil {
+			break
+		}
+		nodeRef = childRef
+	}
+	return nodeRef, depth
+}
+
+// Explain traverses the tree for example x, starting at the given node ID,
+// and calls fn for every visited node with a description of the decision.
+// It returns the reason why the traversal has stopped.
+func (t *Tree) Explain(x core.Example, nodeRef int64, fn func(*Node, *common.DecisionStep)) common.StopReason {
+	for node := t.Get(nodeRef); node != nil; node = t.Get(nodeRef) {
+		step := &common.DecisionStep{Weight: node.Weight()}
+
+		split := node.GetSplit()
+		if split == nil {
+			fn(node, step)
+			return common.StopLeaf
+		}
+
+		feature := t.Model.Feature(split.Feature)
+		step.Feature = split.Feature
+		step.Value = x.GetExampleValue(split.Feature)
+		step.Missing = !core.IsCat(split.childCat(feature, x))
+
+		nodeIndex := split.childIndex(feature, x)
+		if nodeIndex < 0 {
+			fn(node, step)
+			return common.StopMissingValue
+		}
+
+		step.Condition = split.formatCondition(feature, nodeIndex)
+		fn(node, step)
+
+		nodeRef = split.Children.GetRef(nodeIndex)
+	}
+	return common.StopMissingChild
+}
+
+// Track passes example x along its path, starting at the given node ID, and
+// accumulates the weights of examples routed by each split node, including
+// the weight of examples with missing values. Returns the growth of the
+// estimated byte size.
+func (t *Tree) Track(x core.Example, nodeRef int64, weight float64) int {
+	growth := 0
+	for node := t.Get(nodeRef); node != nil; node = t.Get(nodeRef) {
+		split := node.GetSplit()
+		if split == nil {
+			break
+		}
+
+		feature := t.Model.Feature(split.Feature)
+		growth += split.track(feature, x, weight)
+
+		nodeIndex := split.childIndex(feature, x)
+		if nodeIndex < 0 {
+			break
+		}
+		nodeRef = split.Children.GetRef(nodeIndex)
+	}
+	return growth
+}
+
+// Discard disables all leaves of the subtree at the given node ID, including
+// the leaves of alternate subtrees. Returns the reduction of the estimated
+// byte size.
+func (t *Tree) Discard(nodeRef int64) int {
+	node := t.Get(nodeRef)
+	if node == nil {
+		return 0
+	}
+
+	reduction := node.ByteSize()
+	node.ErrorDetector = nil
+	switch kind := node.GetKind().(type) {
+	case *Node_Leaf:
+		kind.Leaf.Disable()
+	case *Node_Split:
+		kind.Split.FeatureStats = nil
+		kind.Split.Children.ForEach(func(_ int, childRef int64) bool {
+			reduction += t.Discard(childRef)
+			return true
+		})
+		if kind.Split.Alternate > 0 {
+			reduction += t.Discard(kind.Split.Alternate)
+			kind.Split.Alternate = 0
+		}
+	}
+	return reduction - node.ByteSize()
+}
+
+// Revert turns a split node back into a leaf, re-using the feature stats
+// observed by the split node and discarding its subtrees. Returns the growth
+// of the estimated byte size.
+func (t *Tree) Revert(nodeRef int64) int {
+	node := t.Get(nodeRef)
+	if node == nil {
+		return 0
+	}
+
+	split := node.GetSplit()
+	if split == nil {
+		return 0
+	}
+
+	before := node.ByteSize()
+	featureStats := split.FeatureStats
+	split.FeatureStats = nil
+	reduction := t.Discard(nodeRef)
+
+	leaf := &LeafNode{FeatureStats: featureStats, WeightAtLastEval: node.Weight()}
+	node.Kind = &Node_Leaf{Leaf: leaf}
+	return node.ByteSize() - before - reduction
+}
+
+// Compact rebuilds the node registry without the nodes which are no
+// longer reachable from the root, i.e. subtrees discarded by drift
+// adaptation or split re-evaluation. Node references are re-assigned
+// in depth-first order. Returns the number of removed nodes.
+func (t *Tree) Compact() int {
+	refs := make([]int64, len(t.Nodes)+1)
+	nodes := t.collect(t.Root, refs, make([]*Node, 0, len(t.Nodes)))
+
+	for _, node := range nodes {
+		if split := node.GetSplit(); split != nil {
+			split.Children.remap(refs)
+			split.Alternate = lookupRef(refs, split.Alternate)
+		}
+	}
+
+	removed := len(t.Nodes) - len(nodes)
+	t.Root = lookupRef(refs, t.Root)
+	t.Nodes = nodes
+	return removed
+}
+
+// collect appends all reachable nodes of a subtree to dst in depth-first
+// order and records their new references.
+func (t *Tree) collect(nodeRef int64, refs []int64, dst []*Node) []*Node {
+	node := t.Get(nodeRef)
+	if node == nil || refs[nodeRef] != 0 {
+		return dst
+	}
+
+	dst = append(dst, node)
+	refs[nodeRef] = int64(len(dst))
+
+	if split := node.GetSplit(); split != nil {
+		split.Children.ForEachOrdered(func(_ int, childRef int64) bool {
+			dst = t.collect(childRef, refs, dst)
+			return true
+		})
+		if split.Alternate > 0 {
+			dst = t.collect(split.Alternate, refs, dst)
 		}
 	}
 	return dst
@@ -236,6 +516,9 @@ func (t *Tree) Accumulate(nodeRef int64, depth int, info *common.TreeInfo) {
 			t.Accumulate(childRef, depth+1, info)
 			return true
 		})
+		if split.Alternate > 0 {
+			info.NumAlternates++
+		}
 	} else if leaf := node.GetLeaf(); leaf != nil {
 		if leaf.IsDisabled {
 			info.NumDisabled++
@@ -245,6 +528,80 @@ func (t *Tree) Accumulate(nodeRef int64, depth int, info *common.TreeInfo) {
 	}
 }
 
+// AccumulateImportances collects the weighted impurity decreases of all
+// reachable split nodes and returns the aggregated stats of the subtree.
+// As split node stats are not updated after the split, decreases are
+// calculated from the aggregated stats of the child subtrees.
+func (t *Tree) AccumulateImportances(nodeRef int64, depth int, crit classification.SplitCriterion, acc common.ImportanceAccumulator) *util.Vector {
+	node := t.Get(nodeRef)
+	if node == nil {
+		return nil
+	}
+
+	split := node.GetSplit()
+	if split == nil {
+		return node.Stats
+	}
+
+	pre := new(util.Vector)
+	post := new(util.VectorDistribution)
+	split.Children.ForEach(func(i int, childRef int64) bool {
+		if stats := t.AccumulateImportances(childRef, depth+1, crit, acc); stats != nil {
+			stats.ForEach(func(j int, w float64) bool {
+				pre.Add(j, w)
+				post.Add(i, j, w)
+				return true
+			})
+		}
+		return true
+	})
+	acc.Add(split.Feature, pre.Weight()*crit.Merit(pre, post), depth)
+	return pre
+}
+
+// Contributions calculates additive feature contributions (SHAP values)
+// for example x, starting at the given node ID. Node weights are used as cover.
+func (t *Tree) Contributions(x core.Example, nodeRef int64) *common.Contributions {
+	return hoeffding.TreeSHAP(t.shapNode(x, nodeRef))
+}
+
+// shapNode converts the subtree at the given node ID into a SHAP node,
+// routing example x. Leaves output the probabilities of each target category.
+func (t *Tree) shapNode(x core.Example, nodeRef int64) *hoeffding.SHAPNode {
+	node := t.Get(nodeRef)
+	if node == nil {
+		return nil
+	}
+
+	split := node.GetSplit()
+	if split == nil {
+		res := &hoeffding.SHAPNode{Cover: node.Weight()}
+		if weight := node.Weight(); weight > 0 {
+			node.Stats.ForEach(func(i int, w float64) bool {
+				for len(res.Values) <= i {
+					res.Values = append(res.Values, 0)
+				}
+				res.Values[i] = w / weight
+				return true
+			})
+		}
+		return res
+	}
+
+	res := &hoeffding.SHAPNode{Feature: split.Feature, Hot: -1}
+	nodeIndex := split.childIndex(t.Model.Feature(split.Feature), x)
+	split.Children.ForEach(func(i int, childRef int64) bool {
+		if child := t.shapNode(x, childRef); child != nil {
+			if i == nodeIndex {
+				res.Hot = len(res.Children)
+			}
+			res.Children = append(res.Children, child)
+		}
+		return true
+	})
+	return res
+}
+
 // WriteTo writes a tree to a Writer.
 func (t *Tree) WriteTo(w io.Writer) (int64, error) {
 	wc := &iocount.Writer{W: w}
@@ -264,6 +621,19 @@ func (t *Tree) WriteTo(w io.Writer) (int64, error) {
 			return wc.N, err
 		}
 	}
+	if len(t.ClassWeights) != 0 {
+		if err := wp.WriteField(5, proto.WireBytes); err != nil {
+			return wc.N, err
+		}
+		if err := wp.WriteVarint(uint64(len(t.ClassWeights) * 8)); err != nil {
+			return wc.N, err
+		}
+		for _, f := range t.ClassWeights {
+			if err := wp.WriteDouble(f); err != nil {
+				return wc.N, err
+			}
+		}
+	}
 	return wc.N, wp.Flush()
 }
 
@@ -321,8 +691,36 @@ func (t *Tree) ReadFrom(r io.Reader) (int64, error) {
 				return rc.N, err
 			}
 			t.Nodes = append(t.Nodes, node)
+		case 5: // class weights
+			if wire != proto.WireBytes {
+				return rc.N, proto.ErrInternalBadWireType
+			}
+
+			slice, err := readFloatSlice(rp)
+			if err != nil {
+				return rc.N, err
+			}
+			t.ClassWeights = slice
 		default:
 			return rc.N, fmt.Errorf("hoeffding: unexpected field tag %d", tag)
 		}
 	}
 }
+
+func readFloatSlice(rp *protoio.Reader) ([]float64, error) {
+	u, err := rp.ReadVarint()
+	if err != nil {
+		return nil, err
+	}
+	n := int(u / 8)
+	slice := make([]float64, 0, n)
+
+	for i := 0; i < n; i++ {
+		f, err := rp.ReadDouble()
+		if err != nil {
+			return nil, err
+		}
+		slice = append(slice, f)
+	}
+	return slice, nil
+}
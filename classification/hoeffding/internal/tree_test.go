@@ -1,6 +1,8 @@
 package internal_test
 
 import (
+	"bytes"
+
 	"github.com/bsm/reason/classification/hoeffding/internal"
 	"github.com/bsm/reason/common/hoeffding"
 	"github.com/bsm/reason/core"
@@ -47,16 +49,45 @@ var _ = Describe("Tree", func() {
 		split := subject.Get(1).GetSplit()
 		Expect(split).NotTo(BeNil())
 		Expect(split.Children.Len()).To(Equal(3))
+
+		// children are added in index order
+		Expect(split.Children.GetRef(0)).To(Equal(int64(2)))
+		Expect(split.Children.GetRef(1)).To(Equal(int64(3)))
+		Expect(split.Children.GetRef(2)).To(Equal(int64(4)))
+
+		// child stats do not share state with the post-split distribution
+		child := subject.Get(2)
+		Expect(child.Stats).To(Equal(post.Get(0)))
+		Expect(child.Stats).NotTo(BeIdenticalTo(post.Get(0)))
+	})
+
+	It("should traverse binary categorical splits", func() {
+		subject.Split(1, "outlook", pre, post, 0)
+		split := subject.Get(1).GetSplit()
+		split.Subset = []int64{1}
+
+		_, _, _, parentIndex, _ := subject.Traverse(core.MapExample{"outlook": "overcast"}, 1, nil, -1, nil)
+		Expect(parentIndex).To(Equal(0))
+		_, _, _, parentIndex, _ = subject.Traverse(core.MapExample{"outlook": "sunny"}, 1, nil, -1, nil)
+		Expect(parentIndex).To(Equal(1))
+		_, _, _, parentIndex, _ = subject.Traverse(core.MapExample{}, 1, nil, -1, nil)
+		Expect(parentIndex).To(Equal(0))
+
+		b := new(bytes.Buffer)
+		Expect(subject.WriteText(b, 1, "", "ROOT")).To(Equal(int64(b.Len())))
+		Expect(b.String()).To(ContainSubstring("\toutlook in [overcast] [weight:"))
+		Expect(b.String()).To(ContainSubstring("\toutlook not in [overcast] [weight:"))
 	})
 
 	It("should traverse", func() {
 		subject.Split(1, "outlook", pre, post, 0)
 		root := subject.Get(1)
 
-		node, nodeRef, parent, parentIndex := subject.Traverse(core.MapExample{"outlook": "overcast"}, 1, nil, -1, nil)
+		node, nodeRef, parent, parentIndex, depth := subject.Traverse(core.MapExample{"outlook": "overcast"}, 1, nil, -1, nil)
 		Expect(node.Stats.Sparse).To(Equal(map[int64]float64{0: 4}))
 		Expect(parent).To(Equal(root))
 		Expect(parentIndex).To(Equal(1))
+		Expect(depth).To(Equal(2))
 
 		var traversed []*internal.Node
 		subject.Traverse(core.MapExample{"outlook": "overcast"}, 1, nil, -1, func(n *internal.Node) {
@@ -66,16 +97,215 @@ var _ = Describe("Tree", func() {
 		Expect(traversed[0].Weight()).To(Equal(14.0))
 		Expect(traversed[1].Weight()).To(Equal(4.0))
 
-		node, nodeRef, parent, parentIndex = subject.Traverse(core.MapExample{"outlook": "overcast"}, 99, nil, -1, nil)
+		node, nodeRef, parent, parentIndex, depth = subject.Traverse(core.MapExample{"outlook": "overcast"}, 99, nil, -1, nil)
 		Expect(node).To(BeNil())
 		Expect(nodeRef).To(Equal(int64(99)))
 		Expect(parent).To(BeNil())
 		Expect(parentIndex).To(Equal(-1))
+		Expect(depth).To(Equal(1))
 	})
 
 	It("should filter leaves", func() {
 		subject.Split(1, "outlook", pre, post, 0)
 		Expect(subject.FilterLeaves(nil)).To(HaveLen(3))
+
+		subject.Get(1).GetSplit().Alternate = subject.Add(nil)
+		Expect(subject.FilterLeaves(nil)).To(HaveLen(4))
+	})
+
+	It("should find paths", func() {
+		subject.Split(1, "outlook", pre, post, 0)
+		Expect(subject.Path(core.MapExample{"outlook": "overcast"}, 1, nil)).To(Equal([]int64{1, 3}))
+		Expect(subject.Path(core.MapExample{}, 1, nil)).To(Equal([]int64{1, 2}))
+		Expect(subject.Path(core.MapExample{}, 99, nil)).To(BeEmpty())
+	})
+
+	It("should find leaves", func() {
+		ref, depth := subject.Leaf(core.MapExample{"outlook": "overcast"}, 1)
+		Expect(ref).To(Equal(int64(1)))
+		Expect(depth).To(Equal(1))
+
+		subject.Split(1, "outlook", pre, post, 0)
+		childRef := subject.Get(1).GetSplit().Children.GetRef(1)
+		ref, depth = subject.Leaf(core.MapExample{"outlook": "overcast"}, 1)
+		Expect(ref).To(Equal(childRef))
+		Expect(depth).To(Equal(2))
+
+		ref, depth = subject.Leaf(core.MapExample{}, 99)
+		Expect(ref).To(Equal(int64(99)))
+		Expect(depth).To(Equal(0))
+	})
+
+	It("should explain", func() {
+		subject.Split(1, "outlook", pre, post, 0)
+		split := subject.Get(1).GetSplit()
+
+		var steps []*hoeffding.DecisionStep
+		explain := func(x core.Example) hoeffding.StopReason {
+			steps = steps[:0]
+			return subject.Explain(x, 1, func(_ *internal.Node, step *hoeffding.DecisionStep) {
+				steps = append(steps, step)
+			})
+		}
+
+		Expect(explain(core.MapExample{"outlook": "overcast"})).To(Equal(hoeffding.StopLeaf))
+		Expect(steps).To(Equal([]*hoeffding.DecisionStep{
+			{Weight: 14, Feature: "outlook", Condition: "outlook = overcast", Value: "overcast"},
+			{Weight: 4},
+		}))
+
+		Expect(explain(core.MapExample{})).To(Equal(hoeffding.StopLeaf))
+		Expect(steps).To(Equal([]*hoeffding.DecisionStep{
+			{Weight: 14, Feature: "outlook", Condition: "outlook = rainy", Missing: true},
+			{Weight: 5},
+		}))
+
+		split.DefaultChild = 0
+		Expect(explain(core.MapExample{})).To(Equal(hoeffding.StopMissingValue))
+		Expect(steps).To(Equal([]*hoeffding.DecisionStep{
+			{Weight: 14, Feature: "outlook", Missing: true},
+		}))
+
+		split.Children.SetRef(1, 0)
+		Expect(explain(core.MapExample{"outlook": "overcast"})).To(Equal(hoeffding.StopMissingChild))
+		Expect(steps).To(HaveLen(1))
+	})
+
+	It("should calculate contributions", func() {
+		subject.Split(1, "outlook", pre, post, 0)
+
+		res := subject.Contributions(core.MapExample{"outlook": "overcast"}, 1)
+		Expect(res.Output).To(Equal([]float64{1, 0}))
+		Expect(res.Expected[0]).To(BeNumerically("~", 9.0/14, 1e-9))
+		Expect(res.Expected[1]).To(BeNumerically("~", 5.0/14, 1e-9))
+		Expect(res.Features).To(HaveLen(1))
+		Expect(res.Features["outlook"][0]).To(BeNumerically("~", 5.0/14, 1e-9))
+		Expect(res.Features["outlook"][1]).To(BeNumerically("~", -5.0/14, 1e-9))
+	})
+
+	It("should track routed weights", func() {
+		subject.Split(1, "outlook", pre, post, 0)
+		split := subject.Get(1).GetSplit()
+		Expect(split.ChildWeights.Weight()).To(Equal(14.0))
+		Expect(split.DefaultChild).To(Equal(int64(1)))
+
+		subject.Track(core.MapExample{"outlook": "overcast"}, 1, 2.0)
+		Expect(split.ChildWeights.Get(1)).To(Equal(6.0))
+		Expect(split.DefaultChild).To(Equal(int64(2)))
+
+		subject.Track(core.MapExample{}, 1, 1.0)
+		Expect(split.MissingWeight).To(Equal(1.0))
+		Expect(split.ChildWeights.Weight()).To(Equal(16.0))
+
+		childRef := split.Children.GetRef(1)
+		Expect(subject.Path(core.MapExample{}, 1, nil)).To(Equal([]int64{1, childRef}))
+	})
+
+	It("should discard subtrees", func() {
+		subject.Split(1, "outlook", pre, post, 0)
+		split := subject.Get(1).GetSplit()
+		split.Alternate = subject.Add(nil)
+
+		subject.Discard(1)
+		Expect(split.Alternate).To(Equal(int64(0)))
+		for _, node := range subject.Nodes {
+			if leaf := node.GetLeaf(); leaf != nil {
+				Expect(leaf.IsDisabled).To(BeTrue())
+			}
+		}
+	})
+
+	It("should report observed split weights", func() {
+		subject.Split(1, "outlook", pre, post, 0)
+		split := subject.Get(1).GetSplit()
+		Expect(split.ObservedWeight()).To(Equal(0.0))
+
+		stats := &internal.FeatureStats{MissingWeight: 2}
+		stats.FetchCategorical().Add(0, 1, 3)
+		split.FeatureStats = map[string]*internal.FeatureStats{"outlook": stats}
+		Expect(split.ObservedWeight()).To(Equal(5.0))
+	})
+
+	It("should revert splits", func() {
+		subject.Split(1, "outlook", pre, post, 0)
+		split := subject.Get(1).GetSplit()
+		split.FeatureStats = map[string]*internal.FeatureStats{"temp": new(internal.FeatureStats)}
+		split.Alternate = subject.Add(nil)
+
+		subject.Revert(1)
+		leaf := subject.Get(1).GetLeaf()
+		Expect(leaf).NotTo(BeNil())
+		Expect(leaf.FeatureStats).To(HaveKey("temp"))
+		Expect(leaf.WeightAtLastEval).To(Equal(14.0))
+		Expect(subject.FilterLeaves(nil)).To(HaveLen(1))
+	})
+
+	It("should clone for reading", func() {
+		subject.Split(1, "outlook", pre, post, 0)
+		subject.Get(1).GetSplit().FeatureStats = map[string]*internal.FeatureStats{"temp": new(internal.FeatureStats)}
+		subject.Get(2).GetLeaf().FeatureStats = map[string]*internal.FeatureStats{"temp": new(internal.FeatureStats)}
+
+		clone := subject.CloneForReading(false)
+		Expect(clone.Len()).To(Equal(4))
+		Expect(clone.Get(1).Stats).To(Equal(subject.Get(1).Stats))
+		Expect(clone.Get(1).Stats).NotTo(BeIdenticalTo(subject.Get(1).Stats))
+		Expect(clone.Get(1).GetSplit().Children).To(Equal(subject.Get(1).GetSplit().Children))
+		Expect(clone.Get(1).GetSplit().FeatureStats).To(BeNil())
+		Expect(clone.Get(2).GetLeaf().FeatureStats).To(BeNil())
+
+		clone = subject.CloneForReading(true)
+		Expect(clone.Get(1).GetSplit().FeatureStats).To(BeNil())
+		Expect(clone.Get(2).GetLeaf().FeatureStats).To(HaveKey("temp"))
+		Expect(clone.Get(2).GetLeaf().FeatureStats["temp"]).NotTo(BeIdenticalTo(subject.Get(2).GetLeaf().FeatureStats["temp"]))
+	})
+
+	It("should compact", func() {
+		subject.Split(1, "outlook", pre, post, 0)
+		subject.Split(3, "windy", pre, post, 0)
+		subject.Split(4, "windy", pre, post, 0)
+		subject.Get(1).GetSplit().Children = internal.SplitNode_Children{Dense: []int64{2, 3, 4}}
+		subject.Get(1).GetSplit().Alternate = subject.Add(nil)
+		Expect(subject.Len()).To(Equal(11))
+
+		b1 := new(bytes.Buffer)
+		Expect(subject.WriteTo(b1)).To(Equal(int64(b1.Len())))
+
+		subject.Revert(3)
+		Expect(subject.Compact()).To(Equal(3))
+		Expect(subject.Len()).To(Equal(8))
+		Expect(subject.Root).To(Equal(int64(1)))
+
+		split := subject.Get(1).GetSplit()
+		Expect(split.Children.Dense).To(Equal([]int64{2, 3, 4}))
+		Expect(split.Alternate).To(Equal(int64(8)))
+		Expect(subject.Get(3).GetLeaf()).NotTo(BeNil())
+		Expect(subject.Get(4).GetSplit().Children.Sparse).To(Equal(map[int64]int64{0: 5, 1: 6, 2: 7}))
+		Expect(subject.FilterLeaves(nil)).To(HaveLen(6))
+
+		b2 := new(bytes.Buffer)
+		Expect(subject.WriteTo(b2)).To(Equal(int64(b2.Len())))
+		Expect(b2.Len()).To(BeNumerically("<", b1.Len()))
+
+		Expect(subject.Compact()).To(Equal(0))
+		Expect(subject.Len()).To(Equal(8))
+	})
+
+	It("should store class weights", func() {
+		Expect(subject.ClassWeight(1)).To(Equal(1.0))
+
+		subject.ClassWeights = []float64{1, 20}
+		Expect(subject.ClassWeight(0)).To(Equal(1.0))
+		Expect(subject.ClassWeight(1)).To(Equal(20.0))
+		Expect(subject.ClassWeight(2)).To(Equal(1.0))
+		Expect(subject.ClassWeight(core.NoCategory)).To(Equal(1.0))
+
+		buf := new(bytes.Buffer)
+		Expect(subject.WriteTo(buf)).To(Equal(int64(buf.Len())))
+
+		tree := new(internal.Tree)
+		Expect(tree.ReadFrom(buf)).To(BeNumerically(">", 0))
+		Expect(tree.ClassWeights).To(Equal([]float64{1, 20}))
+		Expect(tree.Len()).To(Equal(subject.Len()))
 	})
 
 	It("should accumulate info", func() {
@@ -89,6 +319,12 @@ var _ = Describe("Tree", func() {
 			NumDisabled: 0,
 			MaxDepth:    2,
 		}))
+
+		subject.Get(1).GetSplit().Alternate = subject.Add(nil)
+		info = new(hoeffding.TreeInfo)
+		subject.Accumulate(1, 1, info)
+		Expect(info.NumNodes).To(Equal(4))
+		Expect(info.NumAlternates).To(Equal(1))
 	})
 
 })
@@ -5,13 +5,21 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"math/rand"
 	"sort"
 	"sync"
+	"sync/atomic"
 
 	"github.com/bsm/reason/classification"
 	"github.com/bsm/reason/classification/hoeffding/internal"
 	common "github.com/bsm/reason/common/hoeffding"
 	"github.com/bsm/reason/core"
+	"github.com/bsm/reason/util"
+)
+
+const (
+	adaptiveMinWidth       = 300
+	adaptiveSwapConfidence = 0.05
 )
 
 // Tree is an implementation of a Hoeffding tree.
@@ -19,11 +27,24 @@ type Tree struct {
 	tree   *internal.Tree
 	target *core.Feature
 
-	config Config
-	cycles int
+	config   Config
+	cycles   int
+	byteSize int
+	tracer   common.Tracer
+	rnd      *rand.Rand
 
 	tn []*internal.Node
+	sn []int64
 	mu sync.RWMutex
+
+	published   atomic.Value // *publication
+	unpublished int
+}
+
+// publication is a published version of the tree.
+type publication struct {
+	tree     *internal.Tree
+	byteSize int
 }
 
 // Load loads a new tree from a reader.
@@ -54,24 +75,105 @@ func newTree(t *internal.Tree, c *Config) (*Tree, error) {
 		return nil, fmt.Errorf("hoeffding: feature %q is not categorical", t.Target)
 	}
 
-	return &Tree{
+	if _, ok := config.SplitBound.(common.McDiarmidGini); ok {
+		if _, ok := config.SplitCriterion.(classification.GiniImpurity); !ok {
+			return nil, fmt.Errorf("hoeffding: McDiarmidGini bound requires the GiniImpurity split criterion")
+		}
+	}
+
+	if len(config.ClassWeights) != 0 {
+		weights, err := classWeights(target, config.ClassWeights)
+		if err != nil {
+			return nil, err
+		}
+		t.ClassWeights = weights
+	}
+
+	tracer := config.Tracer
+	if tracer == nil && config.EnableTracing {
+		tracer = common.NewChanTracer(common.DefaultTraceBufferSize)
+	}
+
+	tree := &Tree{
 		tree:   t,
 		target: target,
 		config: config,
-	}, nil
+		tracer: tracer,
+		rnd:    rand.New(rand.NewSource(config.Seed)),
+
+		byteSize: t.ByteSize(),
+	}
+	if config.PublishPeriod > 0 {
+		tree.publish()
+	}
+	return tree, nil
+}
+
+// classWeights resolves class weights by target category.
+func classWeights(target *core.Feature, m map[string]float64) ([]float64, error) {
+	var weights []float64
+	for name, w := range m {
+		if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return nil, fmt.Errorf("hoeffding: invalid weight %v for class %q", w, name)
+		}
+
+		cat := target.CategoryOf(name)
+		if !core.IsCat(cat) {
+			return nil, fmt.Errorf("hoeffding: unknown class %q", name)
+		}
+		for int(cat) >= len(weights) {
+			weights = append(weights, 1)
+		}
+		weights[cat] = w
+	}
+	return weights, nil
+}
+
+// Traces returns the channel of trace events. It returns nil unless
+// tracing is enabled or a ChanTracer is configured. Events are dropped
+// when the channel is not consumed.
+func (t *Tree) Traces() <-chan *common.Trace {
+	if ct, ok := t.tracer.(*common.ChanTracer); ok {
+		return ct.C
+	}
+	return nil
 }
 
 // Info returns information about the tree
 func (t *Tree) Info() *common.TreeInfo {
 	info := new(common.TreeInfo)
 
-	t.mu.RLock()
-	t.tree.Accumulate(t.tree.Root, 1, info)
-	t.mu.RUnlock()
+	p, release := t.acquirePublication()
+	p.tree.Accumulate(p.tree.Root, 1, info)
+	info.ByteSize = p.byteSize
+	if t.config.IncludeFeatureImportances {
+		info.FeatureImportances = t.featureImportances(p.tree)
+	}
+	release()
 
 	return info
 }
 
+// FeatureImportances calculates the mean decrease in impurity (MDI) of all
+// features used by split nodes, normalised to sum up to 1.
+func (t *Tree) FeatureImportances() common.FeatureImportances {
+	tree, release := t.acquire()
+	defer release()
+
+	return t.featureImportances(tree)
+}
+
+// Publish publishes the current state of the tree to readers. It is
+// only effective if copy-on-write publication is enabled via PublishPeriod.
+func (t *Tree) Publish() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.config.PublishPeriod > 0 {
+		t.publish()
+	}
+}
+
 // Prune manually prunes the tree to limit it to maxLearningNodes.
 func (t *Tree) Prune(maxLearningNodes int) {
 	t.mu.Lock()
@@ -80,31 +182,97 @@ func (t *Tree) Prune(maxLearningNodes int) {
 	t.prune(maxLearningNodes)
 }
 
+// Compact removes nodes which are no longer reachable, i.e. subtrees
+// discarded by drift adaptation or split re-evaluation, and reduces the
+// memory footprint and the serialized size of the tree. Returns the
+// number of removed nodes.
+func (t *Tree) Compact() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	removed := t.tree.Compact()
+	t.byteSize = t.tree.ByteSize()
+	return removed
+}
+
 // Predict traverses the tree for the given example x and appends a prediction
 // for every branch to dst, returning it in the end.
 // The predictions will therefore increase in accuracy with the most accurate
 // one being the last element of the returned slice.
 func (t *Tree) Predict(dst classification.Predictions, x core.Example) classification.Predictions {
-	t.mu.RLock()
-	defer t.mu.RUnlock()
+	tree, release := t.acquire()
+	defer release()
 
-	t.tree.Traverse(x, t.tree.Root, nil, -1, func(node *internal.Node) {
-		dst = append(dst, classification.Prediction{Vector: *node.Stats})
+	tree.Traverse(x, tree.Root, nil, -1, func(node *internal.Node) {
+		dst = append(dst, t.predict(tree.Model, node, x))
 	})
 	return dst
 }
 
+// PredictLeaf traverses the tree for the given example x and returns the
+// identifier and the depth of the node it ends up in. Identifiers are stable
+// while the tree grows, but may be re-assigned by Compact. Nodes with
+// identifiers beyond the configured LeafBuckets are reported as -1.
+// Examples with missing values follow the default child of a split, the
+// traversal only stops early at split nodes that have no default child yet
+// or no matching child.
+func (t *Tree) PredictLeaf(x core.Example) (int64, int) {
+	tree, release := t.acquire()
+	defer release()
+
+	ref, depth := tree.Leaf(x, tree.Root)
+	if ref >= int64(t.config.LeafBuckets) {
+		ref = -1
+	}
+	return ref, depth
+}
+
+// LeafFeature returns a categorical feature with the configured number of
+// LeafBuckets, which maps the identifiers returned by PredictLeaf to their
+// own buckets. It can be used to feed leaf membership into other models,
+// e.g. ftrl.Optimizer.
+func (t *Tree) LeafFeature(name string) *core.Feature {
+	return core.NewCategoricalFeatureIdentityBuckets(name, uint32(t.config.LeafBuckets))
+}
+
 // Train passes an example x with a weight (usually 1.0) to the tree for training.
+// The weight is multiplied by the class weight of the example's target.
 func (t *Tree) Train(x core.Example, weight float64) *common.SplitAttemptInfo {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
-	node, nodeRef, parent, parentIndex := t.tree.Traverse(x, t.tree.Root, nil, -1, nil)
+	if len(t.tree.ClassWeights) != 0 {
+		weight *= t.tree.ClassWeight(t.target.Category(x))
+	}
+
+	if t.config.PublishPeriod > 0 {
+		defer t.publishPeriodically()
+	}
+	if t.config.Adaptive {
+		t.adapt(x, weight, t.tree.Root, 1)
+	}
+	if !t.config.ReevaluateSplits {
+		return t.train(x, weight, t.tree.Root, 1)
+	}
+
+	t.sn = t.filterSplits(x, t.sn[:0])
+	info := t.train(x, weight, t.tree.Root, 1)
+	if rinfo := t.reevaluate(x, weight, t.sn); info == nil {
+		info = rinfo
+	}
+	return info
+}
+
+func (t *Tree) train(x core.Example, weight float64, startRef int64, startDepth int) *common.SplitAttemptInfo {
+	t.grow(t.tree.Track(x, startRef, weight))
+
+	node, nodeRef, parent, parentIndex, depth := t.tree.Traverse(x, startRef, nil, -1, nil)
 	if node == nil && parentIndex > -1 {
 		if split := parent.GetSplit(); split != nil {
 			ref := t.tree.Add(nil)
 			node = t.tree.Get(ref)
 			split.Children.SetRef(parentIndex, ref)
+			t.grow(node.ByteSize())
 		}
 	}
 	if node == nil {
@@ -112,13 +280,26 @@ func (t *Tree) Train(x core.Example, weight float64) *common.SplitAttemptInfo {
 	}
 
 	if leaf := node.GetLeaf(); leaf != nil {
+		// Track prediction accuracy, if adaptive
+		if t.config.LeafPrediction == LeafPredictionNBAdaptive {
+			leaf.TrackPredictions(t.tree.Model, t.target, x, weight, node)
+		}
+
+		// Sample a feature subspace, if enabled
+		t.sampleSubspace(leaf)
+
 		// Observe an example
-		leaf.Observe(t.tree.Model, t.target, x, weight, node)
+		t.grow(leaf.Observe(t.tree.Model, t.target, x, weight, t.config.NumericObserver, node))
 
 		// Pre-prune, if enabled
 		if t.config.PrunePeriod > 0 {
-			if t.cycles++; t.config.MaxLearningNodes > 0 && t.cycles%t.config.PrunePeriod == 0 {
-				t.prune(t.config.MaxLearningNodes)
+			if t.cycles++; t.cycles%t.config.PrunePeriod == 0 {
+				if t.config.MaxLearningNodes > 0 {
+					t.prune(t.config.MaxLearningNodes)
+				}
+				if t.config.MaxByteSize > 0 {
+					t.limitByteSize(t.config.MaxByteSize)
+				}
 			}
 		}
 
@@ -137,20 +318,89 @@ func (t *Tree) Train(x core.Example, weight float64) *common.SplitAttemptInfo {
 		}
 
 		// Try to split
-		info := t.attemptSplit(leaf, node, nodeRef, nodeWeight)
-		if info.Success {
-			if parent == nil {
-				t.tree.Root = nodeRef
-			} else if split := parent.GetSplit(); split != nil {
-				split.Children.SetRef(parentIndex, nodeRef)
-			}
-		}
+		info := t.attemptSplit(leaf, node, nodeRef, startDepth+depth-1, nodeWeight)
+		t.traceSplitAttempt(nodeRef, info)
 		return info
 	}
 
 	return nil
 }
 
+// adapt updates the error change detectors along the path of example x,
+// starting at the given node, grows alternate subtrees when the error
+// increases and replaces the original subtrees when alternates become more
+// accurate.
+func (t *Tree) adapt(x core.Example, weight float64, startRef int64, startDepth int) {
+	targetCat := t.target.Category(x)
+	if !core.IsCat(targetCat) {
+		return
+	}
+
+	path := t.tree.Path(x, startRef, nil)
+	if len(path) == 0 {
+		return
+	}
+
+	// Determine the prediction error of the reached node, ties
+	// including the target category are considered correct
+	prediction := t.predict(t.tree.Model, t.tree.Get(path[len(path)-1]), x)
+	errValue := 1.0
+	if _, w := prediction.TopW(); w > 0 && prediction.W(targetCat) == w {
+		errValue = 0.0
+	}
+
+	for i, nodeRef := range path {
+		node := t.tree.Get(nodeRef)
+		if node.ErrorDetector == nil {
+			node.ErrorDetector = util.NewADWIN(t.config.DriftConfidence)
+		}
+
+		prevErr := node.ErrorDetector.Mean()
+		increased := node.ErrorDetector.Add(errValue) && node.ErrorDetector.Mean() > prevErr
+
+		split := node.GetSplit()
+		if split == nil {
+			continue
+		}
+
+		if split.Alternate == 0 {
+			if increased {
+				split.Alternate = t.tree.Add(nil)
+				t.grow(t.tree.Get(split.Alternate).ByteSize())
+				t.trace(&common.Trace{Kind: common.TraceDriftDetected, NodeRef: nodeRef})
+			}
+		} else if alt := t.tree.Get(split.Alternate); alt != nil && alt.ErrorDetector != nil &&
+			node.ErrorDetector.Width > adaptiveMinWidth && alt.ErrorDetector.Width > adaptiveMinWidth {
+
+			nodeErr := node.ErrorDetector.Mean()
+			altErr := alt.ErrorDetector.Mean()
+			n := 1/float64(node.ErrorDetector.Width) + 1/float64(alt.ErrorDetector.Width)
+			bound := math.Sqrt(2 * nodeErr * (1 - nodeErr) * math.Log(2/adaptiveSwapConfidence) * n)
+
+			if bound < nodeErr-altErr {
+				// Replace the subtree with the alternate and discard the original
+				altRef := split.Alternate
+				split.Alternate = 0
+				t.tree.Set(nodeRef, alt)
+				t.tree.Set(altRef, node)
+				t.grow(-t.tree.Discard(altRef))
+				t.trace(&common.Trace{Kind: common.TraceDriftReset, NodeRef: nodeRef})
+				return
+			} else if bound < altErr-nodeErr {
+				// Discard the alternate
+				t.grow(-t.tree.Discard(split.Alternate))
+				split.Alternate = 0
+				t.trace(&common.Trace{Kind: common.TraceAlternateDiscarded, NodeRef: nodeRef})
+			}
+		}
+
+		if altRef := split.Alternate; altRef > 0 {
+			t.adapt(x, weight, altRef, startDepth+i)
+			t.train(x, weight, altRef, startDepth+i)
+		}
+	}
+}
+
 // WriteTo implements io.WriterTo
 func (t *Tree) WriteTo(w io.Writer) (int64, error) {
 	t.mu.RLock()
@@ -163,10 +413,10 @@ func (t *Tree) WriteTo(w io.Writer) (int64, error) {
 func (t *Tree) WriteText(w io.Writer) (int64, error) {
 	buf := bufio.NewWriter(w)
 
-	t.mu.RLock()
-	defer t.mu.RUnlock()
+	tree, release := t.acquire()
+	defer release()
 
-	nn, err := t.tree.WriteText(w, t.tree.Root, "", "ROOT")
+	nn, err := tree.WriteText(w, tree.Root, "", "ROOT")
 	if err != nil {
 		return nn, err
 	}
@@ -188,10 +438,10 @@ func (t *Tree) WriteDOT(w io.Writer) (int64, error) {
 		return nw, err
 	}
 
-	t.mu.RLock()
-	defer t.mu.RUnlock()
+	tree, release := t.acquire()
+	defer release()
 
-	nn, err := t.tree.WriteDOT(buf, t.tree.Root, "N", "")
+	nn, err := tree.WriteDOT(buf, tree.Root, "N", "")
 	nw += nn
 	if err != nil {
 		return nw, err
@@ -205,6 +455,26 @@ func (t *Tree) WriteDOT(w io.Writer) (int64, error) {
 	return nw, buf.Flush()
 }
 
+func (t *Tree) predict(model *core.Model, node *internal.Node, x core.Example) classification.Prediction {
+	leaf := node.GetLeaf()
+	if leaf == nil {
+		return classification.Prediction{Vector: *node.Stats}
+	}
+
+	switch t.config.LeafPrediction {
+	case LeafPredictionNBAdaptive:
+		if !leaf.PrefersNaiveBayes() {
+			break
+		}
+		fallthrough
+	case LeafPredictionNaiveBayes:
+		if nb := leaf.PredictNaiveBayes(model, x, node); nb != nil {
+			return classification.Prediction{Vector: *nb}
+		}
+	}
+	return classification.Prediction{Vector: *node.Stats}
+}
+
 func (t *Tree) prune(maxLearningNodes int) {
 	if maxLearningNodes < 0 {
 		return
@@ -215,47 +485,194 @@ func (t *Tree) prune(maxLearningNodes int) {
 		return
 	}
 
-	// Sort leaves by weight (highest first)
-	sort.Slice(t.tn, func(i, j int) bool {
-		return t.tn[i].Weight() >= t.tn[j].Weight()
-	})
+	// Sort leaves by weight or promise (highest first)
+	if t.config.PruneByPromise {
+		sort.SliceStable(t.tn, func(i, j int) bool {
+			return t.tn[i].Promise() > t.tn[j].Promise()
+		})
+	} else if t.config.Deterministic {
+		sort.SliceStable(t.tn, func(i, j int) bool {
+			return t.tn[i].Weight() > t.tn[j].Weight()
+		})
+	} else {
+		sort.Slice(t.tn, func(i, j int) bool {
+			return t.tn[i].Weight() >= t.tn[j].Weight()
+		})
+	}
 
 	// Update node status
+	numDeactivated := 0
 	for i, node := range t.tn {
 		if leaf := node.GetLeaf(); leaf != nil {
 			if i < maxLearningNodes {
 				leaf.Enable()
-			} else {
-				leaf.Disable()
+			} else if !leaf.IsDisabled {
+				t.disable(leaf, node)
+				numDeactivated++
 			}
 		}
 	}
+	if numDeactivated != 0 {
+		t.trace(&common.Trace{Kind: common.TracePrune, NumDeactivated: numDeactivated})
+	}
+}
+
+// limitByteSize deactivates the leaves with the least promise until the
+// estimated byte size of the tree is within maxByteSize.
+func (t *Tree) limitByteSize(maxByteSize int) {
+	if t.byteSize <= maxByteSize {
+		return
+	}
+
+	t.tn = t.tree.FilterLeaves(t.tn[:0])
+
+	// Sort leaves by promise (lowest first)
+	sort.SliceStable(t.tn, func(i, j int) bool {
+		return t.tn[i].Promise() < t.tn[j].Promise()
+	})
+
+	// Deactivate leaves until the tree fits
+	numDeactivated := 0
+	for _, node := range t.tn {
+		if t.byteSize <= maxByteSize {
+			break
+		}
+		if leaf := node.GetLeaf(); leaf != nil && !leaf.IsDisabled {
+			t.disable(leaf, node)
+			numDeactivated++
+		}
+	}
+	if numDeactivated != 0 {
+		t.trace(&common.Trace{Kind: common.TraceDeactivate, NumDeactivated: numDeactivated})
+	}
 }
 
-func (t *Tree) attemptSplit(leaf *internal.LeafNode, node *internal.Node, nodeRef int64, weight float64) *common.SplitAttemptInfo {
+// disable disables a leaf and releases its feature stats.
+func (t *Tree) disable(leaf *internal.LeafNode, node *internal.Node) {
+	before := node.ByteSize()
+	leaf.Disable()
+	t.grow(node.ByteSize() - before)
+}
+
+// grow adjusts the estimated byte size of the tree.
+func (t *Tree) grow(delta int) {
+	t.byteSize += delta
+}
+
+func (t *Tree) featureImportances(tree *internal.Tree) common.FeatureImportances {
+	acc := make(common.ImportanceAccumulator)
+	tree.AccumulateImportances(tree.Root, 1, t.config.SplitCriterion, acc)
+	return acc.Result()
+}
+
+// acquire returns the tree for read access and a function to release it.
+// If copy-on-write publication is enabled, the most recently published
+// version is returned without locking.
+func (t *Tree) acquire() (*internal.Tree, func()) {
+	if p, ok := t.published.Load().(*publication); ok {
+		return p.tree, func() {}
+	}
+
+	t.mu.RLock()
+	return t.tree, t.mu.RUnlock
+}
+
+// acquirePublication is like acquire, but also returns the estimated byte
+// size of the tree.
+func (t *Tree) acquirePublication() (*publication, func()) {
+	if p, ok := t.published.Load().(*publication); ok {
+		return p, func() {}
+	}
+
+	t.mu.RLock()
+	return &publication{tree: t.tree, byteSize: t.byteSize}, t.mu.RUnlock
+}
+
+func (t *Tree) publish() {
+	// leaf feature stats are only required for naive-bayes predictions
+	leafStats := t.config.LeafPrediction != LeafPredictionMajorityClass
+	t.published.Store(&publication{tree: t.tree.CloneForReading(leafStats), byteSize: t.byteSize})
+	t.unpublished = 0
+}
+
+func (t *Tree) publishPeriodically() {
+	if t.unpublished++; t.unpublished >= t.config.PublishPeriod {
+		t.publish()
+	}
+}
+
+func (t *Tree) trace(e *common.Trace) {
+	if t.tracer != nil {
+		t.tracer.Trace(e)
+	}
+}
+
+func (t *Tree) traceSplitAttempt(nodeRef int64, info *common.SplitAttemptInfo) {
+	if t.tracer == nil || info == nil {
+		return
+	}
+
+	t.tracer.Trace(&common.Trace{Kind: common.TraceSplitAttempt, NodeRef: nodeRef, SplitAttempt: info})
+	if info.Success {
+		e := &common.Trace{Kind: common.TraceSplit, NodeRef: nodeRef, SplitAttempt: info}
+		if split := t.tree.Get(nodeRef).GetSplit(); split != nil {
+			e.Feature = split.Feature
+		}
+		t.tracer.Trace(e)
+	}
+}
+
+func (t *Tree) split(nodeRef int64, c *internal.SplitCandidate) {
+	// In EFDT mode, split nodes keep the feature stats observed by the leaf
+	var featureStats map[string]*internal.FeatureStats
+	if leaf := t.tree.Get(nodeRef).GetLeaf(); leaf != nil && t.config.ReevaluateSplits {
+		featureStats = leaf.FeatureStats
+	}
+
+	t.grow(t.tree.Split(nodeRef, c.Feature, c.PreSplit, c.PostSplit, c.Pivot))
+	node := t.tree.Get(nodeRef)
+	if split := node.GetSplit(); split != nil {
+		before := node.ByteSize()
+		split.Subset = c.Subset
+		split.FeatureStats = featureStats
+		t.grow(node.ByteSize() - before)
+	}
+}
+
+func (t *Tree) attemptSplit(leaf *internal.LeafNode, node *internal.Node, nodeRef int64, depth int, weight float64) *common.SplitAttemptInfo {
 	// Init split info
 	info := &common.SplitAttemptInfo{Weight: weight}
 
+	// Give up if the leaf has reached the maximum depth
+	if t.config.MaxDepth > 0 && depth >= t.config.MaxDepth {
+		info.Rejection = common.RejectMaxDepth
+		return info
+	}
+
 	// Init candidates, including a null result
 	candidates := make(internal.SplitCandidates, 1, len(leaf.FeatureStats)+1)
 
 	// Calculate a split candiate from each of the leaf stats
-	for name := range leaf.FeatureStats {
-		if c := leaf.EvaluateSplit(name, t.config.SplitCriterion, node); c != nil {
-			candidates = append(candidates, *c)
-		}
-	}
+	candidates = append(candidates, leaf.EvaluateSplits(t.config.SplitCriterion, t.config.BinaryCategoricalSplits, t.config.SplitWorkers, node)...)
 
 	// Sort candidates by merit, select first
-	sort.Stable(sort.Reverse(candidates))
+	candidates.Sort(t.config.Deterministic)
 	best := candidates[0]
 
-	// Calculate the gain between merits of the best and the second-best split
+	// Calculate the gain between merits of the best and the second-best split,
+	// or between the best and the null split in EFDT mode
 	meritGain := best.Merit
-	if len(candidates) > 1 {
+	if len(candidates) > 1 && !t.config.ReevaluateSplits {
 		meritGain -= candidates[1].Merit
 	}
 
+	// In deterministic mode, ties between the best candidates are
+	// broken by the tie threshold only
+	isTie := t.config.Deterministic && !t.config.ReevaluateSplits && candidates.IsTie()
+	if isTie {
+		meritGain = 0
+	}
+
 	// Update info
 	info.MeritGain = meritGain
 	info.Candidates = make([]common.SplitCandidateInfo, 0, len(candidates))
@@ -266,20 +683,177 @@ func (t *Tree) attemptSplit(leaf *internal.LeafNode, node *internal.Node, nodeRe
 		})
 	}
 
+	// Calculate the confidence bound
+	bound := t.config.SplitBound.Value(best.Range, t.config.SplitConfidence, weight)
+
 	// Give up if there is no merit gain
-	if meritGain <= 0 {
+	if meritGain <= 0 && !isTie {
+		t.removePoorFeatures(leaf, candidates, bound)
 		return info
 	}
-
-	// Calculate confidence interval + hoeffding bound
-	interval := math.Log(1.0 / t.config.SplitConfidence)
-	bound := math.Sqrt(best.Range * best.Range * interval * 0.5 / weight)
 	info.HoeffdingBound = bound
 
 	// Determine split
 	if meritGain > bound || bound < t.config.TieThreshold {
+		if info.Rejection = t.checkGrowth(&best); info.Rejection != common.RejectNone {
+			t.removePoorFeatures(leaf, candidates, bound)
+			return info
+		}
+
 		info.Success = true
-		t.tree.Split(nodeRef, best.Feature, best.PreSplit, best.PostSplit, best.Pivot)
+		t.split(nodeRef, &best)
+		return info
+	}
+
+	t.removePoorFeatures(leaf, candidates, bound)
+	return info
+}
+
+// checkGrowth checks a split candidate against the growth constraints.
+func (t *Tree) checkGrowth(c *internal.SplitCandidate) common.SplitRejection {
+	if t.config.MinMeritGain > 0 && c.Merit < t.config.MinMeritGain {
+		return common.RejectMinMeritGain
+	}
+	if t.config.MinLeafWeight > 0 && c.MinChildWeight() < t.config.MinLeafWeight {
+		return common.RejectMinLeafWeight
+	}
+	return common.RejectNone
+}
+
+// removePoorFeatures stops the leaf from observing features with a merit
+// that is confidently below the best candidate, if enabled. Candidates must
+// be sorted by merit.
+func (t *Tree) removePoorFeatures(leaf *internal.LeafNode, candidates internal.SplitCandidates, bound float64) {
+	if !t.config.RemovePoorFeatures {
+		return
+	}
+
+	best := candidates[0]
+	for _, c := range candidates[1:] {
+		if c.Feature != "" && best.Merit-c.Merit > bound {
+			t.grow(leaf.IgnoreFeature(c.Feature))
+		}
+	}
+}
+
+// sampleSubspace restricts a new leaf to a random subspace of features,
+// if enabled.
+func (t *Tree) sampleSubspace(leaf *internal.LeafNode) {
+	if t.config.FeatureSubspace == 0 || leaf.IsDisabled || len(leaf.FeatureStats) != 0 || len(leaf.IgnoredFeatures) != 0 {
+		return
+	}
+
+	for _, name := range common.SubspaceExclusions(t.rnd, t.tree.Model, t.tree.Target, t.config.FeatureSubspace) {
+		t.grow(leaf.IgnoreFeature(name))
+	}
+}
+
+// filterSplits appends the references of all split nodes along the path of
+// example x to dst.
+func (t *Tree) filterSplits(x core.Example, dst []int64) []int64 {
+	dst = t.tree.Path(x, t.tree.Root, dst)
+
+	n := 0
+	for _, nodeRef := range dst {
+		if t.tree.Get(nodeRef).GetSplit() != nil {
+			dst[n] = nodeRef
+			n++
+		}
+	}
+	return dst[:n]
+}
+
+// reevaluate passes example x to the given split nodes and re-evaluates
+// their split decisions periodically. Returns info about the last
+// re-evaluation, if any.
+func (t *Tree) reevaluate(x core.Example, weight float64, splitRefs []int64) *common.SplitAttemptInfo {
+	var info *common.SplitAttemptInfo
+	for _, nodeRef := range splitRefs {
+		node := t.tree.Get(nodeRef)
+		split := node.GetSplit()
+		if split == nil {
+			break
+		}
+
+		// Observe an example
+		t.grow(split.Observe(t.tree.Model, t.target, x, weight, t.config.NumericObserver, node))
+
+		// Check if a re-evaluation should be attempted
+		nodeWeight := node.Weight()
+		if int(nodeWeight-split.WeightAtLastEval) < t.config.ReevaluationPeriod {
+			continue
+		}
+
+		// Store new weight
+		split.WeightAtLastEval = nodeWeight
+
+		// Check if we have sufficient stats
+		if !node.IsSufficient() {
+			continue
+		}
+
+		// Re-evaluate, stop once the tree has been restructured
+		info = t.reevaluateSplit(split, node, nodeRef, nodeWeight)
+		if t.traceSplitAttempt(nodeRef, info); info.Success {
+			break
+		}
+	}
+	return info
+}
+
+func (t *Tree) reevaluateSplit(split *internal.SplitNode, node *internal.Node, nodeRef int64, weight float64) *common.SplitAttemptInfo {
+	// Init split info
+	info := &common.SplitAttemptInfo{Weight: weight, Reevaluation: true}
+
+	// Init candidates, including a null result
+	candidates := make(internal.SplitCandidates, 1, len(split.FeatureStats)+1)
+
+	// Calculate a split candiate from each of the observed stats
+	candidates = append(candidates, split.EvaluateSplits(t.config.SplitCriterion, t.config.BinaryCategoricalSplits, t.config.SplitWorkers, node)...)
+
+	// Sort candidates by merit, select first
+	candidates.Sort(t.config.Deterministic)
+	best := candidates[0]
+
+	// Calculate the gain between merits of the best and the current split
+	meritGain := best.Merit - split.Merit(t.config.SplitCriterion, node)
+
+	// Update info
+	info.MeritGain = meritGain
+	info.Candidates = make([]common.SplitCandidateInfo, 0, len(candidates))
+	for _, c := range candidates {
+		info.Candidates = append(info.Candidates, common.SplitCandidateInfo{
+			Feature: c.Feature,
+			Merit:   c.Merit,
+		})
+	}
+
+	// Give up if the current split is still the best or there is no merit gain
+	if best.Feature == split.Feature || meritGain <= 0 {
+		return info
+	}
+
+	// Calculate the confidence bound, based on the weight observed by the
+	// feature stats of the split
+	valueRange := t.config.SplitCriterion.Range(node.Stats)
+	bound := t.config.SplitBound.Value(valueRange, t.config.SplitConfidence, split.ObservedWeight())
+	info.HoeffdingBound = bound
+
+	// Determine restructure, revert to a leaf if the null split is best
+	if meritGain > bound || (bound < t.config.TieThreshold && meritGain > t.config.TieThreshold/2) {
+		// Reverting to a leaf is always permitted, a replacement split must
+		// satisfy the growth constraints
+		if best.Feature != "" {
+			if info.Rejection = t.checkGrowth(&best); info.Rejection != common.RejectNone {
+				return info
+			}
+		}
+
+		info.Success = true
+		t.grow(t.tree.Revert(nodeRef))
+		if best.Feature != "" {
+			t.split(nodeRef, &best)
+		}
 	}
 	return info
 }
@@ -2,17 +2,46 @@ package hoeffding_test
 
 import (
 	"bytes"
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"testing"
 
+	"github.com/bsm/reason/classification"
 	"github.com/bsm/reason/classification/eval"
+	"github.com/bsm/reason/classification/ftrl"
 	"github.com/bsm/reason/classification/hoeffding"
+	"github.com/bsm/reason/classification/hoeffding/internal"
 	common "github.com/bsm/reason/common/hoeffding"
 	"github.com/bsm/reason/core"
 	"github.com/bsm/reason/testdata"
+	"github.com/gogo/protobuf/proto"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 )
 
+type traceRecorder []*common.Trace
+
+func (r *traceRecorder) Trace(e *common.Trace) { *r = append(*r, e) }
+
+func (r traceRecorder) First(kind common.TraceKind) *common.Trace {
+	for _, e := range r {
+		if e.Kind == kind {
+			return e
+		}
+	}
+	return nil
+}
+
+// withoutByteSize strips the estimated byte size from tree info, it is
+// covered by dedicated specs.
+func withoutByteSize(info *common.TreeInfo) *common.TreeInfo {
+	info.ByteSize = 0
+	return info
+}
+
 var _ = Describe("Tree", func() {
 
 	var train = func(n int) (*hoeffding.Tree, *core.Model, []core.Example) {
@@ -32,13 +61,239 @@ var _ = Describe("Tree", func() {
 		return tree, model, examples
 	}
 
+	var driftModel = core.NewModel(
+		core.NewCategoricalFeature("a", []string{"x", "y"}),
+		core.NewCategoricalFeature("b", []string{"x", "y"}),
+		core.NewCategoricalFeature("c", []string{"x", "y"}),
+		core.NewCategoricalFeature("target", []string{"x", "y"}),
+	)
+	var driftStream = func(rnd *rand.Rand, n int, concept string) []core.Example {
+		examples := make([]core.Example, 0, n)
+		for i := 0; i < n; i++ {
+			x := core.MapExample{}
+			for _, name := range []string{"a", "b", "c"} {
+				x[name] = "x"
+				if rnd.Intn(2) == 0 {
+					x[name] = "y"
+				}
+			}
+			x["target"] = x[concept]
+			examples = append(examples, x)
+		}
+		return examples
+	}
+
+	It("should route missing values", func() {
+		rnd := rand.New(rand.NewSource(1))
+		tree, err := hoeffding.New(driftModel, "target", &hoeffding.Config{
+			Config: common.Config{GracePeriod: 50},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, x := range driftStream(rnd, 1000, "a") {
+			tree.Train(x, 1.0)
+		}
+		Expect(withoutByteSize(tree.Info())).To(Equal(&common.TreeInfo{NumNodes: 3, NumLearning: 2, MaxDepth: 2}))
+
+		for _, x := range driftStream(rnd, 500, "a") {
+			delete(x.(core.MapExample), "a")
+			tree.Train(x, 1.0)
+		}
+		Expect(withoutByteSize(tree.Info())).To(Equal(&common.TreeInfo{NumNodes: 3, NumLearning: 2, MaxDepth: 2}))
+		Expect(tree.Predict(nil, core.MapExample{"b": "x"})).To(HaveLen(2))
+
+		b := new(bytes.Buffer)
+		Expect(tree.WriteText(b)).To(Equal(int64(b.Len())))
+		Expect(b.String()).To(ContainSubstring("\ta = y [weight:1006]"))
+	})
+
+	It("should explain", func() {
+		rnd := rand.New(rand.NewSource(1))
+		tree, err := hoeffding.New(driftModel, "target", &hoeffding.Config{
+			Config: common.Config{GracePeriod: 50},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, x := range driftStream(rnd, 1000, "a") {
+			tree.Train(x, 1.0)
+		}
+
+		x := core.MapExample{"a": "y", "b": "x"}
+		exp := tree.Explain(x)
+		Expect(exp.Stop).To(Equal(common.StopLeaf))
+		Expect(exp.Steps).To(HaveLen(2))
+		Expect(exp.Steps[0].DecisionStep).To(Equal(common.DecisionStep{Weight: 50, Feature: "a", Condition: "a = y", Value: "y"}))
+		Expect(exp.Steps[1].DecisionStep).To(Equal(common.DecisionStep{Weight: 506}))
+
+		predictions := tree.Predict(nil, x)
+		Expect(exp.Steps[0].Prediction).To(Equal(predictions[0]))
+		Expect(exp.Steps[1].Prediction).To(Equal(predictions[1]))
+
+		exp = tree.Explain(core.MapExample{"b": "x"})
+		Expect(exp.Stop).To(Equal(common.StopLeaf))
+		Expect(exp.Steps).To(HaveLen(2))
+		Expect(exp.Steps[0].Missing).To(BeTrue())
+	})
+
+	It("should calculate contributions", func() {
+		rnd := rand.New(rand.NewSource(1))
+		tree, err := hoeffding.New(driftModel, "target", &hoeffding.Config{
+			Config: common.Config{GracePeriod: 50},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, x := range driftStream(rnd, 5000, "a") {
+			if x.(core.MapExample)["b"] == "x" && rnd.Intn(4) == 0 {
+				x.(core.MapExample)["target"] = "x"
+			}
+			tree.Train(x, 1.0)
+		}
+
+		x := core.MapExample{"a": "y", "b": "x"}
+		res := tree.Contributions(x)
+		Expect(res.Features).To(HaveLen(2))
+		Expect(res.Features["a"][1]).To(BeNumerically(">", 0))
+		Expect(res.Features["b"][1]).To(BeNumerically("<", 0))
+
+		best := tree.Predict(nil, x).Best()
+		for j := range res.Output {
+			Expect(res.Output[j]).To(BeNumerically("~", best.P(core.Category(j)), 1e-9))
+			Expect(res.Expected[j] + res.Features["a"][j] + res.Features["b"][j]).To(BeNumerically("~", res.Output[j], 1e-9))
+		}
+	})
+
+	It("should snapshot", func() {
+		model := core.NewModel(
+			core.NewCategoricalFeature("a", []string{"x", "y", "z"}),
+			core.NewNumericalFeature("n"),
+			core.NewCategoricalFeature("target", []string{"x", "y"}),
+		)
+		rnd := rand.New(rand.NewSource(1))
+		stream := func(n int) []core.Example {
+			examples := make([]core.Example, 0, n)
+			for i := 0; i < n; i++ {
+				x := core.MapExample{"a": []string{"x", "y", "z"}[rnd.Intn(3)], "n": rnd.Float64(), "target": "x"}
+				if x["a"] != "x" && x["n"].(float64) > 0.3 {
+					x["target"] = "y"
+				}
+				if rnd.Intn(10) == 0 {
+					delete(x, "a")
+				}
+				examples = append(examples, x)
+			}
+			return examples
+		}
+
+		tree, err := hoeffding.New(model, "target", &hoeffding.Config{
+			Config: common.Config{GracePeriod: 50},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, x := range stream(5000) {
+			tree.Train(x, 1.0)
+		}
+		Expect(tree.Info().MaxDepth).To(BeNumerically(">", 2))
+
+		snap, err := tree.Snapshot()
+		Expect(err).NotTo(HaveOccurred())
+
+		b := new(bytes.Buffer)
+		Expect(snap.WriteTo(b)).To(Equal(int64(b.Len())))
+
+		loaded, err := hoeffding.LoadSnapshot(b)
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, x := range stream(1000) {
+			expected := *tree.Predict(nil, x).Best()
+			Expect(snap.Predict(x)).To(Equal(expected))
+			Expect(loaded.Predict(x)).To(Equal(expected))
+		}
+
+		x := core.MapExample{"a": "y", "n": 0.5}
+		Expect(testing.AllocsPerRun(100, func() { snap.Predict(x) })).To(BeZero())
+
+		_, err = hoeffding.LoadSnapshot(new(bytes.Buffer))
+		Expect(err).To(MatchError("hoeffding: invalid snapshot"))
+
+		// corrupted snapshots are rejected at load time
+		data := new(bytes.Buffer)
+		Expect(snap.WriteTo(data)).To(Equal(int64(data.Len())))
+		for _, corrupt := range []func(*internal.Snapshot){
+			func(s *internal.Snapshot) { s.Pivots = s.Pivots[1:] },
+			func(s *internal.Snapshot) { s.SubsetOffsets = s.SubsetOffsets[1:] },
+			func(s *internal.Snapshot) { s.ChildOffsets[1] = int64(len(s.Children) + 1) },
+			func(s *internal.Snapshot) { s.Children[0] = int64(len(s.Features)) },
+			func(s *internal.Snapshot) { s.Children[0] = 0 },
+			func(s *internal.Snapshot) { s.Features[0] = int64(len(s.FeatureNames)) },
+		} {
+			raw := new(internal.Snapshot)
+			Expect(proto.Unmarshal(data.Bytes(), raw)).To(Succeed())
+			corrupt(raw)
+
+			b := new(bytes.Buffer)
+			Expect(raw.WriteTo(b)).To(Equal(int64(b.Len())))
+			_, err = hoeffding.LoadSnapshot(b)
+			Expect(err).To(HaveOccurred())
+		}
+		// naive-bayes predictions require feature stats
+		nb, err := hoeffding.New(model, "target", &hoeffding.Config{LeafPrediction: hoeffding.LeafPredictionNBAdaptive})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = nb.Snapshot()
+		Expect(err).To(MatchError("hoeffding: snapshots require majority-class leaf predictions"))
+	})
+
+	It("should publish copy-on-write", func() {
+		rnd := rand.New(rand.NewSource(1))
+		tree, err := hoeffding.New(driftModel, "target", &hoeffding.Config{
+			Config: common.Config{GracePeriod: 50, PublishPeriod: 100},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		examples := driftStream(rnd, 1000, "a")
+		for _, x := range examples[:99] {
+			tree.Train(x, 1.0)
+		}
+		Expect(withoutByteSize(tree.Info())).To(Equal(&common.TreeInfo{NumNodes: 1, NumLearning: 1, MaxDepth: 1}))
+		Expect(tree.Predict(nil, examples[0]).Best().Weight()).To(Equal(0.0))
+
+		tree.Train(examples[99], 1.0)
+		Expect(withoutByteSize(tree.Info())).To(Equal(&common.TreeInfo{NumNodes: 3, NumLearning: 2, MaxDepth: 2}))
+		Expect(tree.Predict(nil, examples[0]).Best().Weight()).To(Equal(49.0))
+
+		for _, x := range examples[100:150] {
+			tree.Train(x, 1.0)
+		}
+		Expect(tree.Predict(nil, examples[0]).Best().Weight()).To(Equal(49.0))
+
+		tree.Publish()
+		Expect(tree.Predict(nil, examples[0]).Best().Weight()).To(Equal(73.0))
+
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+
+				for _, x := range examples {
+					tree.Predict(nil, x)
+					tree.Info()
+				}
+			}()
+		}
+		for _, x := range examples {
+			tree.Train(x, 1.0)
+		}
+		wg.Wait()
+	})
+
 	It("should dump/load", func() {
 		c := &hoeffding.Config{
 			Config: common.Config{GracePeriod: 50},
 		}
 
 		t1, _, examples := train(3000)
-		Expect(t1.Info()).To(Equal(&common.TreeInfo{NumNodes: 11, NumLearning: 9, MaxDepth: 3}))
+		Expect(withoutByteSize(t1.Info())).To(Equal(&common.TreeInfo{NumNodes: 11, NumLearning: 9, MaxDepth: 3}))
 		Expect(t1.Predict(nil, examples[4001]).Best().P(0)).To(BeNumerically("~", 0.273, 0.001))
 
 		b1 := new(bytes.Buffer)
@@ -46,13 +301,13 @@ var _ = Describe("Tree", func() {
 
 		t2, err := hoeffding.Load(b1, c)
 		Expect(err).NotTo(HaveOccurred())
-		Expect(t2.Info()).To(Equal(&common.TreeInfo{NumNodes: 11, NumLearning: 9, MaxDepth: 3}))
+		Expect(withoutByteSize(t2.Info())).To(Equal(&common.TreeInfo{NumNodes: 11, NumLearning: 9, MaxDepth: 3}))
 		Expect(t2.Predict(nil, examples[4001]).Best().P(0)).To(BeNumerically("~", 0.273, 0.001))
 	})
 
 	It("should prune", func() {
 		t, _, _ := train(3000)
-		Expect(t.Info()).To(Equal(&common.TreeInfo{
+		Expect(withoutByteSize(t.Info())).To(Equal(&common.TreeInfo{
 			NumNodes:    11,
 			NumLearning: 9,
 			NumDisabled: 0,
@@ -60,7 +315,7 @@ var _ = Describe("Tree", func() {
 		}))
 
 		t.Prune(5)
-		Expect(t.Info()).To(Equal(&common.TreeInfo{
+		Expect(withoutByteSize(t.Info())).To(Equal(&common.TreeInfo{
 			NumNodes:    11,
 			NumLearning: 5,
 			NumDisabled: 4,
@@ -68,6 +323,82 @@ var _ = Describe("Tree", func() {
 		}))
 	})
 
+	It("should evaluate splits concurrently", func() {
+		examples := driftStream(rand.New(rand.NewSource(1)), 5000, "a")
+
+		var dumps []string
+		for _, workers := range []int{1, 4} {
+			tree, err := hoeffding.New(driftModel, "target", &hoeffding.Config{
+				Config: common.Config{GracePeriod: 50, SplitWorkers: workers},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, x := range examples {
+				tree.Train(x, 1.0)
+			}
+
+			b := new(bytes.Buffer)
+			Expect(tree.WriteText(b)).To(Equal(int64(b.Len())))
+			dumps = append(dumps, b.String())
+		}
+		Expect(dumps[0]).To(ContainSubstring("\ta = x"))
+		Expect(dumps[1]).To(Equal(dumps[0]))
+	})
+
+	It("should train deterministically", func() {
+		model := testdata.ClassificationModel()
+		examples := testdata.ClassificationData()
+
+		var dumps [][]byte
+		for i := 0; i < 2; i++ {
+			tree, err := hoeffding.New(model, "play", &hoeffding.Config{
+				Config: common.Config{GracePeriod: 20, Deterministic: true, SplitWorkers: 4},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			for j := 0; j < 100; j++ {
+				for _, x := range examples {
+					tree.Train(x, 1.0)
+				}
+			}
+
+			b := new(bytes.Buffer)
+			Expect(tree.WriteTo(b)).To(Equal(int64(b.Len())))
+			dumps = append(dumps, b.Bytes())
+		}
+		Expect(dumps[1]).To(Equal(dumps[0]))
+	})
+
+	It("should break ties deterministically", func() {
+		model := core.NewModel(
+			core.NewCategoricalFeature("a", []string{"x", "y"}),
+			core.NewCategoricalFeature("b", []string{"x", "y"}),
+			core.NewCategoricalFeature("target", []string{"x", "y"}),
+		)
+		rnd := rand.New(rand.NewSource(1))
+
+		examples := make([]core.Example, 0, 5000)
+		for i := 0; i < 5000; i++ {
+			v := "x"
+			if rnd.Intn(2) == 0 {
+				v = "y"
+			}
+			examples = append(examples, core.MapExample{"a": v, "b": v, "target": v})
+		}
+
+		for i := 0; i < 10; i++ {
+			tree, err := hoeffding.New(model, "target", &hoeffding.Config{
+				Config: common.Config{GracePeriod: 50, Deterministic: true},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, x := range examples {
+				tree.Train(x, 1.0)
+			}
+			Expect(tree.Explain(examples[0]).Steps[0].Feature).To(Equal("a"))
+		}
+	})
+
 	It("should write TXT", func() {
 		t, _, _ := train(3000)
 
@@ -90,10 +421,772 @@ var _ = Describe("Tree", func() {
 		Expect(s).To(ContainSubstring(`N_0 [label="c5 = v1\nweight: 644"];`))
 	})
 
+	DescribeTable("should predict leaves",
+		func(mode hoeffding.LeafPrediction, expP0, expP1 float64) {
+			tree, err := hoeffding.New(testdata.ClassificationModel(), "play", &hoeffding.Config{
+				Config:         common.Config{GracePeriod: 100},
+				LeafPrediction: mode,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, x := range testdata.ClassificationData() {
+				tree.Train(x, 1.0)
+			}
+
+			prediction := tree.Predict(nil, core.MapExample{
+				"outlook":  "sunny",
+				"temp":     "cool",
+				"humidity": "high",
+				"windy":    "true",
+			}).Best()
+			Expect(prediction.P(0)).To(BeNumerically("~", expP0, 0.001))
+			Expect(prediction.P(1)).To(BeNumerically("~", expP1, 0.001))
+		},
+
+		Entry("majority class", hoeffding.LeafPredictionMajorityClass, 0.643, 0.357),
+		Entry("naive bayes", hoeffding.LeafPredictionNaiveBayes, 0.409, 0.591),
+		Entry("adaptive", hoeffding.LeafPredictionNBAdaptive, 0.643, 0.357),
+	)
+
+	It("should adapt to drift", func() {
+		rnd := rand.New(rand.NewSource(1))
+		tree, err := hoeffding.New(driftModel, "target", &hoeffding.Config{
+			Config:   common.Config{GracePeriod: 50},
+			Adaptive: true,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, x := range driftStream(rnd, 2000, "a") {
+			tree.Train(x, 1.0)
+		}
+		Expect(withoutByteSize(tree.Info())).To(Equal(&common.TreeInfo{NumNodes: 3, NumLearning: 2, MaxDepth: 2}))
+
+		// drift, until an alternate is grown
+		drifted := driftStream(rnd, 5000, "b")
+		for len(drifted) != 0 && tree.Info().NumAlternates == 0 {
+			tree.Train(drifted[0], 1.0)
+			drifted = drifted[1:]
+		}
+		Expect(tree.Info().NumAlternates).To(Equal(1))
+
+		// alternates must survive dump/load
+		buf := new(bytes.Buffer)
+		Expect(tree.WriteTo(buf)).To(Equal(int64(buf.Len())))
+		tree, err = hoeffding.Load(buf, &hoeffding.Config{
+			Config:   common.Config{GracePeriod: 50},
+			Adaptive: true,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tree.Info().NumAlternates).To(Equal(1))
+
+		for _, x := range drifted {
+			tree.Train(x, 1.0)
+		}
+
+		b := new(bytes.Buffer)
+		Expect(tree.WriteText(b)).To(Equal(int64(b.Len())))
+		Expect(b.String()).To(ContainSubstring("\tb = x"))
+		Expect(b.String()).NotTo(ContainSubstring("\ta = x"))
+
+		accuracy := eval.NewAccuracy()
+		for _, x := range driftStream(rnd, 1000, "b") {
+			predicted, _ := tree.Predict(nil, x).Best().Top()
+			accuracy.Record(predicted, driftModel.Feature("target").Category(x), 1.0)
+		}
+		Expect(accuracy.Accuracy()).To(BeNumerically(">", 0.99))
+	})
+
+	It("should adapt reproducibly", func() {
+		dump := func() []byte {
+			tree, err := hoeffding.New(driftModel, "target", &hoeffding.Config{
+				Config:   common.Config{GracePeriod: 50, Deterministic: true},
+				Adaptive: true,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			// alternating targets produce tied predictions
+			for i := 0; i < 1000; i++ {
+				x := core.MapExample{"a": "x", "b": "x", "c": "x", "target": "x"}
+				if i%2 == 1 {
+					x["target"] = "y"
+				}
+				tree.Train(x, 1.0)
+			}
+
+			buf := new(bytes.Buffer)
+			Expect(tree.WriteTo(buf)).To(Equal(int64(buf.Len())))
+			return buf.Bytes()
+		}
+
+		exp := dump()
+		for i := 0; i < 10; i++ {
+			Expect(dump()).To(Equal(exp))
+		}
+	})
+
+	It("should compact", func() {
+		rnd := rand.New(rand.NewSource(1))
+		tree, err := hoeffding.New(driftModel, "target", &hoeffding.Config{
+			Config:   common.Config{GracePeriod: 50},
+			Adaptive: true,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, x := range driftStream(rnd, 2000, "a") {
+			tree.Train(x, 1.0)
+		}
+		for _, x := range driftStream(rnd, 5000, "b") {
+			tree.Train(x, 1.0)
+		}
+
+		info := tree.Info()
+		Expect(withoutByteSize(tree.Info())).To(Equal(&common.TreeInfo{NumNodes: 3, NumLearning: 2, MaxDepth: 2}))
+
+		examples := driftStream(rnd, 100, "b")
+		predictions := make([]classification.Predictions, 0, len(examples))
+		for _, x := range examples {
+			predictions = append(predictions, tree.Predict(nil, x))
+		}
+
+		b1 := new(bytes.Buffer)
+		Expect(tree.WriteTo(b1)).To(Equal(int64(b1.Len())))
+		Expect(b1.Len()).To(Equal(1084))
+
+		Expect(tree.Compact()).To(Equal(3))
+		Expect(tree.Compact()).To(Equal(0))
+		Expect(tree.Info().ByteSize).To(BeNumerically("<", info.ByteSize))
+		Expect(withoutByteSize(tree.Info())).To(Equal(withoutByteSize(info)))
+		for i, x := range examples {
+			Expect(tree.Predict(nil, x)).To(Equal(predictions[i]))
+		}
+
+		b2 := new(bytes.Buffer)
+		Expect(tree.WriteTo(b2)).To(Equal(int64(b2.Len())))
+		Expect(b2.Len()).To(Equal(900))
+	})
+
+	DescribeTable("should limit growth",
+		func(config common.Config, expInfo *common.TreeInfo, expRejections map[common.SplitRejection]int) {
+			rnd := rand.New(rand.NewSource(1))
+			tree, err := hoeffding.New(driftModel, "target", &hoeffding.Config{Config: config})
+			Expect(err).NotTo(HaveOccurred())
+
+			rejections := make(map[common.SplitRejection]int)
+			for _, x := range driftStream(rnd, 5000, "b") {
+				if x := x.(core.MapExample); x["a"] == "y" {
+					x["target"] = "y"
+				}
+				if info := tree.Train(x, 1.0); info != nil && info.Rejection != common.RejectNone {
+					rejections[info.Rejection]++
+				}
+			}
+			Expect(withoutByteSize(tree.Info())).To(Equal(expInfo))
+			Expect(rejections).To(Equal(expRejections))
+		},
+
+		Entry("unlimited", common.Config{GracePeriod: 50},
+			&common.TreeInfo{NumNodes: 5, NumLearning: 3, MaxDepth: 3},
+			map[common.SplitRejection]int{}),
+		Entry("max depth", common.Config{GracePeriod: 50, MaxDepth: 2},
+			&common.TreeInfo{NumNodes: 3, NumLearning: 2, MaxDepth: 2},
+			map[common.SplitRejection]int{common.RejectMaxDepth: 17}),
+		Entry("min leaf weight", common.Config{GracePeriod: 50, MinLeafWeight: 1500},
+			&common.TreeInfo{NumNodes: 3, NumLearning: 2, MaxDepth: 2},
+			map[common.SplitRejection]int{common.RejectMinLeafWeight: 17}),
+		Entry("min merit gain", common.Config{GracePeriod: 50, MinMeritGain: 0.4},
+			&common.TreeInfo{NumNodes: 1, NumLearning: 1, MaxDepth: 1},
+			map[common.SplitRejection]int{common.RejectMinMeritGain: 36}),
+	)
+
+	DescribeTable("should sample feature subspaces",
+		func(config common.Config, expFeatures []string) {
+			config.GracePeriod = 50
+			tree, err := hoeffding.New(driftModel, "target", &hoeffding.Config{Config: config})
+			Expect(err).NotTo(HaveOccurred())
+
+			rnd := rand.New(rand.NewSource(1))
+			for _, x := range driftStream(rnd, 5000, "b") {
+				tree.Train(x, 1.0)
+			}
+
+			var features []string
+			for _, fi := range tree.FeatureImportances() {
+				features = append(features, fi.Feature)
+			}
+			Expect(features).To(Equal(expFeatures))
+		},
+
+		Entry("all features", common.Config{}, []string{"b"}),
+		Entry("one feature", common.Config{FeatureSubspace: 1, Seed: 1}, []string{"c"}),
+		Entry("one feature (reseeded)", common.Config{FeatureSubspace: 1, Seed: 2}, []string{"b"}),
+		Entry("two features", common.Config{FeatureSubspace: 2, Seed: 4}, []string{"b"}),
+	)
+
+	It("should weight classes", func() {
+		model := core.NewModel(
+			core.NewCategoricalFeature("a", []string{"x", "y"}),
+			core.NewCategoricalFeature("b", []string{"x", "y"}),
+			core.NewCategoricalFeature("target", []string{"x", "y"}),
+		)
+		rnd := rand.New(rand.NewSource(1))
+		examples := make([]core.Example, 0, 6000)
+		for i := 0; i < 6000; i++ {
+			x := core.MapExample{"a": "x", "b": "x", "target": "x"}
+			if rnd.Intn(2) == 0 {
+				x["a"] = "y"
+			}
+			if rnd.Intn(2) == 0 {
+				x["b"] = "y"
+			}
+			p := 0.005
+			if x["a"] == "x" {
+				p = 0.05
+			}
+			if rnd.Float64() < p {
+				x["target"] = "y"
+			}
+			examples = append(examples, x)
+		}
+		predict := func(tree *hoeffding.Tree, a string) float64 {
+			return tree.Predict(nil, core.MapExample{"a": a}).Best().P(1)
+		}
+
+		_, err := hoeffding.New(model, "target", &hoeffding.Config{ClassWeights: map[string]float64{"z": 2}})
+		Expect(err).To(MatchError(`hoeffding: unknown class "z"`))
+		_, err = hoeffding.New(model, "target", &hoeffding.Config{ClassWeights: map[string]float64{"y": -1}})
+		Expect(err).To(MatchError(`hoeffding: invalid weight -1 for class "y"`))
+
+		t1, err := hoeffding.New(model, "target", &hoeffding.Config{
+			Config: common.Config{GracePeriod: 50},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		t2, err := hoeffding.New(model, "target", &hoeffding.Config{
+			Config:       common.Config{GracePeriod: 50},
+			ClassWeights: map[string]float64{"y": 20},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, x := range examples[:5000] {
+			t1.Train(x, 1.0)
+			t2.Train(x, 1.0)
+		}
+		Expect(predict(t1, "x")).To(BeNumerically("~", 0.034, 0.001))
+		Expect(predict(t1, "y")).To(BeNumerically("~", 0.005, 0.001))
+		Expect(predict(t2, "x")).To(BeNumerically("~", 0.495, 0.001))
+		Expect(predict(t2, "y")).To(BeNumerically("~", 0.086, 0.001))
+
+		buf := new(bytes.Buffer)
+		Expect(t2.WriteTo(buf)).To(Equal(int64(buf.Len())))
+		t3, err := hoeffding.Load(buf, &hoeffding.Config{
+			Config: common.Config{GracePeriod: 50},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, x := range examples[5000:] {
+			t2.Train(x, 1.0)
+			t3.Train(x, 1.0)
+		}
+		Expect(predict(t3, "x")).To(Equal(predict(t2, "x")))
+		Expect(predict(t3, "y")).To(Equal(predict(t2, "y")))
+	})
+
+	It("should predict leaf identifiers", func() {
+		rnd := rand.New(rand.NewSource(1))
+		tree, err := hoeffding.New(driftModel, "target", &hoeffding.Config{
+			Config: common.Config{GracePeriod: 50, LeafBuckets: 4},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		ref, depth := tree.PredictLeaf(core.MapExample{"a": "x"})
+		Expect(ref).To(Equal(int64(1)))
+		Expect(depth).To(Equal(1))
+
+		for _, x := range driftStream(rnd, 1000, "a") {
+			tree.Train(x, 1.0)
+		}
+		Expect(withoutByteSize(tree.Info())).To(Equal(&common.TreeInfo{NumNodes: 3, NumLearning: 2, MaxDepth: 2}))
+
+		refX, depth := tree.PredictLeaf(core.MapExample{"a": "x"})
+		Expect(depth).To(Equal(2))
+		refY, depth := tree.PredictLeaf(core.MapExample{"a": "y", "b": "x"})
+		Expect(depth).To(Equal(2))
+		Expect(refX).NotTo(Equal(refY))
+
+		feat := tree.LeafFeature("leaf")
+		Expect(feat.NumCategories()).To(Equal(4))
+		Expect(feat.Category(core.MapExample{"leaf": refX})).To(Equal(core.Category(refX)))
+
+		opt, err := ftrl.New(core.NewModel(feat, driftModel.Feature("target")), "target", nil)
+		Expect(err).NotTo(HaveOccurred())
+		for _, x := range driftStream(rnd, 1000, "a") {
+			ref, _ := tree.PredictLeaf(x)
+			opt.Train(core.MapExample{"leaf": ref, "target": x.(core.MapExample)["target"]}, 1.0)
+		}
+		Expect(opt.Predict(core.MapExample{"leaf": refX})).To(BeNumerically("~", 0.104, 0.001))
+		Expect(opt.Predict(core.MapExample{"leaf": refY})).To(BeNumerically("~", 0.900, 0.001))
+
+		small, err := hoeffding.New(driftModel, "target", &hoeffding.Config{
+			Config: common.Config{LeafBuckets: 1},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		ref, depth = small.PredictLeaf(core.MapExample{"a": "x"})
+		Expect(ref).To(Equal(int64(-1)))
+		Expect(depth).To(Equal(1))
+		Expect(small.LeafFeature("leaf").Category(core.MapExample{"leaf": ref})).To(Equal(core.NoCategory))
+	})
+
+	It("should trace", func() {
+		rnd := rand.New(rand.NewSource(1))
+		tracer := new(traceRecorder)
+		tree, err := hoeffding.New(driftModel, "target", &hoeffding.Config{
+			Config:   common.Config{GracePeriod: 50, Tracer: tracer},
+			Adaptive: true,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tree.Traces()).To(BeNil())
+
+		for _, x := range driftStream(rnd, 2000, "a") {
+			tree.Train(x, 1.0)
+		}
+		for _, x := range driftStream(rnd, 5000, "b") {
+			tree.Train(x, 1.0)
+		}
+
+		kinds := make(map[common.TraceKind]int)
+		for _, e := range *tracer {
+			kinds[e.Kind]++
+		}
+		Expect(kinds).To(Equal(map[common.TraceKind]int{
+			common.TraceSplitAttempt:  10,
+			common.TraceSplit:         2,
+			common.TraceDriftDetected: 1,
+			common.TraceDriftReset:    1,
+		}))
+
+		split := tracer.First(common.TraceSplit)
+		Expect(split).NotTo(BeNil())
+		Expect(split.NodeRef).To(Equal(int64(1)))
+		Expect(split.Feature).To(Equal("a"))
+		Expect(split.SplitAttempt.Success).To(BeTrue())
+	})
+
+	It("should not block when traces are not consumed", func() {
+		rnd := rand.New(rand.NewSource(1))
+		stream := driftStream(rnd, 2000, "a")
+		for _, x := range stream {
+			x.(core.MapExample)["target"] = []string{"x", "y"}[rnd.Intn(2)]
+		}
+
+		tree, err := hoeffding.New(driftModel, "target", &hoeffding.Config{
+			Config: common.Config{GracePeriod: 1, EnableTracing: true},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, x := range stream {
+			tree.Train(x, 1.0)
+		}
+		Expect(tree.Traces()).To(HaveLen(common.DefaultTraceBufferSize))
+
+		tracer := common.NewChanTracer(3)
+		tree, err = hoeffding.New(driftModel, "target", &hoeffding.Config{
+			Config: common.Config{GracePeriod: 1, Tracer: tracer},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, x := range stream {
+			tree.Train(x, 1.0)
+		}
+		Expect(tree.Traces()).To(HaveLen(3))
+		Expect(tracer.Dropped()).To(Equal(uint64(1996)))
+	})
+
+	It("should re-evaluate splits", func() {
+		rnd := rand.New(rand.NewSource(1))
+		tree, err := hoeffding.New(driftModel, "target", &hoeffding.Config{
+			Config: common.Config{GracePeriod: 50, ReevaluateSplits: true},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, x := range driftStream(rnd, 1000, "a") {
+			tree.Train(x, 1.0)
+		}
+		Expect(withoutByteSize(tree.Info())).To(Equal(&common.TreeInfo{NumNodes: 3, NumLearning: 2, MaxDepth: 2}))
+
+		var restructured *common.SplitAttemptInfo
+		for _, x := range driftStream(rnd, 3000, "b") {
+			if info := tree.Train(x, 1.0); info != nil && info.Reevaluation && info.Success && restructured == nil {
+				restructured = info
+			}
+		}
+		Expect(restructured).NotTo(BeNil())
+		Expect(restructured.Candidates[0].Feature).To(Equal("b"))
+		Expect(restructured.HoeffdingBound).To(BeNumerically("<", 0.065))
+		Expect(restructured.MeritGain).To(BeNumerically(">", 0.025))
+
+		b := new(bytes.Buffer)
+		Expect(tree.WriteText(b)).To(Equal(int64(b.Len())))
+		Expect(b.String()).To(ContainSubstring("\n\tb = x"))
+		Expect(b.String()).NotTo(ContainSubstring("\n\ta = x"))
+	})
+
+	It("should apply growth constraints to re-evaluated splits", func() {
+		rnd := rand.New(rand.NewSource(1))
+		tree, err := hoeffding.New(driftModel, "target", &hoeffding.Config{
+			Config: common.Config{GracePeriod: 50, ReevaluateSplits: true, MinMeritGain: 0.3},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, x := range driftStream(rnd, 1000, "a") {
+			tree.Train(x, 1.0)
+		}
+		Expect(withoutByteSize(tree.Info())).To(Equal(&common.TreeInfo{NumNodes: 3, NumLearning: 2, MaxDepth: 2}))
+
+		var rejected *common.SplitAttemptInfo
+		for _, x := range driftStream(rnd, 3000, "b") {
+			if info := tree.Train(x, 1.0); info != nil && info.Reevaluation && info.Rejection != common.RejectNone && rejected == nil {
+				rejected = info
+			}
+		}
+		Expect(rejected).NotTo(BeNil())
+		Expect(rejected.Success).To(BeFalse())
+		Expect(rejected.Rejection).To(Equal(common.RejectMinMeritGain))
+		Expect(rejected.Candidates[0].Feature).To(Equal("b"))
+		Expect(rejected.Candidates[0].Merit).To(BeNumerically("<", 0.3))
+		Expect(rejected.MeritGain).To(BeNumerically(">", rejected.HoeffdingBound))
+	})
+
+	DescribeTable("should observe skewed numerical features",
+		func(observer common.NumericObserver, expAccuracy float64) {
+			model := core.NewModel(
+				core.NewNumericalFeature("v"),
+				core.NewCategoricalFeature("target", []string{"lo", "hi"}),
+			)
+			rnd := rand.New(rand.NewSource(1))
+			stream := func(n int) []core.Example {
+				examples := make([]core.Example, 0, n)
+				for i := 0; i < n; i++ {
+					v := math.Exp(rnd.NormFloat64() * 3)
+					if v < 0.5 {
+						examples = append(examples, core.MapExample{"v": v, "target": "lo"})
+					} else {
+						examples = append(examples, core.MapExample{"v": v, "target": "hi"})
+					}
+				}
+				return examples
+			}
+
+			tree, err := hoeffding.New(model, "target", &hoeffding.Config{
+				Config: common.Config{GracePeriod: 100, NumericObserver: observer},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, x := range stream(3000) {
+				tree.Train(x, 1.0)
+			}
+
+			accuracy := eval.NewAccuracy()
+			for _, x := range stream(1000) {
+				predicted, _ := tree.Predict(nil, x).Best().Top()
+				accuracy.Record(predicted, model.Feature("target").Category(x), 1.0)
+			}
+			Expect(accuracy.Accuracy()).To(BeNumerically("~", expAccuracy, 0.001))
+		},
+
+		Entry("gaussian", common.NumericObserverGaussian, 0.580),
+		Entry("E-BST", common.NumericObserverEBST, 0.999),
+		Entry("quantile sketch", common.NumericObserverQuantileSketch, 0.986),
+	)
+
+	DescribeTable("should prune by promise",
+		func(pruneByPromise bool, expInfo *common.TreeInfo) {
+			rnd := rand.New(rand.NewSource(1))
+			stream := func(n int) []core.Example {
+				examples := make([]core.Example, 0, n)
+				for _, x := range driftStream(rnd, n, "b") {
+					if rnd.Intn(4) != 0 {
+						x.(core.MapExample)["a"] = "x"
+					}
+					if x.(core.MapExample)["a"] == "x" {
+						x.(core.MapExample)["target"] = "x"
+					}
+					examples = append(examples, x)
+				}
+				return examples
+			}
+
+			tree, err := hoeffding.New(driftModel, "target", &hoeffding.Config{
+				Config: common.Config{GracePeriod: 50, PruneByPromise: pruneByPromise},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, x := range stream(500) {
+				tree.Train(x, 1.0)
+			}
+			Expect(withoutByteSize(tree.Info())).To(Equal(&common.TreeInfo{NumNodes: 3, NumLearning: 2, MaxDepth: 2}))
+
+			tree.Prune(1)
+			for _, x := range stream(2000) {
+				tree.Train(x, 1.0)
+			}
+			Expect(withoutByteSize(tree.Info())).To(Equal(expInfo))
+		},
+
+		Entry("by weight", false, &common.TreeInfo{NumNodes: 3, NumLearning: 1, NumDisabled: 1, MaxDepth: 2}),
+		Entry("by promise", true, &common.TreeInfo{NumNodes: 5, NumLearning: 2, NumDisabled: 1, MaxDepth: 3}),
+	)
+
+	It("should remove poor features", func() {
+		train := func(removePoorFeatures bool) *common.TreeInfo {
+			rnd := rand.New(rand.NewSource(1))
+			tree, err := hoeffding.New(driftModel, "target", &hoeffding.Config{
+				Config: common.Config{GracePeriod: 50, RemovePoorFeatures: removePoorFeatures},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, x := range driftStream(rnd, 2000, "a") {
+				x.(core.MapExample)["b"] = x.(core.MapExample)["a"]
+				tree.Train(x, 1.0)
+			}
+			return tree.Info()
+		}
+
+		Expect(train(false)).To(Equal(&common.TreeInfo{NumNodes: 1, NumLearning: 1, MaxDepth: 1, ByteSize: 1056}))
+		Expect(train(true)).To(Equal(&common.TreeInfo{NumNodes: 1, NumLearning: 1, MaxDepth: 1, ByteSize: 752}))
+	})
+
+	It("should calculate feature importances", func() {
+		rnd := rand.New(rand.NewSource(1))
+		tree, err := hoeffding.New(driftModel, "target", &hoeffding.Config{
+			Config: common.Config{GracePeriod: 50, IncludeFeatureImportances: true},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tree.FeatureImportances()).To(BeEmpty())
+
+		for _, x := range driftStream(rnd, 5000, "a") {
+			if x.(core.MapExample)["b"] == "x" && rnd.Intn(4) == 0 {
+				x.(core.MapExample)["target"] = "x"
+			}
+			tree.Train(x, 1.0)
+		}
+
+		fi := tree.FeatureImportances()
+		Expect(fi).To(HaveLen(2))
+		Expect(fi[0].Feature).To(Equal("a"))
+		Expect(fi[0].Importance).To(BeNumerically("~", 0.911, 0.001))
+		Expect(fi[0].NumSplits).To(Equal(1))
+		Expect(fi[0].AvgDepth).To(Equal(1.0))
+		Expect(fi[1].Feature).To(Equal("b"))
+		Expect(fi[1].Importance).To(BeNumerically("~", 0.089, 0.001))
+		Expect(fi[1].NumSplits).To(Equal(1))
+		Expect(fi[1].AvgDepth).To(Equal(2.0))
+		Expect(fi.Get("c")).To(BeNil())
+
+		Expect(withoutByteSize(tree.Info())).To(Equal(&common.TreeInfo{NumNodes: 5, NumLearning: 3, MaxDepth: 3, FeatureImportances: fi}))
+	})
+
+	DescribeTable("should limit byte size",
+		func(maxByteSize int, expInfo *common.TreeInfo) {
+			model := core.NewModel(
+				core.NewCategoricalFeature("c", []string{"v0", "v1", "v2", "v3", "v4", "v5", "v6", "v7"}),
+				core.NewCategoricalFeatureHashBuckets("h", 1000),
+				core.NewCategoricalFeature("target", []string{"x", "y"}),
+			)
+			rnd := rand.New(rand.NewSource(1))
+
+			tree, err := hoeffding.New(model, "target", &hoeffding.Config{
+				Config: common.Config{GracePeriod: 50, PrunePeriod: 1000, MaxByteSize: maxByteSize},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			for i := 0; i < 10000; i++ {
+				c := rnd.Intn(8)
+				x := core.MapExample{"c": fmt.Sprintf("v%d", c), "h": fmt.Sprintf("h%d", rnd.Intn(1000)), "target": "y"}
+				if c%2 == 0 && rnd.Intn(4) != 0 {
+					x["target"] = "x"
+				}
+				tree.Train(x, 1.0)
+			}
+			Expect(tree.Info()).To(Equal(expInfo))
+		},
+
+		Entry("unbounded", 1<<30, &common.TreeInfo{NumNodes: 9, NumLearning: 8, MaxDepth: 2, ByteSize: 346000}),
+		Entry("bounded", 140000, &common.TreeInfo{NumNodes: 9, NumLearning: 3, NumDisabled: 5, MaxDepth: 2, ByteSize: 137320}),
+	)
+
+	DescribeTable("should track byte sizes",
+		func(config common.Config) {
+			model := core.NewModel(
+				core.NewCategoricalFeature("a", []string{"x", "y"}),
+				core.NewCategoricalFeature("b", []string{"x", "y", "z"}),
+				core.NewNumericalFeature("n"),
+				core.NewCategoricalFeature("target", []string{"x", "y"}),
+			)
+			rnd := rand.New(rand.NewSource(1))
+
+			tree, err := hoeffding.New(model, "target", &hoeffding.Config{Config: config, Adaptive: true})
+			Expect(err).NotTo(HaveOccurred())
+
+			for i := 0; i < 6000; i++ {
+				x := core.MapExample{"a": "x", "b": fmt.Sprintf("%c", 'x'+rnd.Intn(3)), "n": rnd.Float64(), "target": "y"}
+				if rnd.Intn(2) == 0 {
+					x["a"] = "y"
+				}
+				if (i < 3000) == (x["a"] == "x") {
+					x["target"] = "x"
+				}
+				tree.Train(x, 1.0)
+			}
+
+			// the running estimate must match a full estimate of the loaded tree
+			buf := new(bytes.Buffer)
+			_, err = tree.WriteTo(buf)
+			Expect(err).NotTo(HaveOccurred())
+
+			loaded, err := hoeffding.Load(buf, &hoeffding.Config{Config: config, Adaptive: true})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tree.Info().ByteSize).To(BeNumerically(">", 0))
+			Expect(tree.Info().ByteSize).To(Equal(loaded.Info().ByteSize))
+		},
+
+		Entry("gaussian", common.Config{GracePeriod: 50}),
+		Entry("e-bst", common.Config{GracePeriod: 50, NumericObserver: common.NumericObserverEBST}),
+		Entry("quantile sketch", common.Config{GracePeriod: 50, NumericObserver: common.NumericObserverQuantileSketch}),
+		Entry("re-evaluated", common.Config{GracePeriod: 50, ReevaluateSplits: true}),
+		Entry("bounded", common.Config{GracePeriod: 50, PrunePeriod: 500, MaxByteSize: 20000}),
+		Entry("poor features removed", common.Config{GracePeriod: 50, RemovePoorFeatures: true}),
+	)
+
+	DescribeTable("should apply split bounds",
+		func(crit classification.SplitCriterion, bound common.Bound, expBound, expWeight float64) {
+			rnd := rand.New(rand.NewSource(1))
+			tracer := new(traceRecorder)
+			tree, err := hoeffding.New(driftModel, "target", &hoeffding.Config{
+				Config:         common.Config{GracePeriod: 50, Tracer: tracer, SplitBound: bound},
+				SplitCriterion: crit,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, x := range driftStream(rnd, 8000, "a") {
+				if rnd.Float64() < 0.1 {
+					x.(core.MapExample)["target"] = x.(core.MapExample)["b"]
+				}
+				tree.Train(x, 1.0)
+			}
+
+			attempt := tracer.First(common.TraceSplitAttempt)
+			Expect(attempt).NotTo(BeNil())
+			Expect(attempt.SplitAttempt.HoeffdingBound).To(BeNumerically("~", expBound, 0.01))
+
+			split := tracer.First(common.TraceSplit)
+			Expect(split).NotTo(BeNil())
+			Expect(split.Feature).To(Equal("a"))
+			Expect(split.SplitAttempt.Weight).To(Equal(expWeight))
+		},
+
+		Entry("default", nil, nil, 0.40, 50.0),
+		Entry("hoeffding", nil, common.HoeffdingBound{}, 0.40, 50.0),
+		Entry("hoeffding gini", classification.GiniImpurity{}, common.HoeffdingBound{}, 0.40, 50.0),
+		Entry("mcdiarmid gini", classification.GiniImpurity{}, common.McDiarmidGini{}, 3.21, 3100.0),
+		Entry("gaussian", nil, common.GaussianBound{}, 0.37, 50.0),
+	)
+
+	It("should reject incompatible split bounds", func() {
+		_, err := hoeffding.New(driftModel, "target", &hoeffding.Config{
+			Config: common.Config{SplitBound: common.McDiarmidGini{}},
+		})
+		Expect(err).To(MatchError(`hoeffding: McDiarmidGini bound requires the GiniImpurity split criterion`))
+	})
+
+	DescribeTable("should split imbalanced streams",
+		func(crit classification.SplitCriterion, expFeature string, expWeight float64) {
+			model := core.NewModel(
+				core.NewCategoricalFeature("a", []string{"x", "y"}),
+				core.NewCategoricalFeature("b", []string{"x", "y"}),
+				core.NewCategoricalFeature("target", []string{"x", "y"}),
+			)
+			tracer := new(traceRecorder)
+			tree, err := hoeffding.New(model, "target", &hoeffding.Config{
+				Config:         common.Config{Tracer: tracer},
+				SplitCriterion: crit,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			// 1% minority class; a captures almost all of the minority,
+			// b isolates half of it in a tiny branch
+			rnd := rand.New(rand.NewSource(1))
+			for i := 0; i < 10000; i++ {
+				x := core.MapExample{"a": "y", "b": "y", "target": "x"}
+				pa, pb := 0.3, 0.01
+				if rnd.Float64() < 0.01 {
+					x["target"] = "y"
+					pa, pb = 0.99, 0.5
+				}
+				if rnd.Float64() < pa {
+					x["a"] = "x"
+				}
+				if rnd.Float64() < pb {
+					x["b"] = "x"
+				}
+				tree.Train(x, 1.0)
+			}
+
+			split := tracer.First(common.TraceSplit)
+			Expect(split).NotTo(BeNil())
+			Expect(split.NodeRef).To(Equal(int64(1)))
+			Expect(split.Feature).To(Equal(expFeature))
+			Expect(split.SplitAttempt.Weight).To(Equal(expWeight))
+		},
+
+		Entry("information gain", classification.InformationGain{}, "b", 3400.0),
+		Entry("hellinger distance", classification.HellingerDistance{}, "a", 400.0),
+	)
+
+	DescribeTable("should split categorical features",
+		func(binary bool, expInfo *common.TreeInfo, expAccuracy float64) {
+			model := core.NewModel(
+				core.NewCategoricalFeature("c", []string{"v0", "v1", "v2", "v3", "v4", "v5", "v6", "v7"}),
+				core.NewCategoricalFeature("target", []string{"x", "y"}),
+			)
+			rnd := rand.New(rand.NewSource(1))
+			stream := func(n int) []core.Example {
+				examples := make([]core.Example, 0, n)
+				for i := 0; i < n; i++ {
+					c := rnd.Intn(8)
+					if c == 0 {
+						examples = append(examples, core.MapExample{"c": fmt.Sprintf("v%d", c), "target": "x"})
+					} else {
+						examples = append(examples, core.MapExample{"c": fmt.Sprintf("v%d", c), "target": "y"})
+					}
+				}
+				return examples
+			}
+
+			tree, err := hoeffding.New(model, "target", &hoeffding.Config{
+				Config: common.Config{GracePeriod: 100, BinaryCategoricalSplits: binary},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, x := range stream(5000) {
+				tree.Train(x, 1.0)
+			}
+			Expect(withoutByteSize(tree.Info())).To(Equal(expInfo))
+
+			accuracy := eval.NewAccuracy()
+			for _, x := range stream(1000) {
+				predicted, _ := tree.Predict(nil, x).Best().Top()
+				accuracy.Record(predicted, model.Feature("target").Category(x), 1.0)
+			}
+			Expect(accuracy.Accuracy()).To(BeNumerically("~", expAccuracy, 0.001))
+		},
+
+		Entry("multi-way", false, &common.TreeInfo{NumNodes: 9, NumLearning: 8, MaxDepth: 2}, 1.0),
+		Entry("binary", true, &common.TreeInfo{NumNodes: 3, NumLearning: 2, MaxDepth: 2}, 1.0),
+	)
+
 	DescribeTable("should train & predict",
 		func(n int, expInfo *common.TreeInfo, exp *testdata.ClassificationScore) {
 			tree, model, examples := train(n)
-			Expect(tree.Info()).To(Equal(expInfo))
+			Expect(withoutByteSize(tree.Info())).To(Equal(expInfo))
 
 			accuracy := eval.NewAccuracy()
 			kappa := eval.NewKappa()
@@ -26,7 +26,9 @@ func DefaultSplitCriterion() SplitCriterion {
 
 // --------------------------------------------------------------------
 
-// GiniImpurity determines split merit using Gini impurity
+// GiniImpurity determines split merit using Gini impurity. The merit
+// is the Gini gain, i.e. the impurity before the split minus the weighted
+// impurity after the split.
 type GiniImpurity struct{}
 
 // Range implements SplitCriterion
@@ -38,6 +40,11 @@ func (GiniImpurity) Merit(pre *util.Vector, post *util.VectorDistribution) float
 		return 0.0
 	}
 
+	preSum := pre.Weight()
+	if preSum == 0 {
+		return 0.0
+	}
+
 	total := 0.0
 	post.ForEach(func(_ int, vv *util.Vector) bool {
 		total += vv.Weight()
@@ -47,10 +54,10 @@ func (GiniImpurity) Merit(pre *util.Vector, post *util.VectorDistribution) float
 		return 0.0
 	}
 
-	merit := 0.0
+	merit := calcGiniSplit(pre, preSum)
 	post.ForEach(func(_ int, vv *util.Vector) bool {
 		sum := vv.Weight()
-		merit += sum / total * calcGiniSplit(vv, sum)
+		merit -= sum / total * calcGiniSplit(vv, sum)
 		return true
 	})
 	return splits.NormMerit(merit)
@@ -123,6 +130,62 @@ func (c InformationGain) Merit(pre *util.Vector, post *util.VectorDistribution)
 	return splits.NormMerit(e1 - e2/total)
 }
 
+// HellingerDistance determines split merit through the Hellinger distance
+// between the distributions of a class and all other classes across the
+// branches of a split. It is insensitive to class skew and therefore
+// suitable for imbalanced streams. Multi-class problems are evaluated
+// one-vs-rest, using the largest distance.
+type HellingerDistance struct{}
+
+// Range implements SplitCriterion
+func (HellingerDistance) Range(_ *util.Vector) float64 { return math.Sqrt2 }
+
+// Merit implements SplitCriterion
+func (HellingerDistance) Merit(pre *util.Vector, post *util.VectorDistribution) float64 {
+	if pre == nil || post == nil {
+		return 0.0
+	}
+
+	total := 0.0
+	totals := make([]float64, 0, pre.Len())
+	post.ForEach(func(_ int, vv *util.Vector) bool {
+		total += vv.Weight()
+		vv.ForEach(func(cat int, w float64) bool {
+			for cat >= len(totals) {
+				totals = append(totals, 0)
+			}
+			totals[cat] += w
+			return true
+		})
+		return true
+	})
+
+	merit := 0.0
+	for cat, pos := range totals {
+		neg := total - pos
+		if pos <= 0 || neg <= 0 {
+			continue
+		}
+
+		dist := 0.0
+		post.ForEach(func(_ int, vv *util.Vector) bool {
+			p := vv.Get(cat)
+			d := math.Sqrt(p/pos) - math.Sqrt(math.Max(vv.Weight()-p, 0)/neg)
+			dist += d * d
+			return true
+		})
+		if dist = math.Sqrt(dist); dist > merit {
+			merit = dist
+		}
+
+		// binary distances are symmetric
+		if len(totals) == 2 {
+			break
+		}
+	}
+	return splits.NormMerit(merit)
+}
+
 // --------------------------------------------------------------------
 
 // GainRatio normalises the merits of other split criterions
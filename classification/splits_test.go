@@ -50,9 +50,9 @@ var _ = Describe("SplitCriterion", func() {
 
 		It("should evaluate split", func() {
 			Expect(subject.Merit(nil, nil)).To(Equal(0.0))
-			Expect(subject.Merit(pre, post1)).To(BeNumerically("~", 0.338, 0.001))
-			Expect(subject.Merit(pre, post2)).To(BeNumerically("~", 0.155, 0.001))
-			Expect(subject.Merit(pre, post3)).To(BeNumerically("~", 0.480, 0.001))
+			Expect(subject.Merit(pre, post1)).To(BeNumerically("~", 0.142, 0.001))
+			Expect(subject.Merit(pre, post2)).To(BeNumerically("~", 0.325, 0.001))
+			Expect(subject.Merit(pre, post3)).To(BeNumerically("~", 0.000, 0.001))
 		})
 	})
 
@@ -82,6 +82,49 @@ var _ = Describe("SplitCriterion", func() {
 		})
 	})
 
+	Describe("HellingerDistance", func() {
+		var subject = classification.HellingerDistance{}
+
+		It("should have range", func() {
+			Expect(subject.Range(nil)).To(BeNumerically("~", 1.414, 0.001))
+			Expect(subject.Range(pre)).To(BeNumerically("~", 1.414, 0.001))
+		})
+
+		It("should evaluate split", func() {
+			Expect(subject.Merit(nil, nil)).To(Equal(0.0))
+			Expect(subject.Merit(pre, post1)).To(BeNumerically("~", 0.751, 0.001))
+			Expect(subject.Merit(pre, post2)).To(BeNumerically("~", 1.159, 0.001))
+			Expect(subject.Merit(pre, post3)).To(Equal(0.0))
+		})
+
+		It("should evaluate multi-class splits one-vs-rest", func() {
+			post := new(util.VectorDistribution)
+			post.Add(0, 0, 4.0)
+			post.Add(0, 1, 4.0)
+			post.Add(1, 0, 4.0)
+			post.Add(1, 1, 4.0)
+			post.Add(1, 2, 2.0)
+			Expect(subject.Merit(util.NewVectorFromSlice(8, 8, 2), post)).To(BeNumerically("~", 0.765, 0.001))
+		})
+
+		It("should be insensitive to class skew", func() {
+			balanced := new(util.VectorDistribution)
+			balanced.Add(0, 0, 80)
+			balanced.Add(0, 1, 20)
+			balanced.Add(1, 0, 20)
+			balanced.Add(1, 1, 80)
+
+			skewed := new(util.VectorDistribution)
+			skewed.Add(0, 0, 8000)
+			skewed.Add(0, 1, 20)
+			skewed.Add(1, 0, 2000)
+			skewed.Add(1, 1, 80)
+
+			Expect(subject.Merit(util.NewVectorFromSlice(100, 100), balanced)).To(BeNumerically("~", 0.632, 0.001))
+			Expect(subject.Merit(util.NewVectorFromSlice(10000, 100), skewed)).To(BeNumerically("~", 0.632, 0.001))
+		})
+	})
+
 	Describe("GainRatio", func() {
 		var (
 			base    = classification.DefaultSplitCriterion()
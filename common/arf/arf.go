@@ -0,0 +1,2 @@
+// Package arf contains configuration shared by adaptive random forests.
+package arf
@@ -0,0 +1,44 @@
+package arf
+
+// Config configures behaviour
+type Config struct {
+	// The number of member trees.
+	// Default: 10
+	Size int
+
+	// The lambda parameter of the Poisson distribution used to weight
+	// examples for each member.
+	// Default: 6
+	Lambda float64
+
+	// The confidence of the ADWIN warning detectors. Once a member
+	// signals a warning, it starts training a background tree.
+	// Default: 0.01
+	WarningConfidence float64
+
+	// The confidence of the ADWIN drift detectors. Once a member signals
+	// a drift, it is replaced by its background tree or by a new tree,
+	// if no background tree has been started.
+	// Default: 0.001
+	DriftConfidence float64
+
+	// The seed of the random number generator.
+	// Default: 0
+	Seed int64
+}
+
+// Norm inits and normalizes the config
+func (c *Config) Norm() {
+	if c.Size <= 0 {
+		c.Size = 10
+	}
+	if c.Lambda <= 0 {
+		c.Lambda = 6
+	}
+	if c.WarningConfidence <= 0 {
+		c.WarningConfidence = 0.01
+	}
+	if c.DriftConfidence <= 0 {
+		c.DriftConfidence = 0.001
+	}
+}
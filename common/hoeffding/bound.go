@@ -0,0 +1,49 @@
+package hoeffding
+
+import "math"
+
+// Bound calculates the confidence bound of split decisions, i.e. the
+// maximum expected deviation between the observed and the true difference
+// in merit after a number of observations.
+type Bound interface {
+	// Value returns the bound for a merit range, the allowable error
+	// of a split decision and the observed weight.
+	Value(rng, delta, weight float64) float64
+}
+
+// HoeffdingBound is the classic bound used by Hoeffding trees:
+//
+//	sqrt(R² * ln(1/δ) / 2n)
+type HoeffdingBound struct{}
+
+// Value implements Bound.
+func (HoeffdingBound) Value(rng, delta, weight float64) float64 {
+	return math.Sqrt(rng * rng * math.Log(1/delta) * 0.5 / weight)
+}
+
+// McDiarmidGini is the McDiarmid's bound for the Gini gain, as derived by
+// Rutkowski et al. (2013). It is independent of the merit range and only
+// valid for the GiniImpurity split criterion, trees reject it otherwise.
+// The corresponding bound for the information gain is not provided, its
+// constant grows with the number of classes and observations and prevents
+// practically all splits:
+//
+//	8 * sqrt(ln(1/δ) / 2n)
+type McDiarmidGini struct{}
+
+// Value implements Bound.
+func (McDiarmidGini) Value(_, delta, weight float64) float64 {
+	return 8 * math.Sqrt(math.Log(1/delta)*0.5/weight)
+}
+
+// GaussianBound approximates the distribution of the merit difference
+// with a gaussian, assuming a worst-case standard deviation of R/2:
+//
+//	z(1-δ) * R / (2 * sqrt(n))
+type GaussianBound struct{}
+
+// Value implements Bound.
+func (GaussianBound) Value(rng, delta, weight float64) float64 {
+	z := math.Sqrt2 * math.Erfinv(1-2*delta)
+	return z * rng * 0.5 / math.Sqrt(weight)
+}
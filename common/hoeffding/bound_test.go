@@ -0,0 +1,28 @@
+package hoeffding_test
+
+import (
+	"github.com/bsm/reason/common/hoeffding"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Bound", func() {
+
+	DescribeTable("should calculate",
+		func(b hoeffding.Bound, rng, weight, exp float64) {
+			Expect(b.Value(rng, 1e-7, weight)).To(BeNumerically("~", exp, 0.0001))
+		},
+
+		Entry("hoeffding", hoeffding.HoeffdingBound{}, 1.0, 200.0, 0.2007),
+		Entry("hoeffding (range)", hoeffding.HoeffdingBound{}, 2.0, 200.0, 0.4015),
+		Entry("hoeffding (weight)", hoeffding.HoeffdingBound{}, 1.0, 2000.0, 0.0635),
+		Entry("mcdiarmid gini", hoeffding.McDiarmidGini{}, 1.0, 200.0, 1.6059),
+		Entry("mcdiarmid gini (range)", hoeffding.McDiarmidGini{}, 2.0, 200.0, 1.6059),
+		Entry("mcdiarmid gini (weight)", hoeffding.McDiarmidGini{}, 1.0, 2000.0, 0.5078),
+		Entry("gaussian", hoeffding.GaussianBound{}, 1.0, 200.0, 0.1838),
+		Entry("gaussian (range)", hoeffding.GaussianBound{}, 2.0, 200.0, 0.3677),
+		Entry("gaussian (weight)", hoeffding.GaussianBound{}, 1.0, 2000.0, 0.0581),
+	)
+
+})
@@ -19,21 +19,135 @@ type Config struct {
 	// Default: 1,000,000
 	MaxLearningNodes int
 
+	// The maximum estimated byte size of the tree. Leaf sizes are estimated
+	// from the size of the collected feature stats. When the tree exceeds
+	// the budget, the leaves with the least promise are deactivated on
+	// every pruning attempt. To disable, set to 0.
+	// Default: 0 (unlimited)
+	MaxByteSize int
+
+	// Ranks leaves by promise, i.e. their potential to improve accuracy
+	// through further splits, instead of by weight when deactivating
+	// leaves during pruning.
+	// Default: false
+	PruneByPromise bool
+
+	// Enables poor feature removal. After a split attempt, leaves drop the
+	// stats of features whose merit is confidently below the best candidate
+	// and stop observing them.
+	// Default: false
+	RemovePoorFeatures bool
+
+	// The number of features each leaf observes. When a leaf is created,
+	// it samples a random subspace of this size from the model features
+	// and ignores all others. To disable, set to 0.
+	// Default: 0 (all features)
+	FeatureSubspace int
+
+	// The seed of the random number generator used to sample feature
+	// subspaces.
+	// Default: 0
+	Seed int64
+
 	// The allowable error in a split decision - values closer
 	// to zero will take longer to decide.
 	// Default: 0.0000001
 	SplitConfidence float64
 
+	// The bound which determines whether the best split candidate is
+	// confidently better than the second-best.
+	// Default: HoeffdingBound{}
+	SplitBound Bound
+
+	// The observer for numerical features.
+	// Default: NumericObserverGaussian
+	NumericObserver NumericObserver
+
 	// Threshold below which a split will be forced to break ties
 	// Default: 0.05
 	TieThreshold float64
 
+	// The maximum depth of the tree, the root being at depth 1. Leaves
+	// at the maximum depth are not split. To disable, set to 0.
+	// Default: 0 (unlimited)
+	MaxDepth int
+
+	// The minimum weight each child must receive from a split, estimated
+	// from the post-split stats of the best candidate. To disable, set to 0.
+	// Default: 0 (disabled)
+	MinLeafWeight float64
+
+	// The minimum merit gain required to perform a split, i.e. the merit of
+	// the best split candidate over not splitting at all. Splits with a lower
+	// gain are rejected, even when forced by the tie threshold.
+	// To disable, set to 0.
+	// Default: 0 (disabled)
+	MinMeritGain float64
+
+	// Enables binary splits on categorical features. Instead of creating
+	// one child per category, splits route a subset of categories to the
+	// first child and all others to the second.
+	// Default: false
+	BinaryCategoricalSplits bool
+
+	// Enables EFDT mode. Leaves split as soon as the best split is
+	// better than no split at all. Split nodes keep collecting feature
+	// stats and periodically re-evaluate the split decision, restructuring
+	// the subtree once a different split becomes clearly better.
+	// Default: false
+	ReevaluateSplits bool
+
+	// The number of training instances a split node should observe
+	// between re-evaluations (EFDT mode only).
+	// Default: same as GracePeriod
+	ReevaluationPeriod int
+
+	// The number of workers evaluating split candidates concurrently.
+	// Features are distributed across workers, which may reduce the
+	// latency of split attempts when many features are observed. Results
+	// are identical to the sequential evaluation.
+	// Default: 1 (sequential)
+	SplitWorkers int
+
+	// Enables deterministic training. Ties between split candidates with
+	// equal merits are broken by feature name, and ties between leaves
+	// during pruning by their position in the tree. Two trees trained on
+	// the same examples in the same order are identical.
+	// Default: false
+	Deterministic bool
+
+	// Enables copy-on-write publication. Training operates on a private
+	// copy of the tree while readers use the most recently published
+	// version without locking. A new version is published every
+	// PublishPeriod training examples or when Publish is called.
+	// Publishing copies the tree without the feature stats collected for
+	// training. To disable, set to 0.
+	// Default: 0 (disabled)
+	PublishPeriod int
+
+	// The number of buckets of the categorical feature returned by
+	// LeafFeature. Leaf identifiers are node references, which are never
+	// wrapped around: PredictLeaf reports nodes with references at or
+	// beyond the bucket count as -1, which maps to core.NoCategory.
+	// Default: 1024
+	LeafBuckets int
+
+	// Includes feature importances in the tree info. Importances are
+	// calculated on every call to Info.
+	// Default: false
+	IncludeFeatureImportances bool
+
 	// By enabling this option, tracing notification events will be
-	// emitted via the Traces channel after each training cycle. This
-	// is for debug purposes only. When enabled, you must consume
-	// the Traces channel to avoid locked threads.
+	// emitted via the Traces channel of the tree. This is for debug
+	// purposes only. Training never blocks, events are dropped when
+	// the channel is not consumed fast enough.
 	// Default: false
 	EnableTracing bool
+
+	// A custom tracer to receive trace notification events. When set,
+	// events are passed to the tracer instead of the Traces channel.
+	// Default: nil
+	Tracer Tracer
 }
 
 // Norm inits and normalizes the config
@@ -41,15 +155,45 @@ func (c *Config) Norm() {
 	if c.GracePeriod <= 0 {
 		c.GracePeriod = 200
 	}
+	if c.ReevaluationPeriod <= 0 {
+		c.ReevaluationPeriod = c.GracePeriod
+	}
+	if c.SplitWorkers <= 0 {
+		c.SplitWorkers = 1
+	}
 	if c.PrunePeriod == 0 {
 		c.PrunePeriod = 100000
 	}
 	if c.MaxLearningNodes <= 0 {
 		c.MaxLearningNodes = 1000000
 	}
+	if c.MaxByteSize < 0 {
+		c.MaxByteSize = 0
+	}
+	if c.FeatureSubspace < 0 {
+		c.FeatureSubspace = 0
+	}
+	if c.MaxDepth < 0 {
+		c.MaxDepth = 0
+	}
+	if c.MinLeafWeight < 0 {
+		c.MinLeafWeight = 0
+	}
+	if c.MinMeritGain < 0 {
+		c.MinMeritGain = 0
+	}
+	if c.LeafBuckets <= 0 {
+		c.LeafBuckets = 1024
+	}
+	if c.PublishPeriod < 0 {
+		c.PublishPeriod = 0
+	}
 	if c.SplitConfidence <= 0 {
 		c.SplitConfidence = 1e-7
 	}
+	if c.SplitBound == nil {
+		c.SplitBound = HoeffdingBound{}
+	}
 	if c.TieThreshold <= 0 {
 		c.TieThreshold = 0.05
 	}
@@ -0,0 +1,70 @@
+package hoeffding
+
+import "fmt"
+
+// StopReason indicates why the traversal of a decision path has stopped.
+type StopReason int
+
+const (
+	// StopLeaf indicates that the traversal has reached a leaf.
+	StopLeaf StopReason = iota
+	// StopMissingValue indicates that the example had no (known) value for
+	// the split feature and the split node had no default child.
+	StopMissingValue
+	// StopMissingChild indicates that the example was routed to a child
+	// which has not been created yet.
+	StopMissingChild
+)
+
+// String returns the reason name.
+func (r StopReason) String() string {
+	switch r {
+	case StopLeaf:
+		return "leaf"
+	case StopMissingValue:
+		return "missing-value"
+	case StopMissingChild:
+		return "missing-child"
+	}
+	return fmt.Sprintf("StopReason(%d)", int(r))
+}
+
+// DecisionStep describes a node on the decision path of an example.
+type DecisionStep struct {
+	// The node weight.
+	Weight float64
+	// The split feature (split nodes only).
+	Feature string
+	// The condition of the branch taken (split nodes only).
+	Condition string
+	// The feature value seen (split nodes only).
+	Value interface{}
+	// Indicates that the value was missing or unknown and the
+	// example was routed to the default child (split nodes only).
+	Missing bool
+}
+
+// String returns a one-liner summary.
+func (s *DecisionStep) String() string {
+	switch {
+	case s.Feature == "":
+		return fmt.Sprintf("leaf [weight:%.0f]", s.Weight)
+	case s.Condition == "":
+		return fmt.Sprintf("%s is missing [weight:%.0f]", s.Feature, s.Weight)
+	case s.Missing:
+		return fmt.Sprintf("%s (default) [weight:%.0f]", s.Condition, s.Weight)
+	}
+	return fmt.Sprintf("%s (%v) [weight:%.0f]", s.Condition, s.Value, s.Weight)
+}
+
+// Contributions contain additive feature contributions (SHAP values) to the
+// outputs of a prediction. For each output, the contributions of all features
+// add up to the output minus the expected output.
+type Contributions struct {
+	// The expected outputs of the tree.
+	Expected []float64
+	// The outputs for the example.
+	Output []float64
+	// The contributions to each output by feature.
+	Features map[string][]float64
+}
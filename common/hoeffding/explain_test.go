@@ -0,0 +1,29 @@
+package hoeffding_test
+
+import (
+	"github.com/bsm/reason/common/hoeffding"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DecisionStep", func() {
+
+	It("should have a string representation", func() {
+		Expect((&hoeffding.DecisionStep{Weight: 4}).String()).To(Equal("leaf [weight:4]"))
+		Expect((&hoeffding.DecisionStep{Weight: 14, Feature: "a", Condition: "a = x", Value: "x"}).String()).To(Equal("a = x (x) [weight:14]"))
+		Expect((&hoeffding.DecisionStep{Weight: 14, Feature: "a", Condition: "a = x", Missing: true}).String()).To(Equal("a = x (default) [weight:14]"))
+		Expect((&hoeffding.DecisionStep{Weight: 14, Feature: "a", Missing: true}).String()).To(Equal("a is missing [weight:14]"))
+	})
+
+})
+
+var _ = Describe("StopReason", func() {
+
+	It("should have a string representation", func() {
+		Expect(hoeffding.StopLeaf.String()).To(Equal("leaf"))
+		Expect(hoeffding.StopMissingValue.String()).To(Equal("missing-value"))
+		Expect(hoeffding.StopMissingChild.String()).To(Equal("missing-child"))
+		Expect(hoeffding.StopReason(9).String()).To(Equal("StopReason(9)"))
+	})
+
+})
@@ -0,0 +1,79 @@
+package hoeffding
+
+import "sort"
+
+// FeatureImportance contains the importance of a feature.
+type FeatureImportance struct {
+	Feature    string  // the feature name
+	Importance float64 // the normalised mean decrease in impurity (MDI)
+	NumSplits  int     // the number of split nodes on the feature
+	AvgDepth   float64 // the average depth of split nodes on the feature
+}
+
+// FeatureImportances are sorted by importance, most important first.
+type FeatureImportances []FeatureImportance
+
+// Get returns the importance of a feature, or nil if the feature is
+// not used by any split.
+func (ff FeatureImportances) Get(feature string) *FeatureImportance {
+	for i := range ff {
+		if ff[i].Feature == feature {
+			return &ff[i]
+		}
+	}
+	return nil
+}
+
+// ImportanceAccumulator accumulates split node impurity decreases by feature.
+type ImportanceAccumulator map[string]*importanceSums
+
+type importanceSums struct {
+	decrease float64
+	depth    int
+	splits   int
+}
+
+// Add adds the weighted impurity decrease of a split node at a given depth.
+// Negative decreases are ignored, but the split is still counted.
+func (a ImportanceAccumulator) Add(feature string, decrease float64, depth int) {
+	sums, ok := a[feature]
+	if !ok {
+		sums = new(importanceSums)
+		a[feature] = sums
+	}
+
+	if decrease > 0 {
+		sums.decrease += decrease
+	}
+	sums.depth += depth
+	sums.splits++
+}
+
+// Result returns the normalised feature importances.
+func (a ImportanceAccumulator) Result() FeatureImportances {
+	var total float64
+	for _, sums := range a {
+		total += sums.decrease
+	}
+
+	res := make(FeatureImportances, 0, len(a))
+	for feature, sums := range a {
+		fi := FeatureImportance{
+			Feature:   feature,
+			NumSplits: sums.splits,
+			AvgDepth:  float64(sums.depth) / float64(sums.splits),
+		}
+		if total > 0 {
+			fi.Importance = sums.decrease / total
+		}
+		res = append(res, fi)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Importance == res[j].Importance {
+			return res[i].Feature < res[j].Feature
+		}
+		return res[i].Importance > res[j].Importance
+	})
+	return res
+}
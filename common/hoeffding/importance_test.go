@@ -0,0 +1,45 @@
+package hoeffding_test
+
+import (
+	"github.com/bsm/reason/common/hoeffding"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ImportanceAccumulator", func() {
+
+	It("should normalise importances", func() {
+		acc := make(hoeffding.ImportanceAccumulator)
+		acc.Add("a", 6, 1)
+		acc.Add("b", 1, 2)
+		acc.Add("b", 1, 3)
+		acc.Add("c", -1, 4)
+
+		Expect(acc.Result()).To(Equal(hoeffding.FeatureImportances{
+			{Feature: "a", Importance: 0.75, NumSplits: 1, AvgDepth: 1},
+			{Feature: "b", Importance: 0.25, NumSplits: 2, AvgDepth: 2.5},
+			{Feature: "c", Importance: 0, NumSplits: 1, AvgDepth: 4},
+		}))
+	})
+
+	It("should handle empty accumulators", func() {
+		acc := make(hoeffding.ImportanceAccumulator)
+		Expect(acc.Result()).To(BeEmpty())
+
+		acc.Add("a", 0, 1)
+		Expect(acc.Result()).To(Equal(hoeffding.FeatureImportances{
+			{Feature: "a", Importance: 0, NumSplits: 1, AvgDepth: 1},
+		}))
+	})
+
+})
+
+var _ = Describe("FeatureImportances", func() {
+
+	It("should get by feature", func() {
+		ff := hoeffding.FeatureImportances{{Feature: "a", Importance: 1}}
+		Expect(ff.Get("a")).To(Equal(&hoeffding.FeatureImportance{Feature: "a", Importance: 1}))
+		Expect(ff.Get("b")).To(BeNil())
+	})
+
+})
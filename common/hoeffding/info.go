@@ -11,6 +11,12 @@ type TreeInfo struct {
 	NumLearning int // the number of learning leaves
 	NumDisabled int // the number of disable leaves
 	MaxDepth    int // the maximum depth
+
+	NumAlternates int // the number of alternate subtrees (adaptive trees only)
+	ByteSize      int // the estimated in-memory byte size of node and feature stats
+
+	// Feature importances (only if IncludeFeatureImportances is enabled)
+	FeatureImportances FeatureImportances
 }
 
 // SplitCandidateInfo contains information about
@@ -20,6 +26,38 @@ type SplitCandidateInfo struct {
 	Merit   float64 // the merit
 }
 
+// SplitRejection indicates why a split has been rejected by a growth
+// constraint.
+type SplitRejection int
+
+const (
+	// RejectNone indicates that no growth constraint was violated.
+	RejectNone SplitRejection = iota
+	// RejectMaxDepth indicates that the leaf has reached the maximum depth.
+	RejectMaxDepth
+	// RejectMinLeafWeight indicates that at least one of the children
+	// would have received less than the minimum leaf weight.
+	RejectMinLeafWeight
+	// RejectMinMeritGain indicates that the merit gain of the split was
+	// below the minimum.
+	RejectMinMeritGain
+)
+
+// String returns the rejection name.
+func (r SplitRejection) String() string {
+	switch r {
+	case RejectNone:
+		return "none"
+	case RejectMaxDepth:
+		return "max-depth"
+	case RejectMinLeafWeight:
+		return "min-leaf-weight"
+	case RejectMinMeritGain:
+		return "min-merit-gain"
+	}
+	return fmt.Sprintf("SplitRejection(%d)", int(r))
+}
+
 // SplitAttemptInfo instances may be emitted as part of the the tree training.
 // They contain information about attempted splits.
 type SplitAttemptInfo struct {
@@ -27,12 +65,17 @@ type SplitAttemptInfo struct {
 	Weight float64
 	// Indicator of a successful split
 	Success bool
+	// Indicator of a re-evaluation of an existing split (EFDT mode only).
+	// Successful re-evaluations restructure the subtree.
+	Reevaluation bool
 	// The posssible merit gain of this split attempt.
 	MeritGain float64
 	// The hoeffding bound of this split attempt.
 	HoeffdingBound float64
 	// Split candidates
 	Candidates []SplitCandidateInfo
+	// The growth constraint which prevented the split, if any.
+	Rejection SplitRejection
 }
 
 // String returns a one-liner summary.
@@ -48,6 +91,14 @@ func (t *SplitAttemptInfo) String() string {
 			break
 		}
 	}
-	return fmt.Sprintf("Weight: %.1f, Success: %v, MeritGain: %.2f, HBound: %.2f, Candidates: [%s]",
-		t.Weight, t.Success, t.MeritGain, t.HoeffdingBound, strings.Join(candidates, " "))
+	var rejection string
+	if t.Rejection != RejectNone {
+		rejection = ", Rejection: " + t.Rejection.String()
+	}
+	if t.Reevaluation {
+		return fmt.Sprintf("Weight: %.1f, Success: %v, Reevaluation: true, MeritGain: %.2f, HBound: %.2f, Candidates: [%s]%s",
+			t.Weight, t.Success, t.MeritGain, t.HoeffdingBound, strings.Join(candidates, " "), rejection)
+	}
+	return fmt.Sprintf("Weight: %.1f, Success: %v, MeritGain: %.2f, HBound: %.2f, Candidates: [%s]%s",
+		t.Weight, t.Success, t.MeritGain, t.HoeffdingBound, strings.Join(candidates, " "), rejection)
 }
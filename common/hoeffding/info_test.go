@@ -0,0 +1,35 @@
+package hoeffding_test
+
+import (
+	"github.com/bsm/reason/common/hoeffding"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SplitAttemptInfo", func() {
+
+	It("should have a string representation", func() {
+		info := &hoeffding.SplitAttemptInfo{
+			Weight:     200,
+			MeritGain:  0.12,
+			Candidates: []hoeffding.SplitCandidateInfo{{Feature: "a", Merit: 0.4}, {Feature: "b", Merit: 0.28}, {Feature: ""}},
+		}
+		Expect(info.String()).To(Equal("Weight: 200.0, Success: false, MeritGain: 0.12, HBound: 0.00, Candidates: [a=0.40 b=0.28]"))
+
+		info.Rejection = hoeffding.RejectMinMeritGain
+		Expect(info.String()).To(Equal("Weight: 200.0, Success: false, MeritGain: 0.12, HBound: 0.00, Candidates: [a=0.40 b=0.28], Rejection: min-merit-gain"))
+	})
+
+})
+
+var _ = Describe("SplitRejection", func() {
+
+	It("should have a string representation", func() {
+		Expect(hoeffding.RejectNone.String()).To(Equal("none"))
+		Expect(hoeffding.RejectMaxDepth.String()).To(Equal("max-depth"))
+		Expect(hoeffding.RejectMinLeafWeight.String()).To(Equal("min-leaf-weight"))
+		Expect(hoeffding.RejectMinMeritGain.String()).To(Equal("min-merit-gain"))
+		Expect(hoeffding.SplitRejection(9).String()).To(Equal("SplitRejection(9)"))
+	})
+
+})
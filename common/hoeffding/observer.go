@@ -0,0 +1,21 @@
+package hoeffding
+
+// NumericObserver determines how numerical features are observed at the
+// leaves and which split pivots are evaluated.
+type NumericObserver int
+
+const (
+	// NumericObserverGaussian evaluates equally spaced pivots between the
+	// observed minimum and maximum. Classification trees approximate the
+	// observed values by target category as gaussians.
+	NumericObserverGaussian NumericObserver = iota
+	// NumericObserverEBST evaluates pivots between all observed values.
+	// Classification trees maintain an exhaustive binary search tree of the
+	// observed values, which is exact but memory intensive for features with
+	// many distinct values.
+	NumericObserverEBST
+	// NumericObserverQuantileSketch evaluates pivots at data quantiles.
+	// Classification trees maintain a compact streaming histogram of the
+	// observed values.
+	NumericObserverQuantileSketch
+)
@@ -0,0 +1,34 @@
+package hoeffding
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/bsm/reason/core"
+)
+
+// SubspaceExclusions samples a random subspace of size features from the
+// model, excluding the target, and returns the names of all features
+// outside of it, sorted by name. Returns nil if the subspace covers all
+// features.
+func SubspaceExclusions(rnd *rand.Rand, model *core.Model, target string, size int) []string {
+	names := make([]string, 0, len(model.Features))
+	for name := range model.Features {
+		if name != target {
+			names = append(names, name)
+		}
+	}
+	if size <= 0 || size >= len(names) {
+		return nil
+	}
+
+	sort.Strings(names)
+	for i := 0; i < size; i++ {
+		j := i + rnd.Intn(len(names)-i)
+		names[i], names[j] = names[j], names[i]
+	}
+
+	excluded := names[size:]
+	sort.Strings(excluded)
+	return excluded
+}
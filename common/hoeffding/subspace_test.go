@@ -0,0 +1,34 @@
+package hoeffding_test
+
+import (
+	"math/rand"
+
+	"github.com/bsm/reason/common/hoeffding"
+	"github.com/bsm/reason/core"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SubspaceExclusions", func() {
+	var model = core.NewModel(
+		core.NewNumericalFeature("a"),
+		core.NewNumericalFeature("b"),
+		core.NewNumericalFeature("c"),
+		core.NewNumericalFeature("d"),
+		core.NewNumericalFeature("target"),
+	)
+
+	It("should exclude features outside the subspace", func() {
+		rnd := rand.New(rand.NewSource(1))
+		Expect(hoeffding.SubspaceExclusions(rnd, model, "target", 2)).To(Equal([]string{"c", "d"}))
+		Expect(hoeffding.SubspaceExclusions(rnd, model, "target", 2)).To(Equal([]string{"b", "c"}))
+		Expect(hoeffding.SubspaceExclusions(rnd, model, "target", 3)).To(Equal([]string{"c"}))
+	})
+
+	It("should not exclude when the subspace covers all features", func() {
+		rnd := rand.New(rand.NewSource(1))
+		Expect(hoeffding.SubspaceExclusions(rnd, model, "target", 0)).To(BeNil())
+		Expect(hoeffding.SubspaceExclusions(rnd, model, "target", 4)).To(BeNil())
+		Expect(hoeffding.SubspaceExclusions(rnd, model, "target", 5)).To(BeNil())
+	})
+})
@@ -0,0 +1,122 @@
+package hoeffding
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// DefaultTraceBufferSize is the buffer size of trace channels created
+// when tracing is enabled without a custom tracer.
+const DefaultTraceBufferSize = 1000
+
+// TraceKind identifies the kind of a trace event.
+type TraceKind int
+
+const (
+	// TraceSplitAttempt is emitted after each split attempt or re-evaluation.
+	TraceSplitAttempt TraceKind = iota
+	// TraceSplit is emitted after a leaf has been split or a split node
+	// has been restructured.
+	TraceSplit
+	// TracePrune is emitted after leaves have been deactivated to limit
+	// the number of learning leaves.
+	TracePrune
+	// TraceDeactivate is emitted after leaves have been deactivated to
+	// limit the byte size of the tree.
+	TraceDeactivate
+	// TraceDriftDetected is emitted when a change in error has been detected
+	// and an alternate subtree is grown (adaptive trees only).
+	TraceDriftDetected
+	// TraceDriftReset is emitted when a subtree has been replaced by its
+	// alternate (adaptive trees only).
+	TraceDriftReset
+	// TraceAlternateDiscarded is emitted when an alternate subtree has been
+	// discarded (adaptive trees only).
+	TraceAlternateDiscarded
+)
+
+// String returns the kind name.
+func (k TraceKind) String() string {
+	switch k {
+	case TraceSplitAttempt:
+		return "split-attempt"
+	case TraceSplit:
+		return "split"
+	case TracePrune:
+		return "prune"
+	case TraceDeactivate:
+		return "deactivate"
+	case TraceDriftDetected:
+		return "drift-detected"
+	case TraceDriftReset:
+		return "drift-reset"
+	case TraceAlternateDiscarded:
+		return "alternate-discarded"
+	}
+	return fmt.Sprintf("TraceKind(%d)", int(k))
+}
+
+// Trace is a tracing notification event.
+type Trace struct {
+	// The kind of event.
+	Kind TraceKind
+	// The reference of the affected node, if any.
+	NodeRef int64
+	// The chosen feature (splits only).
+	Feature string
+	// Details of the split attempt (split attempts and splits only).
+	SplitAttempt *SplitAttemptInfo
+	// The number of deactivated leaves (prune and deactivate events only).
+	NumDeactivated int
+}
+
+// String returns a one-liner summary.
+func (t *Trace) String() string {
+	switch t.Kind {
+	case TraceSplitAttempt:
+		return fmt.Sprintf("%s node:%d %s", t.Kind, t.NodeRef, t.SplitAttempt)
+	case TraceSplit:
+		return fmt.Sprintf("%s node:%d feature:%s", t.Kind, t.NodeRef, t.Feature)
+	case TracePrune, TraceDeactivate:
+		return fmt.Sprintf("%s leaves:%d", t.Kind, t.NumDeactivated)
+	}
+	return fmt.Sprintf("%s node:%d", t.Kind, t.NodeRef)
+}
+
+// Tracer instances receive trace events. Tracers are called while the tree
+// is locked for training and must not block.
+type Tracer interface {
+	// Trace receives a trace event.
+	Trace(*Trace)
+}
+
+// ChanTracer is a Tracer which emits events via a bounded channel. Events
+// are dropped when the channel is full.
+type ChanTracer struct {
+	// C receives the trace events.
+	C chan *Trace
+
+	dropped uint64
+}
+
+// NewChanTracer inits a new ChanTracer with a buffer size.
+func NewChanTracer(size int) *ChanTracer {
+	if size <= 0 {
+		size = DefaultTraceBufferSize
+	}
+	return &ChanTracer{C: make(chan *Trace, size)}
+}
+
+// Trace implements Tracer.
+func (t *ChanTracer) Trace(e *Trace) {
+	select {
+	case t.C <- e:
+	default:
+		atomic.AddUint64(&t.dropped, 1)
+	}
+}
+
+// Dropped returns the number of dropped events.
+func (t *ChanTracer) Dropped() uint64 {
+	return atomic.LoadUint64(&t.dropped)
+}
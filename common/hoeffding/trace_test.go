@@ -0,0 +1,45 @@
+package hoeffding_test
+
+import (
+	"github.com/bsm/reason/common/hoeffding"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ChanTracer", func() {
+	var subject *hoeffding.ChanTracer
+
+	BeforeEach(func() {
+		subject = hoeffding.NewChanTracer(2)
+	})
+
+	It("should emit events without blocking", func() {
+		subject.Trace(&hoeffding.Trace{Kind: hoeffding.TraceSplit, NodeRef: 1, Feature: "a"})
+		subject.Trace(&hoeffding.Trace{Kind: hoeffding.TracePrune, NumDeactivated: 3})
+		subject.Trace(&hoeffding.Trace{Kind: hoeffding.TraceDriftReset, NodeRef: 2})
+		Expect(subject.C).To(HaveLen(2))
+		Expect(subject.Dropped()).To(Equal(uint64(1)))
+
+		Expect((<-subject.C).String()).To(Equal("split node:1 feature:a"))
+		Expect((<-subject.C).String()).To(Equal("prune leaves:3"))
+	})
+
+	It("should default buffer size", func() {
+		Expect(cap(hoeffding.NewChanTracer(0).C)).To(Equal(hoeffding.DefaultTraceBufferSize))
+	})
+
+})
+
+var _ = Describe("Trace", func() {
+
+	It("should have a string representation", func() {
+		Expect((&hoeffding.Trace{
+			Kind:         hoeffding.TraceSplitAttempt,
+			NodeRef:      3,
+			SplitAttempt: &hoeffding.SplitAttemptInfo{Weight: 200},
+		}).String()).To(Equal("split-attempt node:3 Weight: 200.0, Success: false, MeritGain: 0.00, HBound: 0.00, Candidates: []"))
+		Expect((&hoeffding.Trace{Kind: hoeffding.TraceDriftDetected, NodeRef: 2}).String()).To(Equal("drift-detected node:2"))
+		Expect(hoeffding.TraceKind(99).String()).To(Equal("TraceKind(99)"))
+	})
+
+})
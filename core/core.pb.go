@@ -107,7 +107,8 @@ type Feature struct {
 	// List of known fact values.
 	Vocabulary []string `protobuf:"bytes,4,rep,name=vocabulary" json:"vocabulary,omitempty"`
 	// Defines the number of hash buckets used by hashed
-	// categorical features.
+	// categorical features or the number of buckets of
+	// identity features.
 	HashBuckets uint32 `protobuf:"varint,5,opt,name=hash_buckets,json=hashBuckets,proto3" json:"hash_buckets,omitempty"`
 }
 
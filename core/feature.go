@@ -53,6 +53,18 @@ func NewCategoricalFeatureIdentity(name string) *Feature {
 	}
 }
 
+// NewCategoricalFeatureIdentityBuckets initialises a new categorical feature with identity,
+// limited to a number of buckets. Values are converted to integers via:
+//   VALUE % numBuckets
+func NewCategoricalFeatureIdentityBuckets(name string, numBuckets uint32) *Feature {
+	return &Feature{
+		Name:        name,
+		Kind:        Feature_CATEGORICAL,
+		Strategy:    Feature_IDENTITY,
+		HashBuckets: numBuckets,
+	}
+}
+
 // NewCategoricalFeatureHashBuckets initialises a new categorical feature with a number of hash buckets.
 // Values are converted to integers via:
 //   HASH(value) % numBuckets
@@ -84,6 +96,9 @@ func (f *Feature) NumCategories() int {
 		return 0
 	}
 	if f.Strategy == Feature_IDENTITY {
+		if f.HashBuckets != 0 {
+			return int(f.HashBuckets)
+		}
 		return -1
 	}
 	return int(f.HashBuckets) + len(f.Vocabulary)
@@ -107,7 +122,11 @@ func (f *Feature) CategoryOf(v interface{}) Category {
 	}
 
 	if f.Strategy == Feature_IDENTITY {
-		return categorize(v)
+		cat := categorize(v)
+		if f.HashBuckets != 0 && IsCat(cat) {
+			cat %= Category(f.HashBuckets)
+		}
+		return cat
 	}
 
 	s := stringify(v)
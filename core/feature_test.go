@@ -97,6 +97,13 @@ var _ = Describe("Feature", func() {
 			core.NewCategoricalFeatureIdentity("cat"), core.MapExample{"cat": customString("7")}, core.Category(7)),
 		Entry("categorical, identity (int ptr)",
 			core.NewCategoricalFeatureIdentity("cat"), core.MapExample{"cat": intPtr(6)}, core.Category(6)),
+
+		Entry("categorical, identity, buckets (no value)",
+			core.NewCategoricalFeatureIdentityBuckets("cat", 4), core.MapExample{}, core.NoCategory),
+		Entry("categorical, identity, buckets (in range)",
+			core.NewCategoricalFeatureIdentityBuckets("cat", 4), core.MapExample{"cat": 3}, core.Category(3)),
+		Entry("categorical, identity, buckets (out of range)",
+			core.NewCategoricalFeatureIdentityBuckets("cat", 4), core.MapExample{"cat": "6"}, core.Category(2)),
 	)
 
 })
@@ -0,0 +1,23 @@
+// Package bagging contains helpers shared by online bagging ensembles.
+package bagging
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Poisson draws a random number from a Poisson(lambda) distribution.
+// It is used to weight training examples for individual ensemble members.
+func Poisson(rnd *rand.Rand, lambda float64) int {
+	if lambda <= 0 {
+		return 0
+	}
+
+	limit := math.Exp(-lambda)
+	k, p := 0, rnd.Float64()
+	for p > limit {
+		k++
+		p *= rnd.Float64()
+	}
+	return k
+}
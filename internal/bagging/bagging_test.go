@@ -0,0 +1,36 @@
+package bagging_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/bsm/reason/internal/bagging"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = DescribeTable("Poisson",
+	func(lambda, expMean, expZeros float64) {
+		rnd := rand.New(rand.NewSource(1))
+
+		sum, zeros := 0, 0
+		for i := 0; i < 10000; i++ {
+			k := bagging.Poisson(rnd, lambda)
+			Expect(k).To(BeNumerically(">=", 0))
+			if sum += k; k == 0 {
+				zeros++
+			}
+		}
+		Expect(float64(sum) / 10000).To(BeNumerically("~", expMean, 0.05))
+		Expect(float64(zeros) / 10000).To(BeNumerically("~", expZeros, 0.01))
+	},
+	Entry("lambda 1", 1.0, 1.0, 0.368),
+	Entry("lambda 6", 6.0, 6.0, 0.002),
+	Entry("lambda 0", 0.0, 0.0, 1.0),
+)
+
+func TestSuite(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "internal/bagging")
+}
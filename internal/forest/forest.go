@@ -0,0 +1,358 @@
+// Package forest contains the member management shared by adaptive
+// random forests.
+package forest
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+
+	common "github.com/bsm/reason/common/arf"
+	"github.com/bsm/reason/core"
+	"github.com/bsm/reason/internal/bagging"
+	"github.com/bsm/reason/internal/iocount"
+	"github.com/bsm/reason/internal/protoio"
+	"github.com/bsm/reason/util"
+	"github.com/gogo/protobuf/proto"
+)
+
+// Tree is a member tree of a forest.
+type Tree interface {
+	io.WriterTo
+
+	// Train passes an example x with a weight to the tree for training.
+	Train(x core.Example, weight float64)
+
+	// Error returns the prediction error of the tree for an example x.
+	// Returns a negative value if the error cannot be determined.
+	Error(x core.Example) float64
+}
+
+// Factory creates member trees, each seeded individually.
+type Factory interface {
+	// New creates a new tree.
+	New(seed int64) (Tree, error)
+	// Load loads a tree from a reader.
+	Load(r io.Reader, seed int64) (Tree, error)
+}
+
+// NewFactory validates the target feature of a model and returns
+// a factory for member trees.
+type NewFactory func(model *core.Model, target string) (Factory, error)
+
+// SubspaceSize returns the default feature subspace size of member trees,
+// i.e. √M+1, where M is the number of model features excluding the target.
+func SubspaceSize(model *core.Model) int {
+	if n := len(model.Features) - 1; n > 0 {
+		return int(math.Sqrt(float64(n))) + 1
+	}
+	return 1
+}
+
+// Member is a member of a forest.
+type Member struct {
+	// The active tree, used for predictions.
+	Tree Tree
+	// The background tree, trained after a warning has been
+	// signalled, nil otherwise.
+	Background Tree
+
+	warning *util.ADWIN
+	drift   *util.ADWIN
+}
+
+// ErrorRate returns the mean prediction error of the member since
+// it was last replaced.
+func (m *Member) ErrorRate() float64 {
+	return m.drift.Mean()
+}
+
+// Forest manages the members of an adaptive random forest.
+type Forest struct {
+	Model   *core.Model
+	Target  string
+	Members []*Member
+
+	factory Factory
+	config  common.Config
+	rnd     *rand.Rand
+}
+
+// New inits a new forest using a model, a target feature and a config
+// with config.Size members.
+func New(model *core.Model, target string, newFactory NewFactory, config common.Config) (*Forest, error) {
+	f, err := newForest(model, target, newFactory, config)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < f.config.Size; i++ {
+		tree, err := f.newTree()
+		if err != nil {
+			return nil, err
+		}
+		f.Members = append(f.Members, f.newMember(tree))
+	}
+	return f, nil
+}
+
+// Load loads a forest from a reader, as written by WriteTo. The number
+// of members is determined by the stored forest.
+func Load(r io.Reader, newFactory NewFactory, config common.Config) (*Forest, error) {
+	rp := &protoio.Reader{Reader: bufio.NewReader(r)}
+
+	var (
+		model   *core.Model
+		target  string
+		members [][]byte
+	)
+
+	for {
+		tag, wire, err := rp.ReadField()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if wire != proto.WireBytes {
+			return nil, proto.ErrInternalBadWireType
+		}
+
+		switch tag {
+		case 1: // model
+			model = new(core.Model)
+			if err := rp.ReadMessage(model); err != nil {
+				return nil, err
+			}
+		case 2: // target
+			str, err := rp.ReadString()
+			if err != nil {
+				return nil, err
+			}
+			target = str
+		case 3: // members
+			str, err := rp.ReadString()
+			if err != nil {
+				return nil, err
+			}
+			members = append(members, []byte(str))
+		default:
+			return nil, fmt.Errorf("forest: unexpected field tag %d", tag)
+		}
+	}
+
+	if model == nil {
+		return nil, fmt.Errorf("forest: invalid forest")
+	}
+	if len(members) == 0 {
+		return nil, fmt.Errorf("forest: no members")
+	}
+
+	f, err := newForest(model, target, newFactory, config)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, data := range members {
+		member, err := f.loadMember(data)
+		if err != nil {
+			return nil, err
+		}
+		f.Members = append(f.Members, member)
+	}
+	f.config.Size = len(f.Members)
+	return f, nil
+}
+
+func newForest(model *core.Model, target string, newFactory NewFactory, config common.Config) (*Forest, error) {
+	config.Norm()
+
+	factory, err := newFactory(model, target)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Forest{
+		Model:   model,
+		Target:  target,
+		factory: factory,
+		config:  config,
+		rnd:     rand.New(rand.NewSource(config.Seed)),
+	}, nil
+}
+
+// Train passes an example x with a weight to the forest for training. Each
+// member receives a Poisson-weighted copy of x and tracks its prequential
+// error to detect warnings and drifts.
+func (f *Forest) Train(x core.Example, weight float64) {
+	for _, m := range f.Members {
+		errValue := m.Tree.Error(x)
+
+		if k := bagging.Poisson(f.rnd, f.config.Lambda); k > 0 {
+			m.Tree.Train(x, weight*float64(k))
+			if m.Background != nil {
+				m.Background.Train(x, weight*float64(k))
+			}
+		}
+
+		if errValue >= 0 {
+			f.detectChanges(m, errValue)
+		}
+	}
+}
+
+// WriteTo implements io.WriterTo
+func (f *Forest) WriteTo(w io.Writer) (int64, error) {
+	wc := &iocount.Writer{W: w}
+	wp := &protoio.Writer{Writer: bufio.NewWriter(wc)}
+
+	if err := wp.WriteMessageField(1, f.Model); err != nil {
+		return wc.N, err
+	}
+	if err := wp.WriteStringField(2, f.Target); err != nil {
+		return wc.N, err
+	}
+
+	buf := new(bytes.Buffer)
+	for _, m := range f.Members {
+		buf.Reset()
+		if err := writeMember(buf, m); err != nil {
+			return wc.N, err
+		}
+		if err := wp.WriteStringField(3, buf.String()); err != nil {
+			return wc.N, err
+		}
+	}
+	return wc.N, wp.Flush()
+}
+
+// detectChanges starts a background tree when a warning is signalled and
+// replaces the active tree when a drift is signalled.
+func (f *Forest) detectChanges(m *Member, errValue float64) {
+	if m.warning.Add(errValue) {
+		if tree, err := f.newTree(); err == nil {
+			m.Background = tree
+		}
+		m.warning = util.NewADWIN(f.config.WarningConfidence)
+	}
+
+	if m.drift.Add(errValue) {
+		if m.Background == nil {
+			tree, err := f.newTree()
+			if err != nil {
+				// keep the current tree if no replacement can be built
+				return
+			}
+			m.Background = tree
+		}
+		*m = *f.newMember(m.Background)
+	}
+}
+
+func (f *Forest) newTree() (Tree, error) {
+	return f.factory.New(f.rnd.Int63())
+}
+
+func (f *Forest) newMember(tree Tree) *Member {
+	return &Member{
+		Tree:    tree,
+		warning: util.NewADWIN(f.config.WarningConfidence),
+		drift:   util.NewADWIN(f.config.DriftConfidence),
+	}
+}
+
+func (f *Forest) loadTree(s string) (Tree, error) {
+	return f.factory.Load(bytes.NewReader([]byte(s)), f.rnd.Int63())
+}
+
+func (f *Forest) loadMember(data []byte) (*Member, error) {
+	rp := &protoio.Reader{Reader: bufio.NewReader(bytes.NewReader(data))}
+	m := new(Member)
+
+	for {
+		tag, wire, err := rp.ReadField()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if wire != proto.WireBytes {
+			return nil, proto.ErrInternalBadWireType
+		}
+
+		switch tag {
+		case 1: // tree
+			str, err := rp.ReadString()
+			if err != nil {
+				return nil, err
+			}
+			if m.Tree, err = f.loadTree(str); err != nil {
+				return nil, err
+			}
+		case 2: // background
+			str, err := rp.ReadString()
+			if err != nil {
+				return nil, err
+			}
+			if m.Background, err = f.loadTree(str); err != nil {
+				return nil, err
+			}
+		case 3: // warning
+			m.warning = new(util.ADWIN)
+			if err := rp.ReadMessage(m.warning); err != nil {
+				return nil, err
+			}
+		case 4: // drift
+			m.drift = new(util.ADWIN)
+			if err := rp.ReadMessage(m.drift); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("forest: unexpected field tag %d", tag)
+		}
+	}
+
+	if m.Tree == nil {
+		return nil, fmt.Errorf("forest: invalid member")
+	}
+	if m.warning == nil {
+		m.warning = util.NewADWIN(f.config.WarningConfidence)
+	}
+	if m.drift == nil {
+		m.drift = util.NewADWIN(f.config.DriftConfidence)
+	}
+	return m, nil
+}
+
+func writeMember(w io.Writer, m *Member) error {
+	wp := &protoio.Writer{Writer: bufio.NewWriter(w)}
+	buf := new(bytes.Buffer)
+
+	if _, err := m.Tree.WriteTo(buf); err != nil {
+		return err
+	}
+	if err := wp.WriteStringField(1, buf.String()); err != nil {
+		return err
+	}
+
+	if m.Background != nil {
+		buf.Reset()
+		if _, err := m.Background.WriteTo(buf); err != nil {
+			return err
+		}
+		if err := wp.WriteStringField(2, buf.String()); err != nil {
+			return err
+		}
+	}
+
+	if err := wp.WriteMessageField(3, m.warning); err != nil {
+		return err
+	}
+	if err := wp.WriteMessageField(4, m.drift); err != nil {
+		return err
+	}
+	return wp.Flush()
+}
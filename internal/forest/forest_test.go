@@ -0,0 +1,213 @@
+package forest_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"testing"
+
+	common "github.com/bsm/reason/common/arf"
+	"github.com/bsm/reason/core"
+	"github.com/bsm/reason/internal/forest"
+	"github.com/bsm/reason/internal/protoio"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Forest", func() {
+	var model = core.NewModel(
+		core.NewNumericalFeature("a"),
+		core.NewNumericalFeature("target"),
+	)
+	var factory *mockFactory
+	var subject *forest.Forest
+
+	BeforeEach(func() {
+		var err error
+		factory = new(mockFactory)
+		subject, err = forest.New(model, "target", factory.open, common.Config{Size: 3})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should validate", func() {
+		_, err := forest.New(model, "z", factory.open, common.Config{})
+		Expect(err).To(MatchError(`unknown feature "z"`))
+	})
+
+	It("should init", func() {
+		Expect(subject.Members).To(HaveLen(3))
+		for _, m := range subject.Members {
+			Expect(m.Background).To(BeNil())
+			Expect(m.ErrorRate()).To(Equal(0.0))
+		}
+		Expect(factory.seeds).To(HaveLen(3))
+		Expect(factory.seeds[0]).NotTo(Equal(factory.seeds[1]))
+	})
+
+	It("should calculate default subspace sizes", func() {
+		Expect(forest.SubspaceSize(core.NewModel(
+			core.NewNumericalFeature("target"),
+		))).To(Equal(1))
+		Expect(forest.SubspaceSize(core.NewModel(
+			core.NewNumericalFeature("a"),
+			core.NewNumericalFeature("target"),
+		))).To(Equal(2))
+		Expect(forest.SubspaceSize(core.NewModel(
+			core.NewNumericalFeature("a"),
+			core.NewNumericalFeature("b"),
+			core.NewNumericalFeature("c"),
+			core.NewNumericalFeature("d"),
+			core.NewNumericalFeature("target"),
+		))).To(Equal(3))
+	})
+
+	It("should train members", func() {
+		for i := 0; i < 100; i++ {
+			subject.Train(core.MapExample{}, 1.0)
+		}
+		for _, m := range subject.Members {
+			Expect(m.Tree.(*mockTree).weight).To(BeNumerically("~", 600, 150))
+		}
+	})
+
+	It("should start and promote background trees", func() {
+		for i := 0; i < 1000; i++ {
+			subject.Train(core.MapExample{}, 1.0)
+		}
+		for _, m := range subject.Members {
+			Expect(m.Background).To(BeNil())
+		}
+
+		member := subject.Members[0]
+		orig := member.Tree
+		factory.err = 0.05
+
+		var background forest.Tree
+		for i := 0; i < 2000 && background == nil; i++ {
+			subject.Train(core.MapExample{}, 1.0)
+			background = member.Background
+		}
+		Expect(background).NotTo(BeNil())
+		Expect(member.Tree).To(BeIdenticalTo(orig))
+
+		for i := 0; i < 2000 && member.Tree == orig; i++ {
+			subject.Train(core.MapExample{}, 1.0)
+		}
+		Expect(member.Tree).NotTo(BeIdenticalTo(orig))
+		Expect(member.Tree).To(BeIdenticalTo(background))
+		Expect(member.Tree.(*mockTree).weight).To(BeNumerically(">", 0))
+		Expect(member.Background).To(BeNil())
+		Expect(member.ErrorRate()).To(Equal(0.0))
+	})
+
+	It("should keep trees if no replacement can be built", func() {
+		for i := 0; i < 1000; i++ {
+			subject.Train(core.MapExample{}, 1.0)
+		}
+
+		member := subject.Members[0]
+		orig := member.Tree
+		factory.err = 0.05
+		factory.failNew = true
+
+		for i := 0; i < 4000; i++ {
+			subject.Train(core.MapExample{}, 1.0)
+		}
+		Expect(member.Tree).To(BeIdenticalTo(orig))
+		Expect(member.Background).To(BeNil())
+		Expect(member.Tree.(*mockTree).weight).To(BeNumerically(">", 0))
+	})
+
+	It("should write/load members", func() {
+		for i := 0; i < 1000; i++ {
+			subject.Train(core.MapExample{}, 1.0)
+		}
+		factory.err = 0.05
+		for i := 0; i < 2000 && subject.Members[0].Background == nil; i++ {
+			subject.Train(core.MapExample{}, 1.0)
+		}
+		Expect(subject.Members[0].Background).NotTo(BeNil())
+
+		buf := new(bytes.Buffer)
+		Expect(subject.WriteTo(buf)).To(Equal(int64(buf.Len())))
+
+		loaded, err := forest.Load(bytes.NewReader(buf.Bytes()), factory.open, common.Config{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loaded.Model).To(Equal(model))
+		Expect(loaded.Target).To(Equal("target"))
+		Expect(loaded.Members).To(HaveLen(3))
+		for i, m := range loaded.Members {
+			Expect(m.Tree.(*mockTree).weight).To(Equal(subject.Members[i].Tree.(*mockTree).weight))
+			Expect(m.ErrorRate()).To(Equal(subject.Members[i].ErrorRate()))
+			Expect(m.Background).To(Equal(subject.Members[i].Background))
+		}
+
+		buf.Reset()
+		Expect(loaded.WriteTo(buf)).To(Equal(int64(buf.Len())))
+		_, err = forest.Load(bytes.NewReader(buf.Bytes()), factory.open, common.Config{})
+		Expect(err).NotTo(HaveOccurred())
+
+		buf.Reset()
+		wp := &protoio.Writer{Writer: bufio.NewWriter(buf)}
+		Expect(wp.WriteMessageField(1, model)).To(Succeed())
+		Expect(wp.Flush()).To(Succeed())
+		_, err = forest.Load(buf, factory.open, common.Config{})
+		Expect(err).To(MatchError(`forest: no members`))
+	})
+})
+
+func TestSuite(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "internal/forest")
+}
+
+// --------------------------------------------------------------------
+
+type mockFactory struct {
+	seeds   []int64
+	err     float64
+	failNew bool
+}
+
+func (f *mockFactory) open(model *core.Model, target string) (forest.Factory, error) {
+	if model.Feature(target) == nil {
+		return nil, fmt.Errorf("unknown feature %q", target)
+	}
+	return f, nil
+}
+
+func (f *mockFactory) New(seed int64) (forest.Tree, error) {
+	if f.failNew {
+		return nil, fmt.Errorf("cannot create tree")
+	}
+	f.seeds = append(f.seeds, seed)
+	return &mockTree{factory: f}, nil
+}
+
+func (f *mockFactory) Load(r io.Reader, _ int64) (forest.Tree, error) {
+	t := &mockTree{factory: f}
+	if err := binary.Read(r, binary.LittleEndian, &t.weight); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+type mockTree struct {
+	factory *mockFactory
+	weight  float64
+	errSum  float64
+}
+
+func (t *mockTree) Train(_ core.Example, weight float64) { t.weight += weight }
+func (t *mockTree) Error(_ core.Example) float64 {
+	// emit 0/1 errors at the configured rate
+	prev := math.Floor(t.errSum)
+	t.errSum += t.factory.err
+	return math.Floor(t.errSum) - prev
+}
+func (t *mockTree) WriteTo(w io.Writer) (int64, error) {
+	return 8, binary.Write(w, binary.LittleEndian, t.weight)
+}
@@ -0,0 +1,81 @@
+package hoeffding
+
+import "github.com/bsm/reason/util"
+
+// Byte size estimates assume a 64-bit architecture. They are calculated
+// from the lengths of the underlying slices and maps, which makes them cheap
+// enough to be maintained incrementally and reproducible after
+// serialization.
+const (
+	// WordByteSize is the byte size of pointers, ints and floats.
+	WordByteSize = 8
+	// MapEntryByteSize is the estimated byte size of a map entry with
+	// word-sized keys and values, including the bucket overhead.
+	MapEntryByteSize = 3 * WordByteSize
+	// StreamStatsByteSize is the byte size of util.StreamStats.
+	StreamStatsByteSize = 3 * WordByteSize
+
+	// slice header, sparse map pointer and sparse capacity
+	vectorByteSize = 5 * WordByteSize
+)
+
+// VectorByteSize estimates the byte size of a vector.
+func VectorByteSize(vv *util.Vector) int {
+	if vv == nil {
+		return 0
+	}
+	return vectorByteSize + WordByteSize*len(vv.Dense) + MapEntryByteSize*len(vv.Sparse)
+}
+
+// VectorDistributionByteSize estimates the byte size of a vector distribution.
+func VectorDistributionByteSize(x *util.VectorDistribution) int {
+	size := vectorByteSize + WordByteSize*len(x.Dense) + MapEntryByteSize*len(x.Sparse)
+	x.ForEach(func(_ int, vv *util.Vector) bool {
+		size += VectorByteSize(vv)
+		return true
+	})
+	return size
+}
+
+// StreamStatsDistributionByteSize estimates the byte size of a stream stats
+// distribution.
+func StreamStatsDistributionByteSize(x *util.StreamStatsDistribution) int {
+	size := vectorByteSize + WordByteSize*len(x.Dense) + MapEntryByteSize*len(x.Sparse)
+	x.ForEach(func(_ int, _ *util.StreamStats) bool {
+		size += StreamStatsByteSize
+		return true
+	})
+	return size
+}
+
+// AddToVector adds a weight to a vector and returns the growth of its
+// estimated byte size.
+func AddToVector(vv *util.Vector, index int, weight float64) int {
+	before := VectorByteSize(vv)
+	vv.Add(index, weight)
+	return VectorByteSize(vv) - before
+}
+
+// AddToVectorDistribution adds a weight to a vector distribution and returns
+// the growth of its estimated byte size. Only the affected vector is
+// inspected.
+func AddToVectorDistribution(x *util.VectorDistribution, index, value int, weight float64) int {
+	before := WordByteSize*len(x.Dense) + MapEntryByteSize*len(x.Sparse) + VectorByteSize(x.Get(index))
+	x.Add(index, value, weight)
+	return WordByteSize*len(x.Dense) + MapEntryByteSize*len(x.Sparse) + VectorByteSize(x.Get(index)) - before
+}
+
+// AddToStreamStatsDistribution adds a value to a stream stats distribution
+// and returns the growth of its estimated byte size.
+func AddToStreamStatsDistribution(x *util.StreamStatsDistribution, index int, value, weight float64) int {
+	if index < 0 {
+		return 0
+	}
+
+	before := WordByteSize*len(x.Dense) + MapEntryByteSize*len(x.Sparse)
+	if x.Get(index) != nil {
+		before += StreamStatsByteSize
+	}
+	x.Add(index, value, weight)
+	return WordByteSize*len(x.Dense) + MapEntryByteSize*len(x.Sparse) + StreamStatsByteSize - before
+}
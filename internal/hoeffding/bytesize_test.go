@@ -0,0 +1,43 @@
+package hoeffding_test
+
+import (
+	"github.com/bsm/reason/internal/hoeffding"
+	"github.com/bsm/reason/util"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ByteSize", func() {
+	It("should estimate vectors", func() {
+		Expect(hoeffding.VectorByteSize(nil)).To(Equal(0))
+		Expect(hoeffding.VectorByteSize(new(util.Vector))).To(Equal(40))
+		Expect(hoeffding.VectorByteSize(&util.Vector{Dense: []float64{1, 2}})).To(Equal(56))
+		Expect(hoeffding.VectorByteSize(&util.Vector{Sparse: map[int64]float64{1: 1}})).To(Equal(64))
+	})
+
+	It("should track vector growth", func() {
+		vv := new(util.Vector)
+		Expect(hoeffding.AddToVector(vv, 1, 1.0)).To(Equal(24))
+		Expect(hoeffding.AddToVector(vv, 1, 1.0)).To(Equal(0))
+		Expect(hoeffding.VectorByteSize(vv)).To(Equal(64))
+	})
+
+	It("should track vector distribution growth", func() {
+		x := new(util.VectorDistribution)
+		growth := hoeffding.AddToVectorDistribution(x, 1, 0, 1.0)
+		growth += hoeffding.AddToVectorDistribution(x, 1, 1, 1.0)
+		growth += hoeffding.AddToVectorDistribution(x, 2, 0, 1.0)
+		Expect(hoeffding.AddToVectorDistribution(x, 2, 0, 1.0)).To(Equal(0))
+		Expect(growth).To(Equal(hoeffding.VectorDistributionByteSize(x) - hoeffding.VectorDistributionByteSize(new(util.VectorDistribution))))
+	})
+
+	It("should track stream stats distribution growth", func() {
+		x := new(util.StreamStatsDistribution)
+		Expect(hoeffding.AddToStreamStatsDistribution(x, -1, 1.0, 1.0)).To(Equal(0))
+
+		growth := hoeffding.AddToStreamStatsDistribution(x, 1, 1.0, 1.0)
+		growth += hoeffding.AddToStreamStatsDistribution(x, 3, 2.0, 1.0)
+		Expect(hoeffding.AddToStreamStatsDistribution(x, 3, 4.0, 1.0)).To(Equal(0))
+		Expect(growth).To(Equal(hoeffding.StreamStatsDistributionByteSize(x) - hoeffding.StreamStatsDistributionByteSize(new(util.StreamStatsDistribution))))
+	})
+})
@@ -2,12 +2,25 @@ package hoeffding
 
 import (
 	"fmt"
+	"math"
+	"sort"
+	"strings"
 
 	"github.com/bsm/reason/core"
 )
 
 const numPivotBuckets = 11
 
+// meritTolerance is the relative tolerance within which merits are
+// considered equal.
+const meritTolerance = 1e-9
+
+// MeritLess returns true if merit a is less than merit b. Merits which only
+// differ by floating point rounding errors are considered equal.
+func MeritLess(a, b float64) bool {
+	return b-a > meritTolerance*math.Max(math.Abs(a), math.Abs(b))
+}
+
 // PivotPoints determines the optimum split points between min and max
 // for a given number of buckets.
 func PivotPoints(min, max float64) []float64 {
@@ -23,6 +36,53 @@ func PivotPoints(min, max float64) []float64 {
 	return pp
 }
 
+// MidPivotPoints returns the mid-points between all adjacent values. Values
+// must be sorted and distinct.
+func MidPivotPoints(values []float64) []float64 {
+	if len(values) < 2 {
+		return nil
+	}
+
+	pp := make([]float64, 0, len(values)-1)
+	for i := 1; i < len(values); i++ {
+		pp = append(pp, (values[i-1]+values[i])/2)
+	}
+	return pp
+}
+
+// QuantilePivotPoints returns the mid-points between the adjacent values
+// that are closest to the data quantiles. Values must be sorted and
+// distinct, weights are the observed weights of each value.
+func QuantilePivotPoints(values, weights []float64) []float64 {
+	if len(values) < 2 {
+		return nil
+	}
+
+	sum := 0.0
+	for _, w := range weights {
+		sum += w
+	}
+
+	pp := make([]float64, 0, numPivotBuckets)
+	cum, q := 0.0, 1
+	for i := 0; i < len(values)-1 && q <= numPivotBuckets; i++ {
+		cum += weights[i]
+		if cum < quantileThreshold(sum, q) {
+			continue
+		}
+
+		pp = append(pp, (values[i]+values[i+1])/2)
+		for q <= numPivotBuckets && cum >= quantileThreshold(sum, q) {
+			q++
+		}
+	}
+	return pp
+}
+
+func quantileThreshold(sum float64, q int) float64 {
+	return sum * float64(q) / float64(numPivotBuckets+1)
+}
+
 // FormatNodeCondition returns the node condition description.
 func FormatNodeCondition(feat *core.Feature, pos int, pivot float64) string {
 	if feat.Kind.IsNumerical() {
@@ -36,3 +96,24 @@ func FormatNodeCondition(feat *core.Feature, pos int, pivot float64) string {
 	cat := core.Category(pos)
 	return fmt.Sprintf(`%s = %s`, feat.Name, feat.ValueOf(cat))
 }
+
+// FormatSubsetCondition returns the node condition description
+// of binary categorical splits.
+func FormatSubsetCondition(feat *core.Feature, pos int, subset []int64) string {
+	values := make([]string, 0, len(subset))
+	for _, cat := range subset {
+		values = append(values, feat.ValueOf(core.Category(cat)))
+	}
+
+	if pos == 0 {
+		return fmt.Sprintf(`%s in [%s]`, feat.Name, strings.Join(values, ", "))
+	}
+	return fmt.Sprintf(`%s not in [%s]`, feat.Name, strings.Join(values, ", "))
+}
+
+// SubsetContains returns true if a (sorted) subset contains the category.
+func SubsetContains(subset []int64, cat core.Category) bool {
+	n := int64(cat)
+	i := sort.Search(len(subset), func(i int) bool { return subset[i] >= n })
+	return i < len(subset) && subset[i] == n
+}
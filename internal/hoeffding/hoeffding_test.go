@@ -3,10 +3,46 @@ package hoeffding_test
 import (
 	"testing"
 
+	"github.com/bsm/reason/internal/hoeffding"
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 )
 
+var _ = DescribeTable("MeritLess",
+	func(a, b float64, exp bool) {
+		Expect(hoeffding.MeritLess(a, b)).To(Equal(exp))
+	},
+	Entry("less", 0.1, 0.2, true),
+	Entry("greater", 0.2, 0.1, false),
+	Entry("equal", 0.2, 0.2, false),
+	Entry("rounding error", 0.30000000000000004, 0.3, false),
+	Entry("rounding error (reverse)", 0.3, 0.30000000000000004, false),
+	Entry("zero", 0.0, 1e-12, true),
+)
+
+var _ = Describe("PivotPoints", func() {
+	It("should calculate mid-points", func() {
+		Expect(hoeffding.MidPivotPoints([]float64{1, 2, 4})).To(Equal([]float64{1.5, 3}))
+		Expect(hoeffding.MidPivotPoints([]float64{1})).To(BeEmpty())
+	})
+
+	It("should calculate quantiles", func() {
+		values := make([]float64, 0, 100)
+		weights := make([]float64, 0, 100)
+		for i := 0; i < 100; i++ {
+			values = append(values, float64(i))
+			weights = append(weights, 1)
+		}
+
+		pp := hoeffding.QuantilePivotPoints(values, weights)
+		Expect(pp).To(HaveLen(11))
+		Expect(pp[0]).To(Equal(8.5))
+		Expect(pp[10]).To(Equal(91.5))
+		Expect(hoeffding.QuantilePivotPoints([]float64{1}, []float64{1})).To(BeEmpty())
+	})
+})
+
 func TestSuite(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "internal/hoeffding")
@@ -0,0 +1,206 @@
+package hoeffding
+
+import common "github.com/bsm/reason/common/hoeffding"
+
+// SHAPNode is a tree node used for the calculation of SHAP values.
+type SHAPNode struct {
+	// The split feature (split nodes only).
+	Feature string
+	// The child nodes (split nodes only).
+	Children []*SHAPNode
+	// The index of the child the example is routed to. Split nodes
+	// which cannot route the example must set this to -1 and are
+	// treated as leaves, using the expected output of their children.
+	Hot int
+	// The node cover. The cover of split nodes is derived from the
+	// cover of their children.
+	Cover float64
+	// The node outputs (leaves only).
+	Values []float64
+
+	expected []float64
+}
+
+func (n *SHAPNode) isLeaf() bool {
+	return len(n.Children) == 0 || n.Hot < 0
+}
+
+// prepare calculates the cover and the expected outputs of the subtree
+// and returns the number of outputs.
+func (n *SHAPNode) prepare() int {
+	if len(n.Children) == 0 {
+		n.expected = n.Values
+		return len(n.Values)
+	}
+
+	size := 0
+	n.Cover = 0
+	for _, c := range n.Children {
+		if sz := c.prepare(); sz > size {
+			size = sz
+		}
+		n.Cover += c.Cover
+	}
+
+	n.expected = make([]float64, size)
+	if n.Cover > 0 {
+		for _, c := range n.Children {
+			for j, v := range c.expected {
+				n.expected[j] += v * c.Cover / n.Cover
+			}
+		}
+	}
+	return size
+}
+
+// TreeSHAP calculates additive feature contributions for a tree, using the
+// path-dependent TreeSHAP algorithm (Lundberg et al., 2018). For each output,
+// the feature contributions add up to the output of the example minus the
+// expected output of the tree.
+func TreeSHAP(root *SHAPNode) *common.Contributions {
+	size := root.prepare()
+
+	res := &common.Contributions{
+		Expected: make([]float64, size),
+		Output:   make([]float64, size),
+		Features: make(map[string][]float64),
+	}
+	copy(res.Expected, root.expected)
+
+	node := root
+	for !node.isLeaf() {
+		node = node.Children[node.Hot]
+	}
+	copy(res.Output, node.expected)
+
+	s := &shapState{size: size, res: res}
+	s.recurse(root, nil, 1, 1, "")
+	return res
+}
+
+type shapPathElement struct {
+	feature string
+	zero    float64
+	one     float64
+	weight  float64
+}
+
+type shapState struct {
+	size int
+	res  *common.Contributions
+}
+
+func (s *shapState) recurse(node *SHAPNode, parent []shapPathElement, zero, one float64, feature string) {
+	path := make([]shapPathElement, len(parent)+1)
+	copy(path, parent)
+	shapExtend(path, zero, one, feature)
+
+	if node.isLeaf() {
+		for i := 1; i < len(path); i++ {
+			el := path[i]
+			w := shapUnwoundSum(path, i) * (el.one - el.zero)
+			if w == 0 {
+				continue
+			}
+
+			phi := s.fetch(el.feature)
+			for j, v := range node.expected {
+				phi[j] += w * v
+			}
+		}
+		return
+	}
+
+	// undo previous splits on the same feature
+	incomingZero, incomingOne := 1.0, 1.0
+	for i := 1; i < len(path); i++ {
+		if path[i].feature == node.Feature {
+			incomingZero, incomingOne = path[i].zero, path[i].one
+			path = shapUnwind(path, i)
+			break
+		}
+	}
+
+	for i, c := range node.Children {
+		var childZero, childOne float64
+		if node.Cover > 0 {
+			childZero = c.Cover / node.Cover * incomingZero
+		}
+		if i == node.Hot {
+			childOne = incomingOne
+		}
+		if childZero == 0 && childOne == 0 {
+			continue
+		}
+		s.recurse(c, path, childZero, childOne, node.Feature)
+	}
+}
+
+func (s *shapState) fetch(feature string) []float64 {
+	phi, ok := s.res.Features[feature]
+	if !ok {
+		phi = make([]float64, s.size)
+		s.res.Features[feature] = phi
+	}
+	return phi
+}
+
+// shapExtend initialises the last element of the path and
+// updates the permutation weights.
+func shapExtend(path []shapPathElement, zero, one float64, feature string) {
+	d := len(path) - 1
+	path[d] = shapPathElement{feature: feature, zero: zero, one: one}
+	if d == 0 {
+		path[d].weight = 1
+	}
+
+	for i := d - 1; i >= 0; i-- {
+		path[i+1].weight += one * path[i].weight * float64(i+1) / float64(d+1)
+		path[i].weight = zero * path[i].weight * float64(d-i) / float64(d+1)
+	}
+}
+
+// shapUnwind removes the element at index k from the path and reverts
+// the permutation weights.
+func shapUnwind(path []shapPathElement, k int) []shapPathElement {
+	d := len(path) - 1
+	zero, one := path[k].zero, path[k].one
+	next := path[d].weight
+
+	for i := d - 1; i >= 0; i-- {
+		if one != 0 {
+			tmp := path[i].weight
+			path[i].weight = next * float64(d+1) / (float64(i+1) * one)
+			next = tmp - path[i].weight*zero*float64(d-i)/float64(d+1)
+		} else {
+			path[i].weight = path[i].weight * float64(d+1) / (zero * float64(d-i))
+		}
+	}
+
+	for i := k; i < d; i++ {
+		path[i].feature = path[i+1].feature
+		path[i].zero = path[i+1].zero
+		path[i].one = path[i+1].one
+	}
+	return path[:d]
+}
+
+// shapUnwoundSum returns the total permutation weight of the path with
+// the element at index k removed.
+func shapUnwoundSum(path []shapPathElement, k int) float64 {
+	d := len(path) - 1
+	zero, one := path[k].zero, path[k].one
+	next := path[d].weight
+
+	var sum float64
+	for i := d - 1; i >= 0; i-- {
+		if one != 0 {
+			tmp := next * float64(d+1) / (float64(i+1) * one)
+			sum += tmp
+			next = path[i].weight - tmp*zero*float64(d-i)/float64(d+1)
+		} else if zero != 0 {
+			sum += path[i].weight * float64(d+1) / (zero * float64(d-i))
+		}
+	}
+	return sum
+}
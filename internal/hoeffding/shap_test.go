@@ -0,0 +1,172 @@
+package hoeffding_test
+
+import (
+	"sort"
+
+	"github.com/bsm/reason/internal/hoeffding"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TreeSHAP", func() {
+
+	leaf := func(cover float64, values ...float64) *hoeffding.SHAPNode {
+		return &hoeffding.SHAPNode{Cover: cover, Values: values}
+	}
+	split := func(feature string, hot int, children ...*hoeffding.SHAPNode) *hoeffding.SHAPNode {
+		return &hoeffding.SHAPNode{Feature: feature, Hot: hot, Children: children}
+	}
+
+	It("should calculate contributions of single splits", func() {
+		res := hoeffding.TreeSHAP(split("a", 1, leaf(1, 0), leaf(1, 1)))
+		Expect(res.Expected).To(Equal([]float64{0.5}))
+		Expect(res.Output).To(Equal([]float64{1}))
+		Expect(res.Features).To(Equal(map[string][]float64{"a": {0.5}}))
+	})
+
+	DescribeTable("should match exact shapley values",
+		func(root *hoeffding.SHAPNode) {
+			res := hoeffding.TreeSHAP(root)
+			exp := bruteForceSHAP(root)
+			for feature := range res.Features {
+				Expect(exp).To(HaveKey(feature))
+			}
+			for feature, phi := range exp {
+				act := res.Features[feature]
+				for j := range phi {
+					v := 0.0
+					if act != nil {
+						v = act[j]
+					}
+					Expect(v).To(BeNumerically("~", phi[j], 1e-9), "feature %s, output %d", feature, j)
+				}
+			}
+
+			for j := range res.Output {
+				sum := res.Expected[j]
+				for _, phi := range res.Features {
+					sum += phi[j]
+				}
+				Expect(sum).To(BeNumerically("~", res.Output[j], 1e-9))
+			}
+		},
+
+		Entry("nested", split("a", 1,
+			leaf(3, 1, 0),
+			split("b", 0,
+				leaf(2, 4, 1),
+				leaf(1, -2, 3),
+			),
+		)),
+		Entry("repeated features", split("a", 1,
+			leaf(3, 1),
+			split("b", 1,
+				leaf(2, 4),
+				split("a", 0,
+					leaf(1, 3),
+					split("c", 2, leaf(1, 7), leaf(2, 5), leaf(4, -1)),
+				),
+			),
+		)),
+		Entry("unroutable", split("a", 0,
+			split("b", -1, leaf(2, 4), leaf(1, -2)),
+			split("c", 1, leaf(1, 3), leaf(0, 9)),
+		)),
+	)
+
+})
+
+// bruteForceSHAP calculates exact shapley values, using the covers
+// to estimate the conditional expectations of feature subsets.
+func bruteForceSHAP(root *hoeffding.SHAPNode) map[string][]float64 {
+	var cover func(*hoeffding.SHAPNode) float64
+	cover = func(n *hoeffding.SHAPNode) float64 {
+		if len(n.Children) == 0 {
+			return n.Cover
+		}
+		sum := 0.0
+		for _, c := range n.Children {
+			sum += cover(c)
+		}
+		return sum
+	}
+
+	var condExp func(*hoeffding.SHAPNode, map[string]bool) []float64
+	condExp = func(n *hoeffding.SHAPNode, known map[string]bool) []float64 {
+		if len(n.Children) == 0 {
+			return n.Values
+		}
+		if n.Hot > -1 && known[n.Feature] {
+			return condExp(n.Children[n.Hot], known)
+		}
+
+		total := cover(n)
+		var res []float64
+		for _, c := range n.Children {
+			for j, v := range condExp(c, known) {
+				for len(res) <= j {
+					res = append(res, 0)
+				}
+				res[j] += v * cover(c) / total
+			}
+		}
+		return res
+	}
+
+	fset := make(map[string]bool)
+	var collect func(*hoeffding.SHAPNode)
+	collect = func(n *hoeffding.SHAPNode) {
+		if len(n.Children) != 0 {
+			fset[n.Feature] = true
+		}
+		for _, c := range n.Children {
+			collect(c)
+		}
+	}
+	collect(root)
+
+	features := make([]string, 0, len(fset))
+	for f := range fset {
+		features = append(features, f)
+	}
+	sort.Strings(features)
+
+	factorial := func(n int) float64 {
+		res := 1.0
+		for i := 2; i <= n; i++ {
+			res *= float64(i)
+		}
+		return res
+	}
+
+	m := len(features)
+	res := make(map[string][]float64, m)
+	for i, feature := range features {
+		for mask := 0; mask < 1<<uint(m); mask++ {
+			if mask&(1<<uint(i)) != 0 {
+				continue
+			}
+
+			known := make(map[string]bool)
+			for k, f := range features {
+				if mask&(1<<uint(k)) != 0 {
+					known[f] = true
+				}
+			}
+			size := len(known)
+			without := condExp(root, known)
+			known[feature] = true
+			with := condExp(root, known)
+
+			scale := factorial(size) * factorial(m-size-1) / factorial(m)
+			for j := range with {
+				for len(res[feature]) <= j {
+					res[feature] = append(res[feature], 0)
+				}
+				res[feature][j] += scale * (with[j] - without[j])
+			}
+		}
+	}
+	return res
+}
@@ -0,0 +1,14 @@
+package protoio
+
+import "github.com/gogo/protobuf/proto"
+
+// Marshal marshals a message deterministically, map fields are encoded in
+// the order of their keys.
+func Marshal(m proto.Message) ([]byte, error) {
+	b := proto.NewBuffer(nil)
+	b.SetDeterministic(true)
+	if err := b.Marshal(m); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
@@ -130,9 +130,10 @@ func (w *Writer) WriteStringField(tag uint32, s string) error {
 	return err
 }
 
-// WriteMessageField writes a message.
+// WriteMessageField writes a message. Messages are
+// marshalled deterministically.
 func (w *Writer) WriteMessageField(tag uint32, m proto.Message) error {
-	data, err := proto.Marshal(m)
+	data, err := Marshal(m)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,41 @@
+package protoio_test
+
+import (
+	"testing"
+
+	"github.com/bsm/reason/internal/protoio"
+	"github.com/bsm/reason/util"
+	"github.com/gogo/protobuf/proto"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Marshal", func() {
+	It("should marshal maps deterministically", func() {
+		msg := new(util.Vector)
+		for i := 0; i < 100; i++ {
+			msg.Set(i*1000, float64(i+1))
+		}
+
+		Expect(msg.Sparse).To(HaveLen(100))
+
+		exp, err := protoio.Marshal(msg)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(exp).To(HaveLen(proto.Size(msg)))
+
+		for n := 0; n < 10; n++ {
+			data, err := protoio.Marshal(msg)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(data).To(Equal(exp))
+		}
+
+		decoded := new(util.Vector)
+		Expect(proto.Unmarshal(exp, decoded)).To(Succeed())
+		Expect(decoded).To(Equal(msg))
+	})
+})
+
+func TestSuite(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "internal/protoio")
+}
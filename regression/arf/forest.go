@@ -0,0 +1,254 @@
+package arf
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+
+	"github.com/bsm/reason/core"
+	"github.com/bsm/reason/internal/forest"
+	"github.com/bsm/reason/internal/iocount"
+	"github.com/bsm/reason/internal/protoio"
+	"github.com/bsm/reason/regression"
+	"github.com/bsm/reason/regression/hoeffding"
+	"github.com/gogo/protobuf/proto"
+)
+
+// Forest is an adaptive random forest of Hoeffding trees.
+type Forest struct {
+	forest *forest.Forest
+	mu     sync.RWMutex
+}
+
+// New inits a new forest using a model, a target feature and a config.
+func New(model *core.Model, target string, config *Config) (*Forest, error) {
+	config = normConfig(config)
+
+	f, err := forest.New(model, target, newFactory(config), config.Config)
+	if err != nil {
+		return nil, err
+	}
+	return &Forest{forest: f}, nil
+}
+
+// Load loads a forest from a reader. The number of members is
+// determined by the stored forest.
+func Load(r io.Reader, config *Config) (*Forest, error) {
+	config = normConfig(config)
+
+	f, err := forest.Load(r, newFactory(config), config.Config)
+	if err != nil {
+		return nil, err
+	}
+	return &Forest{forest: f}, nil
+}
+
+func normConfig(c *Config) *Config {
+	var config Config
+	if c != nil {
+		config = *c
+	}
+	config.Norm()
+	return &config
+}
+
+// Size returns the number of members.
+func (f *Forest) Size() int {
+	return len(f.forest.Members)
+}
+
+// Predict appends the aggregated prediction of all members for the given
+// example x to dst. The aggregated prediction collects the mean
+// predictions of all members.
+func (f *Forest) Predict(dst regression.Predictions, x core.Example) regression.Predictions {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	var res regression.Prediction
+	for _, m := range f.forest.Members {
+		best := m.Tree.(*member).Predict(nil, x).Best()
+		if best == nil || best.IsZero() {
+			continue
+		}
+		res.Add(best.Mean(), 1)
+	}
+	return append(dst, res)
+}
+
+// Train passes an example x with a weight (usually 1.0) to the forest
+// for training.
+func (f *Forest) Train(x core.Example, weight float64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.forest.Train(x, weight)
+}
+
+// WriteTo implements io.WriterTo
+func (f *Forest) WriteTo(w io.Writer) (int64, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.forest.WriteTo(w)
+}
+
+// --------------------------------------------------------------------
+
+type factory struct {
+	model  *core.Model
+	target *core.Feature
+	config hoeffding.Config
+}
+
+func newFactory(config *Config) forest.NewFactory {
+	return func(model *core.Model, target string) (forest.Factory, error) {
+		feat := model.Feature(target)
+		if feat == nil {
+			return nil, fmt.Errorf("arf: unknown feature %q", target)
+		} else if !feat.Kind.IsNumerical() {
+			return nil, fmt.Errorf("arf: feature %q is not numerical", target)
+		}
+
+		treeConfig := config.Tree
+		if treeConfig.FeatureSubspace == 0 {
+			treeConfig.FeatureSubspace = forest.SubspaceSize(model)
+		}
+		return &factory{model: model, target: feat, config: treeConfig}, nil
+	}
+}
+
+func (f *factory) New(seed int64) (forest.Tree, error) {
+	config := f.config
+	config.Seed = seed
+
+	tree, err := hoeffding.New(f.model, f.target.Name, &config)
+	if err != nil {
+		return nil, err
+	}
+	return newMember(tree, f.target), nil
+}
+
+func (f *factory) Load(r io.Reader, seed int64) (forest.Tree, error) {
+	config := f.config
+	config.Seed = seed
+
+	rp := &protoio.Reader{Reader: bufio.NewReader(r)}
+	m := newMember(nil, f.target)
+
+	for {
+		tag, wire, err := rp.ReadField()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		switch tag {
+		case 1: // tree
+			if wire != proto.WireBytes {
+				return nil, proto.ErrInternalBadWireType
+			}
+			str, err := rp.ReadString()
+			if err != nil {
+				return nil, err
+			}
+			if m.Tree, err = hoeffding.Load(bytes.NewReader([]byte(str)), &config); err != nil {
+				return nil, err
+			}
+		case 2, 3: // min, max
+			if wire != proto.WireFixed64 {
+				return nil, proto.ErrInternalBadWireType
+			}
+			v, err := rp.ReadDouble()
+			if err != nil {
+				return nil, err
+			}
+			if tag == 2 {
+				m.min = v
+			} else {
+				m.max = v
+			}
+		default:
+			return nil, fmt.Errorf("arf: unexpected field tag %d", tag)
+		}
+	}
+
+	if m.Tree == nil {
+		return nil, fmt.Errorf("arf: invalid member")
+	}
+	return m, nil
+}
+
+// --------------------------------------------------------------------
+
+// member tracks the range of observed target values to normalise its
+// absolute errors, as change detectors expect errors between 0 and 1.
+type member struct {
+	*hoeffding.Tree
+	target *core.Feature
+
+	min, max float64
+}
+
+func newMember(tree *hoeffding.Tree, target *core.Feature) *member {
+	return &member{Tree: tree, target: target, min: math.Inf(1), max: math.Inf(-1)}
+}
+
+func (m *member) Train(x core.Example, weight float64) {
+	m.observe(m.target.Number(x))
+	m.Tree.Train(x, weight)
+}
+
+// Error returns the absolute error of the predicted mean, relative to the
+// range of target values observed so far and capped at 1.
+func (m *member) Error(x core.Example) float64 {
+	actual := m.target.Number(x)
+	if !core.IsNum(actual) {
+		return -1
+	}
+	m.observe(actual)
+
+	best := m.Predict(nil, x).Best()
+	if best == nil || best.IsZero() {
+		return -1
+	}
+
+	rng := m.max - m.min
+	if rng <= 0 {
+		return 0
+	}
+	return math.Min(math.Abs(actual-best.Mean())/rng, 1)
+}
+
+func (m *member) observe(actual float64) {
+	if core.IsNum(actual) {
+		m.min = math.Min(m.min, actual)
+		m.max = math.Max(m.max, actual)
+	}
+}
+
+func (m *member) WriteTo(w io.Writer) (int64, error) {
+	wc := &iocount.Writer{W: w}
+	wp := &protoio.Writer{Writer: bufio.NewWriter(wc)}
+
+	buf := new(bytes.Buffer)
+	if _, err := m.Tree.WriteTo(buf); err != nil {
+		return wc.N, err
+	}
+	if err := wp.WriteStringField(1, buf.String()); err != nil {
+		return wc.N, err
+	}
+
+	for tag, v := range []float64{m.min, m.max} {
+		if err := wp.WriteField(uint32(tag+2), proto.WireFixed64); err != nil {
+			return wc.N, err
+		}
+		if err := wp.WriteDouble(v); err != nil {
+			return wc.N, err
+		}
+	}
+	return wc.N, wp.Flush()
+}
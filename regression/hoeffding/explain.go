@@ -0,0 +1,50 @@
+package hoeffding
+
+import (
+	common "github.com/bsm/reason/common/hoeffding"
+	"github.com/bsm/reason/core"
+	"github.com/bsm/reason/regression"
+	"github.com/bsm/reason/regression/hoeffding/internal"
+)
+
+// ExplainStep is a step on the decision path of an example.
+type ExplainStep struct {
+	common.DecisionStep
+
+	// The prediction at the node.
+	Prediction regression.Prediction
+}
+
+// Explanation describes the decision path of an example.
+type Explanation struct {
+	// The visited nodes, starting at the root.
+	Steps []ExplainStep
+	// The reason why the traversal has stopped.
+	Stop common.StopReason
+}
+
+// Explain traverses the tree for the given example x and returns the
+// decision path, including the conditions which led to each node.
+// The steps correspond to the predictions returned by Predict.
+func (t *Tree) Explain(x core.Example) *Explanation {
+	tree, release := t.acquire()
+	defer release()
+
+	res := new(Explanation)
+	res.Stop = tree.Explain(x, tree.Root, func(node *internal.Node, step *common.DecisionStep) {
+		res.Steps = append(res.Steps, ExplainStep{
+			DecisionStep: *step,
+			Prediction:   regression.Prediction{StreamStats: *node.Stats},
+		})
+	})
+	return res
+}
+
+// Contributions calculates additive feature contributions (SHAP values) to
+// the predicted mean for the given example x. Node weights are used as cover.
+func (t *Tree) Contributions(x core.Example) *common.Contributions {
+	tree, release := t.acquire()
+	defer release()
+
+	return tree.Contributions(x, tree.Root)
+}
@@ -42,6 +42,27 @@ var _ = Describe("SplitNode_Children", func() {
 		Expect(nodes).To(HaveKeyWithValue(72, int64(102)))
 	})
 
+	It("should iterate in order", func() {
+		for i := 50; i > 40; i-- {
+			sparse.SetRef(i, int64(i))
+		}
+
+		var indices []int
+		sparse.ForEachOrdered(func(i int, _ int64) bool {
+			indices = append(indices, i)
+			return true
+		})
+		Expect(indices).To(Equal([]int{33, 41, 42, 43, 44, 45, 46, 47, 48, 49, 50, 72}))
+		Expect(sparse.Dense).To(BeNil())
+
+		indices = indices[:0]
+		dense.ForEachOrdered(func(i int, _ int64) bool {
+			indices = append(indices, i)
+			return i < 33
+		})
+		Expect(indices).To(Equal([]int{33}))
+	})
+
 	It("should cancel iterate", func() {
 		nodes := make(map[int]int64)
 		sparse.ForEach(func(i int, nodeRef int64) bool {
@@ -1,11 +1,33 @@
 package internal
 
 import (
+	"sort"
+
+	common "github.com/bsm/reason/common/hoeffding"
 	"github.com/bsm/reason/core"
 	"github.com/bsm/reason/internal/hoeffding"
 	"github.com/bsm/reason/util"
+	"github.com/gogo/protobuf/proto"
+)
+
+// Estimated byte sizes of the feature stats structures, excluding the
+// distributions they contain.
+const (
+	featureStatsByteSize      = 3 * hoeffding.WordByteSize
+	featureStatsEntryByteSize = 4 * hoeffding.WordByteSize
+	numericalByteSize         = 5 * hoeffding.WordByteSize
+	observationByteSize       = 3 * hoeffding.WordByteSize
 )
 
+// featureStatsMapByteSize estimates the byte size of all feature stats.
+func featureStatsMapByteSize(featureStats map[string]*FeatureStats) int {
+	size := 0
+	for _, stats := range featureStats {
+		size += featureStatsEntryByteSize + stats.byteSize()
+	}
+	return size
+}
+
 // FetchCategorical fetches categorical stats.
 func (s *FeatureStats) FetchCategorical() *FeatureStats_Categorical {
 	stats := s.GetCategorical()
@@ -26,22 +48,153 @@ func (s *FeatureStats) FetchNumerical() *FeatureStats_Numerical {
 	return stats
 }
 
+// observe observes the value of a feature and returns the growth of the
+// estimated byte size.
+func (s *FeatureStats) observe(feat *core.Feature, x core.Example, targetVal, weight float64) int {
+	switch feat.Kind {
+	case core.Feature_CATEGORICAL:
+		if cat := feat.Category(x); core.IsCat(cat) {
+			return s.FetchCategorical().Add(cat, targetVal, weight)
+		}
+	case core.Feature_NUMERICAL:
+		if num := feat.Number(x); core.IsNum(num) {
+			return s.FetchNumerical().Add(num, targetVal, weight)
+		}
+	default:
+		return 0
+	}
+
+	s.MissingWeight += weight
+	return 0
+}
+
+// byteSize estimates the byte size of the feature stats.
+func (s *FeatureStats) byteSize() int {
+	size := featureStatsByteSize
+	switch kind := s.Kind.(type) {
+	case *FeatureStats_Categorical_:
+		size += hoeffding.StreamStatsDistributionByteSize(&kind.Categorical.StreamStatsDistribution)
+	case *FeatureStats_Numerical_:
+		size += numericalByteSize + observationByteSize*len(kind.Numerical.Observations)
+	}
+	return size
+}
+
+// presentFraction returns the fraction of the total weight that was
+// observed with a feature value.
+func (s *FeatureStats) presentFraction(total float64) float64 {
+	if total <= 0 || s.MissingWeight <= 0 {
+		return 1.0
+	} else if s.MissingWeight >= total {
+		return 0.0
+	}
+	return 1.0 - s.MissingWeight/total
+}
+
+// weight returns the total weight of the observations, including the
+// observations with a missing feature value.
+func (s *FeatureStats) weight() float64 {
+	sum := s.MissingWeight
+	switch kind := s.Kind.(type) {
+	case *FeatureStats_Categorical_:
+		kind.Categorical.ForEach(func(_ int, s *util.StreamStats) bool {
+			sum += s.Weight
+			return true
+		})
+	case *FeatureStats_Numerical_:
+		for _, o := range kind.Numerical.Observations {
+			sum += o.Weight
+		}
+	}
+	return sum
+}
+
 // --------------------------------------------------------------------
 
+// Size implements proto.Sizer. The distribution is embedded, so its own
+// Size must not be promoted.
+func (s *FeatureStats_Categorical) Size() int {
+	n := s.StreamStatsDistribution.Size()
+	return 1 + proto.SizeVarint(uint64(n)) + n
+}
+
+// Marshal implements proto.Marshaler. The distribution is embedded, so its
+// own Marshal must not be promoted.
+func (s *FeatureStats_Categorical) Marshal() ([]byte, error) {
+	b := proto.NewBuffer(make([]byte, 0, s.Size()))
+	if err := b.EncodeVarint(1<<3 | proto.WireBytes); err != nil {
+		return nil, err
+	}
+	if err := b.EncodeMessage(&s.StreamStatsDistribution); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
 // PostSplit calculates a post-split distribution from previous observations.
 func (s *FeatureStats_Categorical) PostSplit() *util.StreamStatsDistribution {
 	return &s.StreamStatsDistribution
 }
 
-// Add adds an observation
-func (s *FeatureStats_Categorical) Add(featCat core.Category, targetVal, weight float64) {
-	s.StreamStatsDistribution.Add(int(featCat), targetVal, weight)
+// PostSplitSubset calculates a binary post-split distribution for a
+// category subset.
+func (s *FeatureStats_Categorical) PostSplitSubset(subset []int64) *util.StreamStatsDistribution {
+	res := new(util.StreamStatsDistribution)
+	s.ForEach(func(i int, x *util.StreamStats) bool {
+		nodeIndex := 1
+		if hoeffding.SubsetContains(subset, core.Category(i)) {
+			nodeIndex = 0
+		}
+		res.Merge(nodeIndex, x)
+		return true
+	})
+	return res
+}
+
+// Subsets returns candidate subsets for binary splits. The observed
+// categories are ordered by their target mean, candidates are all
+// subsets up to each possible boundary.
+func (s *FeatureStats_Categorical) Subsets() [][]int64 {
+	type catMean struct {
+		cat  int64
+		mean float64
+	}
+
+	cms := make([]catMean, 0, s.Len())
+	s.ForEach(func(i int, x *util.StreamStats) bool {
+		if !x.IsZero() {
+			cms = append(cms, catMean{cat: int64(i), mean: x.Mean()})
+		}
+		return true
+	})
+	sort.Slice(cms, func(i, j int) bool {
+		if cms[i].mean == cms[j].mean {
+			return cms[i].cat < cms[j].cat
+		}
+		return cms[i].mean < cms[j].mean
+	})
+
+	subsets := make([][]int64, 0, len(cms))
+	for n := 1; n < len(cms); n++ {
+		subset := make([]int64, 0, n)
+		for _, cm := range cms[:n] {
+			subset = append(subset, cm.cat)
+		}
+		sort.Slice(subset, func(i, j int) bool { return subset[i] < subset[j] })
+		subsets = append(subsets, subset)
+	}
+	return subsets
+}
+
+// Add adds an observation and returns the growth of the estimated byte size.
+func (s *FeatureStats_Categorical) Add(featCat core.Category, targetVal, weight float64) int {
+	return hoeffding.AddToStreamStatsDistribution(&s.StreamStatsDistribution, int(featCat), targetVal, weight)
 }
 
 // --------------------------------------------------------------------
 
-// Add adds an observation
-func (s *FeatureStats_Numerical) Add(featVal, targetVal, weight float64) {
+// Add adds an observation and returns the growth of the estimated byte size.
+func (s *FeatureStats_Numerical) Add(featVal, targetVal, weight float64) int {
 	if len(s.Observations) == 0 || featVal < s.Min {
 		s.Min = featVal
 	}
@@ -54,13 +207,86 @@ func (s *FeatureStats_Numerical) Add(featVal, targetVal, weight float64) {
 		TargetValue:  targetVal,
 		Weight:       weight,
 	})
+	return observationByteSize
+}
+
+// PivotPoints determines the candidate split points for the observed
+// values, using the strategy of the given observer.
+func (s *FeatureStats_Numerical) PivotPoints(observer common.NumericObserver) []float64 {
+	return s.pivotPoints(observer, s.sortedObservations())
+}
+
+// PostSplits determines the candidate split points for the observed values,
+// using the strategy of the given observer, and calculates a post-split
+// distribution for each of them. Observations are sorted once and
+// accumulated in a single sweep.
+func (s *FeatureStats_Numerical) PostSplits(observer common.NumericObserver) ([]float64, []*util.StreamStatsDistribution) {
+	obs := s.sortedObservations()
+	pivots := s.pivotPoints(observer, obs)
+	posts := make([]*util.StreamStatsDistribution, len(pivots))
+
+	// accumulate observations <= pivot from the left
+	var left util.StreamStats
+	offsets := make([]int, len(pivots))
+	for i, n := 0, 0; i < len(pivots); i++ {
+		for ; n < len(obs) && obs[n].FeatureValue <= pivots[i]; n++ {
+			left.Add(obs[n].TargetValue, obs[n].Weight)
+		}
+		offsets[i] = n
+
+		posts[i] = new(util.StreamStatsDistribution)
+		if n != 0 {
+			posts[i].Merge(0, &left)
+		}
+	}
+
+	// accumulate observations > pivot from the right
+	var right util.StreamStats
+	for i, n := len(pivots)-1, len(obs); i >= 0; i-- {
+		for ; n > offsets[i]; n-- {
+			right.Add(obs[n-1].TargetValue, obs[n-1].Weight)
+		}
+		if n != len(obs) {
+			posts[i].Merge(1, &right)
+		}
+	}
+	return pivots, posts
 }
 
-// PivotPoints determines the optimum split points for the range of values.
-func (s *FeatureStats_Numerical) PivotPoints() []float64 {
+func (s *FeatureStats_Numerical) pivotPoints(observer common.NumericObserver, obs []FeatureStats_Numerical_Observation) []float64 {
+	switch observer {
+	case common.NumericObserverEBST:
+		values, _ := distinctValues(obs)
+		return hoeffding.MidPivotPoints(values)
+	case common.NumericObserverQuantileSketch:
+		return hoeffding.QuantilePivotPoints(distinctValues(obs))
+	}
 	return hoeffding.PivotPoints(s.Min, s.Max)
 }
 
+// sortedObservations returns a copy of the observations, sorted by feature
+// value.
+func (s *FeatureStats_Numerical) sortedObservations() []FeatureStats_Numerical_Observation {
+	obs := make([]FeatureStats_Numerical_Observation, len(s.Observations))
+	copy(obs, s.Observations)
+	sort.Slice(obs, func(i, j int) bool { return obs[i].FeatureValue < obs[j].FeatureValue })
+	return obs
+}
+
+// distinctValues returns the distinct values of sorted observations
+// together with their total weights.
+func distinctValues(obs []FeatureStats_Numerical_Observation) (values, weights []float64) {
+	for i, o := range obs {
+		if n := len(values); i != 0 && values[n-1] == o.FeatureValue {
+			weights[n-1] += o.Weight
+			continue
+		}
+		values = append(values, o.FeatureValue)
+		weights = append(weights, o.Weight)
+	}
+	return
+}
+
 // PostSplit calculates a post-split distribution from previous observations
 func (s *FeatureStats_Numerical) PostSplit(pivot float64) *util.StreamStatsDistribution {
 	res := new(util.StreamStatsDistribution)
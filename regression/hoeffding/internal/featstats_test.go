@@ -1,7 +1,9 @@
 package internal_test
 
 import (
+	common "github.com/bsm/reason/common/hoeffding"
 	"github.com/bsm/reason/regression/hoeffding/internal"
+	"github.com/bsm/reason/util"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -29,13 +31,28 @@ var _ = Describe("FeatureStats_Numerical", func() {
 	})
 
 	It("should calculate pivot points", func() {
-		pp := subject.PivotPoints()
+		pp := subject.PivotPoints(common.NumericObserverGaussian)
 		Expect(pp).To(HaveLen(11))
 		Expect(pp[0]).To(BeNumerically("~", 1.8, 0.01))
 		Expect(pp[1]).To(BeNumerically("~", 2.4, 0.01))
 		Expect(pp[9]).To(BeNumerically("~", 7.2, 0.01))
 		Expect(pp[10]).To(BeNumerically("~", 7.8, 0.01))
-		Expect(new(internal.FeatureStats_Numerical).PivotPoints()).To(BeEmpty())
+		Expect(new(internal.FeatureStats_Numerical).PivotPoints(common.NumericObserverGaussian)).To(BeEmpty())
+	})
+
+	It("should calculate pivot points by observer", func() {
+		subject.Add(4.2, 3.3, 2)
+		pp := subject.PivotPoints(common.NumericObserverEBST)
+		Expect(pp).To(HaveLen(2))
+		Expect(pp[0]).To(BeNumerically("~", 2.7, 0.01))
+		Expect(pp[1]).To(BeNumerically("~", 6.3, 0.01))
+
+		pp = subject.PivotPoints(common.NumericObserverQuantileSketch)
+		Expect(pp).To(HaveLen(2))
+		Expect(pp[0]).To(BeNumerically("~", 2.7, 0.01))
+		Expect(pp[1]).To(BeNumerically("~", 6.3, 0.01))
+		Expect(new(internal.FeatureStats_Numerical).PivotPoints(common.NumericObserverEBST)).To(BeEmpty())
+		Expect(new(internal.FeatureStats_Numerical).PivotPoints(common.NumericObserverQuantileSketch)).To(BeEmpty())
 	})
 
 	It("should calculate post-splits", func() {
@@ -50,6 +67,54 @@ var _ = Describe("FeatureStats_Numerical", func() {
 		Expect(s2.Get(1).Sum).To(Equal(2.2))
 	})
 
+	It("should calculate post-splits for all pivot points", func() {
+		subject.Add(4.2, 3.3, 2)
+		subject.Add(0.6, 1.5, 1)
+
+		for _, observer := range []common.NumericObserver{
+			common.NumericObserverGaussian,
+			common.NumericObserverEBST,
+			common.NumericObserverQuantileSketch,
+		} {
+			pivots, posts := subject.PostSplits(observer)
+			Expect(pivots).To(Equal(subject.PivotPoints(observer)))
+			Expect(posts).To(HaveLen(len(pivots)))
+
+			for i, pivot := range pivots {
+				exp := subject.PostSplit(pivot)
+				Expect(posts[i].Len()).To(Equal(exp.Len()), "pivot %v", pivot)
+				exp.ForEach(func(j int, s *util.StreamStats) bool {
+					Expect(posts[i].Get(j).Weight).To(BeNumerically("~", s.Weight, 1e-9))
+					Expect(posts[i].Get(j).Sum).To(BeNumerically("~", s.Sum, 1e-9))
+					Expect(posts[i].Get(j).SumSquares).To(BeNumerically("~", s.SumSquares, 1e-9))
+					return true
+				})
+			}
+		}
+
+		pivots, posts := new(internal.FeatureStats_Numerical).PostSplits(common.NumericObserverEBST)
+		Expect(pivots).To(BeEmpty())
+		Expect(posts).To(BeEmpty())
+	})
+
+	It("should handle zero and negative values", func() {
+		subject = new(internal.FeatureStats_Numerical)
+		subject.Add(0.0, 1.0, 1.0)
+		subject.Add(-3.0, 2.0, 1.0)
+		subject.Add(-1.5, 3.0, 1.0)
+		Expect(subject.Min).To(Equal(-3.0))
+		Expect(subject.Max).To(Equal(0.0))
+
+		pp := subject.PivotPoints(common.NumericObserverGaussian)
+		Expect(pp).To(HaveLen(11))
+		Expect(pp[0]).To(BeNumerically("~", -2.75, 0.01))
+		Expect(pp[10]).To(BeNumerically("~", -0.25, 0.01))
+
+		s := subject.PostSplit(-2.0)
+		Expect(s.Get(0).Sum).To(Equal(2.0))
+		Expect(s.Get(1).Sum).To(Equal(4.0))
+	})
+
 })
 
 var _ = Describe("FeatureStats_Categorical", func() {
@@ -9,6 +9,7 @@ It is generated from these files:
 
 It has these top-level messages:
 	Tree
+	Snapshot
 	FeatureStats
 	Node
 	SplitNode
@@ -51,6 +52,42 @@ func (m *Tree) String() string            { return proto.CompactTextString(m) }
 func (*Tree) ProtoMessage()               {}
 func (*Tree) Descriptor() ([]byte, []int) { return fileDescriptorInternal, []int{0} }
 
+// Snapshot is a flattened, read-only representation of a tree, used
+// for inference. Nodes are referenced by their index, the root node
+// has index 0.
+type Snapshot struct {
+	// The underlying model.
+	Model *blacksquaremedia_reason_core.Model `protobuf:"bytes,1,opt,name=model" json:"model,omitempty"`
+	// The target feature.
+	Target string `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`
+	// The names of the split features.
+	FeatureNames []string `protobuf:"bytes,3,rep,name=feature_names,json=featureNames" json:"feature_names,omitempty"`
+	// The split feature of each node, as an index of feature_names.
+	// Leaves are marked as -1.
+	Features []int64 `protobuf:"varint,4,rep,packed,name=features" json:"features,omitempty"`
+	// The pivot value of each node (numerical predictors).
+	Pivots []float64 `protobuf:"fixed64,5,rep,packed,name=pivots" json:"pivots,omitempty"`
+	// The default child index of each node, -1 if none.
+	DefaultChildren []int64 `protobuf:"varint,6,rep,packed,name=default_children,json=defaultChildren" json:"default_children,omitempty"`
+	// The offsets of the child indices of each node within children,
+	// followed by the total number of children.
+	ChildOffsets []int64 `protobuf:"varint,7,rep,packed,name=child_offsets,json=childOffsets" json:"child_offsets,omitempty"`
+	// The node indices of all children, -1 if missing.
+	Children []int64 `protobuf:"varint,8,rep,packed,name=children" json:"children,omitempty"`
+	// The offsets of the category subsets of each node within subsets,
+	// followed by the total number of subset categories.
+	SubsetOffsets []int64 `protobuf:"varint,9,rep,packed,name=subset_offsets,json=subsetOffsets" json:"subset_offsets,omitempty"`
+	// The (sorted) category subsets of binary categorical splits.
+	Subsets []int64 `protobuf:"varint,10,rep,packed,name=subsets" json:"subsets,omitempty"`
+	// The prediction stats of each node.
+	Predictions []blacksquaremedia_reason_util.StreamStats `protobuf:"bytes,11,rep,name=predictions" json:"predictions"`
+}
+
+func (m *Snapshot) Reset()                    { *m = Snapshot{} }
+func (m *Snapshot) String() string            { return proto.CompactTextString(m) }
+func (*Snapshot) ProtoMessage()               {}
+func (*Snapshot) Descriptor() ([]byte, []int) { return fileDescriptorInternal, []int{1} }
+
 // FeatureStats instances maintain stats based on
 // observation of a particular feature.
 type FeatureStats struct {
@@ -58,12 +95,14 @@ type FeatureStats struct {
 	//	*FeatureStats_Numerical_
 	//	*FeatureStats_Categorical_
 	Kind isFeatureStats_Kind `protobuf_oneof:"kind"`
+	// Weight of observations with a missing feature value.
+	MissingWeight float64 `protobuf:"fixed64,3,opt,name=missing_weight,json=missingWeight,proto3" json:"missing_weight,omitempty"`
 }
 
 func (m *FeatureStats) Reset()                    { *m = FeatureStats{} }
 func (m *FeatureStats) String() string            { return proto.CompactTextString(m) }
 func (*FeatureStats) ProtoMessage()               {}
-func (*FeatureStats) Descriptor() ([]byte, []int) { return fileDescriptorInternal, []int{1} }
+func (*FeatureStats) Descriptor() ([]byte, []int) { return fileDescriptorInternal, []int{2} }
 
 type isFeatureStats_Kind interface {
 	isFeatureStats_Kind()
@@ -184,7 +223,7 @@ func (m *FeatureStats_Numerical) Reset()         { *m = FeatureStats_Numerical{}
 func (m *FeatureStats_Numerical) String() string { return proto.CompactTextString(m) }
 func (*FeatureStats_Numerical) ProtoMessage()    {}
 func (*FeatureStats_Numerical) Descriptor() ([]byte, []int) {
-	return fileDescriptorInternal, []int{1, 0}
+	return fileDescriptorInternal, []int{2, 0}
 }
 
 type FeatureStats_Numerical_Observation struct {
@@ -197,7 +236,7 @@ func (m *FeatureStats_Numerical_Observation) Reset()         { *m = FeatureStats
 func (m *FeatureStats_Numerical_Observation) String() string { return proto.CompactTextString(m) }
 func (*FeatureStats_Numerical_Observation) ProtoMessage()    {}
 func (*FeatureStats_Numerical_Observation) Descriptor() ([]byte, []int) {
-	return fileDescriptorInternal, []int{1, 0, 0}
+	return fileDescriptorInternal, []int{2, 0, 0}
 }
 
 type FeatureStats_Categorical struct {
@@ -208,7 +247,7 @@ func (m *FeatureStats_Categorical) Reset()         { *m = FeatureStats_Categoric
 func (m *FeatureStats_Categorical) String() string { return proto.CompactTextString(m) }
 func (*FeatureStats_Categorical) ProtoMessage()    {}
 func (*FeatureStats_Categorical) Descriptor() ([]byte, []int) {
-	return fileDescriptorInternal, []int{1, 1}
+	return fileDescriptorInternal, []int{2, 1}
 }
 
 // Node is a tree node
@@ -226,7 +265,7 @@ type Node struct {
 func (m *Node) Reset()                    { *m = Node{} }
 func (m *Node) String() string            { return proto.CompactTextString(m) }
 func (*Node) ProtoMessage()               {}
-func (*Node) Descriptor() ([]byte, []int) { return fileDescriptorInternal, []int{2} }
+func (*Node) Descriptor() ([]byte, []int) { return fileDescriptorInternal, []int{3} }
 
 type isNode_Kind interface {
 	isNode_Kind()
@@ -343,14 +382,29 @@ type SplitNode struct {
 	Feature string `protobuf:"bytes,1,opt,name=feature,proto3" json:"feature,omitempty"`
 	// The pivot value for binary splits (numerical predictors).
 	Pivot float64 `protobuf:"fixed64,2,opt,name=pivot,proto3" json:"pivot,omitempty"`
+	// The (sorted) category subset for binary splits (categorical predictors).
+	// Examples with a category in the subset are routed to the first child,
+	// all others to the second.
+	Subset []int64 `protobuf:"varint,6,rep,packed,name=subset" json:"subset,omitempty"`
 	// The child references.
 	Children SplitNode_Children `protobuf:"bytes,3,opt,name=children" json:"children"`
+	// Observation stats, by feature, maintained in EFDT mode only.
+	FeatureStats map[string]*FeatureStats `protobuf:"bytes,4,rep,name=feature_stats,json=featureStats" json:"feature_stats,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value"`
+	// Weight at the time of the last split re-evaluation.
+	WeightAtLastEval float64 `protobuf:"fixed64,5,opt,name=weight_at_last_eval,json=weightAtLastEval,proto3" json:"weight_at_last_eval,omitempty"`
+	// The default child for examples with a missing feature value,
+	// stored as child index + 1 (0 if none).
+	DefaultChild int64 `protobuf:"varint,7,opt,name=default_child,json=defaultChild,proto3" json:"default_child,omitempty"`
+	// Weight of examples routed to each child, by child index.
+	ChildWeights blacksquaremedia_reason_util.Vector `protobuf:"bytes,8,opt,name=child_weights,json=childWeights" json:"child_weights"`
+	// Weight of examples with a missing feature value.
+	MissingWeight float64 `protobuf:"fixed64,9,opt,name=missing_weight,json=missingWeight,proto3" json:"missing_weight,omitempty"`
 }
 
 func (m *SplitNode) Reset()                    { *m = SplitNode{} }
 func (m *SplitNode) String() string            { return proto.CompactTextString(m) }
 func (*SplitNode) ProtoMessage()               {}
-func (*SplitNode) Descriptor() ([]byte, []int) { return fileDescriptorInternal, []int{3} }
+func (*SplitNode) Descriptor() ([]byte, []int) { return fileDescriptorInternal, []int{4} }
 
 // Children is a collection of child node references.
 type SplitNode_Children struct {
@@ -362,7 +416,7 @@ type SplitNode_Children struct {
 func (m *SplitNode_Children) Reset()                    { *m = SplitNode_Children{} }
 func (m *SplitNode_Children) String() string            { return proto.CompactTextString(m) }
 func (*SplitNode_Children) ProtoMessage()               {}
-func (*SplitNode_Children) Descriptor() ([]byte, []int) { return fileDescriptorInternal, []int{3, 0} }
+func (*SplitNode_Children) Descriptor() ([]byte, []int) { return fileDescriptorInternal, []int{4, 0} }
 
 // LeafNode instances are the leaves within the tree.
 type LeafNode struct {
@@ -372,15 +426,19 @@ type LeafNode struct {
 	WeightAtLastEval float64 `protobuf:"fixed64,2,opt,name=weight_at_last_eval,json=weightAtLastEval,proto3" json:"weight_at_last_eval,omitempty"`
 	// Status indicator.
 	IsDisabled bool `protobuf:"varint,3,opt,name=is_disabled,json=isDisabled,proto3" json:"is_disabled,omitempty"`
+	// Features that are no longer observed, because their merit was
+	// found to be confidently below the best candidate.
+	IgnoredFeatures []string `protobuf:"bytes,4,rep,name=ignored_features,json=ignoredFeatures" json:"ignored_features,omitempty"`
 }
 
 func (m *LeafNode) Reset()                    { *m = LeafNode{} }
 func (m *LeafNode) String() string            { return proto.CompactTextString(m) }
 func (*LeafNode) ProtoMessage()               {}
-func (*LeafNode) Descriptor() ([]byte, []int) { return fileDescriptorInternal, []int{4} }
+func (*LeafNode) Descriptor() ([]byte, []int) { return fileDescriptorInternal, []int{5} }
 
 func init() {
 	proto.RegisterType((*Tree)(nil), "blacksquaremedia.reason.regression.hoeffding.Tree")
+	proto.RegisterType((*Snapshot)(nil), "blacksquaremedia.reason.regression.hoeffding.Snapshot")
 	proto.RegisterType((*FeatureStats)(nil), "blacksquaremedia.reason.regression.hoeffding.FeatureStats")
 	proto.RegisterType((*FeatureStats_Numerical)(nil), "blacksquaremedia.reason.regression.hoeffding.FeatureStats.Numerical")
 	proto.RegisterType((*FeatureStats_Numerical_Observation)(nil), "blacksquaremedia.reason.regression.hoeffding.FeatureStats.Numerical.Observation")
@@ -396,57 +454,72 @@ func init() {
 }
 
 var fileDescriptorInternal = []byte{
-	// 820 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x95, 0x4f, 0x8f, 0xdb, 0x44,
-	0x14, 0xc0, 0xe3, 0xd8, 0x09, 0xc9, 0xf3, 0x22, 0xca, 0x80, 0xaa, 0x28, 0x12, 0xbb, 0xcb, 0x56,
-	0xa0, 0x20, 0xb5, 0x8e, 0x14, 0xc4, 0x9f, 0xf6, 0x02, 0xec, 0xb6, 0x55, 0x0e, 0x4b, 0x77, 0x35,
-	0x01, 0x0e, 0x5c, 0xa2, 0xb1, 0x3d, 0x71, 0x86, 0xb5, 0x3d, 0x61, 0x66, 0x1c, 0x5a, 0xb8, 0xf1,
-	0x09, 0x38, 0xf3, 0x45, 0xf8, 0x06, 0xa8, 0x47, 0x8e, 0x9c, 0x2a, 0x2a, 0x8e, 0x48, 0x7c, 0x06,
-	0x34, 0x7f, 0x9c, 0x78, 0x85, 0x56, 0x6a, 0xb6, 0xbd, 0x58, 0xef, 0x3d, 0x3f, 0xff, 0xde, 0x9f,
-	0x79, 0x6f, 0x0c, 0xb7, 0x05, 0xcd, 0x04, 0x95, 0x92, 0xf1, 0x72, 0xbc, 0xe4, 0x74, 0xb1, 0x48,
-	0x59, 0x99, 0x8d, 0x59, 0xa9, 0xa8, 0x28, 0x49, 0xbe, 0x11, 0xa2, 0x95, 0xe0, 0x8a, 0xa3, 0xdb,
-	0x71, 0x4e, 0x92, 0x0b, 0xf9, 0x7d, 0x45, 0x04, 0x2d, 0x68, 0xca, 0x48, 0x24, 0x28, 0x91, 0xbc,
-	0x8c, 0xb6, 0x94, 0x68, 0x43, 0x19, 0xbe, 0x97, 0x31, 0xb5, 0xac, 0xe2, 0x28, 0xe1, 0xc5, 0x38,
-	0x96, 0xc5, 0xd8, 0xfa, 0x8e, 0x13, 0x2e, 0xa8, 0x79, 0x58, 0xe8, 0x55, 0x6e, 0x95, 0x62, 0xb9,
-	0x79, 0x38, 0xb7, 0x3b, 0x0d, 0xb7, 0x8c, 0x67, 0x7c, 0x6c, 0xcc, 0x71, 0xb5, 0x30, 0x9a, 0x51,
-	0x8c, 0x64, 0xdd, 0x8f, 0x7e, 0xf3, 0x20, 0xf8, 0x4a, 0x50, 0x8a, 0xee, 0x42, 0xa7, 0xe0, 0x29,
-	0xcd, 0x07, 0xde, 0xa1, 0x37, 0x0a, 0x27, 0xb7, 0xa2, 0xab, 0x6a, 0x30, 0x29, 0x7d, 0xa9, 0x5d,
-	0xb1, 0xfd, 0x02, 0xdd, 0x84, 0xae, 0x22, 0x22, 0xa3, 0x6a, 0xd0, 0x3e, 0xf4, 0x46, 0x7d, 0xec,
-	0x34, 0x84, 0x20, 0x10, 0x9c, 0xab, 0x81, 0x7f, 0xe8, 0x8d, 0x7c, 0x6c, 0x64, 0x34, 0x85, 0x4e,
-	0xc9, 0x53, 0x2a, 0x07, 0xc1, 0xa1, 0x3f, 0x0a, 0x27, 0x93, 0x68, 0x97, 0x56, 0x45, 0x8f, 0x78,
-	0x4a, 0xb1, 0x05, 0x1c, 0xfd, 0x13, 0xc0, 0xde, 0x43, 0x4a, 0x54, 0x25, 0xe8, 0x4c, 0x11, 0x25,
-	0x51, 0x0a, 0xfd, 0xb2, 0x2a, 0xa8, 0x60, 0x09, 0xa9, 0xab, 0xb8, 0xbf, 0x1b, 0xbe, 0x89, 0x8b,
-	0x1e, 0xd5, 0xac, 0x69, 0x0b, 0x6f, 0xc1, 0xe8, 0x3b, 0x08, 0x13, 0xa2, 0x68, 0xc6, 0x6d, 0x9c,
-	0xb6, 0x89, 0xf3, 0xf0, 0x25, 0xe2, 0x9c, 0x6c, 0x69, 0xd3, 0x16, 0x6e, 0xc2, 0x87, 0xbf, 0xb6,
-	0xa1, 0xbf, 0x49, 0x03, 0xdd, 0x00, 0xbf, 0x60, 0xa5, 0xa9, 0xcc, 0xc3, 0x5a, 0x34, 0x16, 0xf2,
-	0xd8, 0xe4, 0xa0, 0x2d, 0xe4, 0x31, 0xfa, 0x11, 0xf6, 0x78, 0x2c, 0xa9, 0x58, 0x13, 0xc5, 0x78,
-	0x29, 0x07, 0xbe, 0xe9, 0xf2, 0xf9, 0xab, 0x68, 0x43, 0x74, 0xb6, 0x05, 0x1f, 0x07, 0x4f, 0x9f,
-	0x1d, 0xb4, 0xf0, 0xa5, 0x58, 0xc3, 0x02, 0xc2, 0x86, 0x0b, 0xba, 0x05, 0xaf, 0x2f, 0x2c, 0x68,
-	0xbe, 0x26, 0x79, 0x45, 0x5d, 0xe2, 0x7b, 0xce, 0xf8, 0x8d, 0xb6, 0xa1, 0x77, 0x61, 0xcf, 0x0e,
-	0x8b, 0xf3, 0xb1, 0xa5, 0x84, 0xd6, 0x66, 0x5d, 0x6e, 0x42, 0xf7, 0x07, 0xca, 0xb2, 0xa5, 0x9d,
-	0x23, 0x0f, 0x3b, 0x6d, 0x98, 0x42, 0xd8, 0x68, 0x1d, 0xfa, 0x1a, 0x3a, 0x52, 0x27, 0xec, 0x4e,
-	0xfe, 0xa3, 0x2b, 0x4b, 0x36, 0xbb, 0x32, 0x53, 0x82, 0x92, 0xc2, 0x54, 0x78, 0x9f, 0x49, 0x25,
-	0x58, 0x5c, 0x99, 0xba, 0x7a, 0xba, 0xae, 0x3f, 0x9e, 0x1d, 0x78, 0xd8, 0xd2, 0x8e, 0xbb, 0x10,
-	0x5c, 0xb0, 0x32, 0x3d, 0xfa, 0xd7, 0x83, 0x40, 0x4f, 0x1f, 0xfa, 0xec, 0x72, 0x9c, 0x0f, 0x5e,
-	0x38, 0x8e, 0x23, 0xa2, 0x53, 0x08, 0x72, 0x4a, 0x16, 0x6e, 0x72, 0x3e, 0xde, 0xed, 0x68, 0x4e,
-	0x29, 0x59, 0xe8, 0x34, 0xa6, 0x2d, 0x6c, 0x28, 0xe8, 0x0c, 0x3a, 0x72, 0x95, 0x33, 0xdb, 0x9c,
-	0x70, 0xf2, 0xc9, 0x6e, 0xb8, 0x99, 0xfe, 0xd4, 0xf1, 0x2c, 0x67, 0x53, 0xf0, 0xcf, 0x3e, 0xf4,
-	0x37, 0xaf, 0xd1, 0x00, 0x5e, 0x73, 0xe7, 0x66, 0xea, 0xee, 0xe3, 0x5a, 0x45, 0x6f, 0x43, 0x67,
-	0xc5, 0xd6, 0x5c, 0xb9, 0xa3, 0xb3, 0x0a, 0x8a, 0xa1, 0x97, 0x2c, 0x59, 0x9e, 0x0a, 0x5a, 0xba,
-	0xcc, 0x3e, 0xbf, 0x66, 0x66, 0xd1, 0x89, 0xe3, 0xb8, 0x99, 0xdb, 0x70, 0x87, 0x7f, 0x79, 0xd0,
-	0xab, 0x5f, 0xea, 0x34, 0x52, 0x5a, 0x4a, 0x9d, 0x9e, 0x3f, 0xf2, 0xb1, 0x55, 0x50, 0x0a, 0x5d,
-	0xb9, 0x22, 0x42, 0xea, 0xc1, 0xd2, 0x8b, 0x70, 0xfa, 0xb2, 0x49, 0x44, 0x33, 0x83, 0x7b, 0x50,
-	0x2a, 0xf1, 0x04, 0x3b, 0x36, 0x7a, 0x07, 0xc0, 0x4a, 0xf3, 0x84, 0xac, 0xdc, 0x6d, 0xd7, 0xb7,
-	0x96, 0x13, 0xb2, 0x1a, 0xde, 0x85, 0xb0, 0xf1, 0x95, 0x5e, 0xda, 0x0b, 0xfa, 0xc4, 0xb4, 0xd1,
-	0xc7, 0x5a, 0xd4, 0xb9, 0x6f, 0xa7, 0xdf, 0xc7, 0x56, 0xb9, 0xd7, 0xfe, 0xd4, 0x3b, 0xfa, 0xbd,
-	0x0d, 0xbd, 0xfa, 0xc8, 0x51, 0xb1, 0x5d, 0xa8, 0x7a, 0x02, 0x75, 0x4d, 0xd3, 0xeb, 0x4d, 0xd0,
-	0xa5, 0x2d, 0xb7, 0xf5, 0xd4, 0xab, 0x69, 0xaf, 0xd3, 0x3b, 0xf0, 0x96, 0xdd, 0xb4, 0x39, 0x51,
-	0xf3, 0x9c, 0x48, 0x35, 0xa7, 0x6b, 0x77, 0xe1, 0x79, 0xf8, 0x86, 0x7d, 0xf5, 0x85, 0x3a, 0x25,
-	0x52, 0x3d, 0x58, 0x93, 0x1c, 0x1d, 0x40, 0xc8, 0xe4, 0x3c, 0x65, 0x92, 0xc4, 0x39, 0x4d, 0x4d,
-	0x17, 0x7a, 0x18, 0x98, 0x5e, 0x2f, 0x63, 0x19, 0xfe, 0x04, 0x6f, 0xfe, 0x2f, 0x64, 0xb3, 0x19,
-	0x7d, 0xdb, 0x8c, 0xf3, 0x66, 0x33, 0xc2, 0xc9, 0xbd, 0xeb, 0x5f, 0x5d, 0x8d, 0x46, 0x1e, 0x9f,
-	0x3d, 0x7d, 0xbe, 0xdf, 0xfa, 0xf3, 0xf9, 0xbe, 0xf7, 0xcb, 0xdf, 0xfb, 0x2d, 0x78, 0x3f, 0xe1,
-	0xc5, 0x0b, 0x70, 0x8f, 0xdf, 0x98, 0xd6, 0xe0, 0x73, 0xfd, 0xb3, 0x94, 0xdf, 0xf6, 0xea, 0x1f,
-	0x7d, 0xdc, 0x35, 0xbf, 0xcf, 0x0f, 0xff, 0x0b, 0x00, 0x00, 0xff, 0xff, 0x79, 0xe4, 0x00, 0xe6,
-	0x19, 0x08, 0x00, 0x00,
+	// 1065 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x56, 0xdd, 0x6e, 0x1b, 0x45,
+	0x14, 0xf6, 0x7a, 0x6d, 0xc7, 0x7b, 0xd6, 0x21, 0x61, 0xa8, 0xaa, 0x95, 0x25, 0x12, 0x93, 0x52,
+	0xe4, 0x4a, 0xed, 0x5a, 0x0a, 0xe2, 0xa7, 0xbd, 0x01, 0x92, 0xb4, 0x32, 0x52, 0x48, 0xc2, 0x06,
+	0x8a, 0xc4, 0x8d, 0x35, 0xf6, 0x8e, 0x9d, 0x21, 0xbb, 0x3b, 0x66, 0x66, 0x36, 0xb4, 0xf0, 0x12,
+	0x88, 0x4b, 0x1e, 0x04, 0x5e, 0xa1, 0x97, 0x48, 0xdc, 0x70, 0x55, 0x51, 0x71, 0x8f, 0xc4, 0x1b,
+	0xa0, 0xf9, 0xd9, 0xf5, 0x06, 0x1a, 0x94, 0xb8, 0x20, 0x6e, 0xac, 0x39, 0x9f, 0xcf, 0x7c, 0x73,
+	0xe6, 0xfc, 0x7c, 0x3b, 0x70, 0x9b, 0x93, 0x19, 0x27, 0x42, 0x50, 0x96, 0x0d, 0x4e, 0x18, 0x99,
+	0x4e, 0x63, 0x9a, 0xcd, 0x06, 0x34, 0x93, 0x84, 0x67, 0x38, 0x29, 0x17, 0xe1, 0x9c, 0x33, 0xc9,
+	0xd0, 0xed, 0x71, 0x82, 0x27, 0xa7, 0xe2, 0xcb, 0x1c, 0x73, 0x92, 0x92, 0x98, 0xe2, 0x90, 0x13,
+	0x2c, 0x58, 0x16, 0x2e, 0x58, 0xc2, 0x92, 0xa5, 0x7b, 0x73, 0x46, 0xe5, 0x49, 0x3e, 0x0e, 0x27,
+	0x2c, 0x1d, 0x8c, 0x45, 0x3a, 0x30, 0xbe, 0x83, 0x09, 0xe3, 0x44, 0xff, 0x18, 0xd2, 0x8b, 0xdc,
+	0x72, 0x49, 0x13, 0xfd, 0x63, 0xdd, 0xee, 0x54, 0xdc, 0x66, 0x6c, 0xc6, 0x06, 0x1a, 0x1e, 0xe7,
+	0x53, 0x6d, 0x69, 0x43, 0xaf, 0x8c, 0xfb, 0xd6, 0x8f, 0x0e, 0x34, 0x3e, 0xe1, 0x84, 0xa0, 0xbb,
+	0xd0, 0x4c, 0x59, 0x4c, 0x92, 0xc0, 0xe9, 0x39, 0x7d, 0x7f, 0xfb, 0x46, 0x78, 0xd1, 0x1d, 0x74,
+	0x48, 0x1f, 0x29, 0xd7, 0xc8, 0xec, 0x40, 0xd7, 0xa1, 0x25, 0x31, 0x9f, 0x11, 0x19, 0xd4, 0x7b,
+	0x4e, 0xdf, 0x8b, 0xac, 0x85, 0x10, 0x34, 0x38, 0x63, 0x32, 0x70, 0x7b, 0x4e, 0xdf, 0x8d, 0xf4,
+	0x1a, 0x0d, 0xa1, 0x99, 0xb1, 0x98, 0x88, 0xa0, 0xd1, 0x73, 0xfb, 0xfe, 0xf6, 0x76, 0x78, 0x95,
+	0x54, 0x85, 0x07, 0x2c, 0x26, 0x91, 0x21, 0xd8, 0xfa, 0xc1, 0x85, 0xf6, 0x71, 0x86, 0xe7, 0xe2,
+	0x84, 0xc9, 0xff, 0x22, 0xfa, 0x1b, 0xb0, 0x3a, 0x25, 0x58, 0xe6, 0x9c, 0x8c, 0x32, 0x9c, 0x12,
+	0x11, 0xb8, 0x3d, 0xb7, 0xef, 0x45, 0x1d, 0x0b, 0x1e, 0x28, 0x0c, 0x75, 0xa1, 0x6d, 0x6d, 0x73,
+	0x23, 0x37, 0x2a, 0x6d, 0x45, 0x3c, 0xa7, 0x67, 0x4c, 0x8a, 0xa0, 0xd9, 0x73, 0xfb, 0x4e, 0x64,
+	0x2d, 0x74, 0x0b, 0xd6, 0x63, 0x32, 0xc5, 0x79, 0x22, 0x47, 0x93, 0x13, 0x9a, 0xc4, 0x9c, 0x64,
+	0x41, 0x4b, 0xef, 0x5d, 0xb3, 0xf8, 0xae, 0x85, 0x55, 0x0c, 0xda, 0x65, 0xc4, 0xa6, 0x53, 0x41,
+	0xa4, 0x08, 0x56, 0xb4, 0x5f, 0x47, 0x83, 0x87, 0x06, 0x53, 0x31, 0x94, 0x3c, 0x6d, 0x13, 0x43,
+	0x61, 0xa3, 0x9b, 0xf0, 0x92, 0xc8, 0xc7, 0x82, 0xc8, 0x92, 0xc1, 0xd3, 0x1e, 0xab, 0x06, 0x2d,
+	0x28, 0x02, 0x58, 0x31, 0x80, 0x08, 0x40, 0xff, 0x5f, 0x98, 0xe8, 0x63, 0xf0, 0xe7, 0x9c, 0xc4,
+	0x74, 0x22, 0x29, 0xcb, 0x44, 0xe0, 0xeb, 0xaa, 0xdd, 0xba, 0x30, 0xbd, 0xba, 0x11, 0x8f, 0x25,
+	0x27, 0x38, 0x3d, 0x96, 0x58, 0x8a, 0x9d, 0xc6, 0x93, 0xa7, 0x9b, 0xb5, 0xa8, 0xca, 0xb1, 0xf5,
+	0x5d, 0x13, 0x3a, 0x0f, 0x4c, 0x92, 0xb4, 0x0f, 0x8a, 0xc1, 0xcb, 0xf2, 0x94, 0x70, 0x3a, 0xc1,
+	0x45, 0x01, 0xf7, 0xae, 0xd6, 0x17, 0x55, 0xba, 0xf0, 0xa0, 0xe0, 0x1a, 0xd6, 0xa2, 0x05, 0x31,
+	0xfa, 0x02, 0xfc, 0x09, 0x96, 0x64, 0xc6, 0xcc, 0x39, 0x75, 0x7d, 0xce, 0x83, 0x17, 0x38, 0x67,
+	0x77, 0xc1, 0x36, 0xac, 0x45, 0x55, 0x72, 0x95, 0xf6, 0x94, 0x0a, 0x41, 0xb3, 0xd9, 0xe8, 0x2b,
+	0x42, 0x67, 0x27, 0x66, 0x06, 0x9c, 0x68, 0xd5, 0xa2, 0x9f, 0x69, 0xb0, 0xfb, 0x7d, 0x1d, 0xbc,
+	0x32, 0x5a, 0xb4, 0x0e, 0x6e, 0x4a, 0x33, 0x9d, 0x00, 0x27, 0x52, 0x4b, 0x8d, 0xe0, 0x47, 0x41,
+	0xdd, 0x22, 0xf8, 0x11, 0xfa, 0x1a, 0x3a, 0x6c, 0x2c, 0x08, 0x3f, 0xc3, 0xa6, 0x1e, 0xae, 0xae,
+	0xc7, 0xd1, 0xbf, 0x91, 0xad, 0xf0, 0x70, 0x41, 0x6c, 0xcb, 0x76, 0xee, 0xac, 0x6e, 0x0a, 0x7e,
+	0xc5, 0xa5, 0x3a, 0x1f, 0x67, 0x38, 0xc9, 0x89, 0x0d, 0xbc, 0x98, 0x8f, 0x87, 0x0a, 0x43, 0xaf,
+	0x41, 0xc7, 0x8c, 0x93, 0xf5, 0x31, 0x57, 0xf1, 0x0d, 0x66, 0x5c, 0xae, 0x43, 0xeb, 0x5c, 0x8e,
+	0xac, 0xd5, 0x8d, 0xc1, 0xaf, 0x64, 0x18, 0x7d, 0x0a, 0x4d, 0xa1, 0x02, 0xb6, 0x0d, 0xf2, 0xd6,
+	0xa5, 0x5b, 0x70, 0x8f, 0x0a, 0xc9, 0xe9, 0x38, 0xd7, 0xf7, 0x6a, 0xab, 0x7b, 0xfd, 0xf4, 0x74,
+	0xd3, 0x89, 0x0c, 0xdb, 0x4e, 0x0b, 0x1a, 0xa7, 0x34, 0x8b, 0xb7, 0x7e, 0x77, 0xa0, 0xa1, 0xd4,
+	0x05, 0xbd, 0x77, 0xfe, 0x9c, 0xcb, 0xb7, 0xba, 0x65, 0x44, 0xfb, 0xd0, 0x48, 0x08, 0x9e, 0xda,
+	0x06, 0x7b, 0xfb, 0x6a, 0xa5, 0xd9, 0x27, 0x78, 0xaa, 0xc2, 0x18, 0xd6, 0x22, 0xcd, 0x82, 0x0e,
+	0xa1, 0x29, 0xe6, 0x09, 0x35, 0xc9, 0xf1, 0xb7, 0xdf, 0xb9, 0x1a, 0xdd, 0xb1, 0xda, 0x6a, 0xf9,
+	0x0c, 0x4f, 0x79, 0xe1, 0x9f, 0x5b, 0xe0, 0x95, 0x7f, 0x2b, 0x01, 0xb0, 0x75, 0xd3, 0xf7, 0xf6,
+	0xa2, 0xc2, 0x44, 0xd7, 0xa0, 0xa9, 0x75, 0xcb, 0x96, 0xce, 0x18, 0xaa, 0x68, 0x46, 0x21, 0xac,
+	0x72, 0x59, 0x0b, 0x8d, 0x2b, 0x5a, 0x64, 0x22, 0x7e, 0x7f, 0xc9, 0x88, 0xc3, 0x42, 0x04, 0x6d,
+	0x2f, 0x2e, 0x34, 0x2d, 0x5b, 0x34, 0x9e, 0xa9, 0x94, 0xf9, 0x94, 0x7c, 0xb8, 0xec, 0x41, 0xd5,
+	0x71, 0xb8, 0x9f, 0x49, 0xfe, 0xb8, 0xec, 0x61, 0x0d, 0xa1, 0x3b, 0xf0, 0x8a, 0x69, 0xc9, 0x11,
+	0x96, 0xa3, 0x04, 0x0b, 0x39, 0x22, 0x67, 0x38, 0x09, 0x9a, 0x3a, 0x1f, 0xeb, 0xe6, 0xaf, 0x0f,
+	0xe4, 0x3e, 0x16, 0xf2, 0xfe, 0x19, 0x4e, 0xd4, 0x5c, 0x9c, 0x93, 0xf7, 0x60, 0x45, 0x7f, 0xfe,
+	0x3a, 0x55, 0x6d, 0x47, 0x87, 0x85, 0xb0, 0x9b, 0xed, 0x22, 0x68, 0xeb, 0x64, 0xbd, 0xfe, 0xcf,
+	0xdd, 0xf6, 0x90, 0x4c, 0x24, 0xe3, 0xc5, 0x70, 0x6a, 0x02, 0xa3, 0x24, 0xe2, 0x39, 0x8a, 0xe3,
+	0x3d, 0x4f, 0x71, 0x7e, 0x75, 0xa0, 0x5d, 0x7e, 0x5d, 0xae, 0x41, 0x33, 0x26, 0x99, 0x50, 0x25,
+	0x57, 0x35, 0x34, 0x06, 0x8a, 0xa1, 0x25, 0xe6, 0x98, 0x0b, 0x35, 0xac, 0x2a, 0xaf, 0xfb, 0x2f,
+	0x5a, 0xc0, 0xf0, 0x58, 0xd3, 0x99, 0xd4, 0x5a, 0x6e, 0xf4, 0x2a, 0x80, 0x59, 0x8d, 0x26, 0x78,
+	0x6e, 0x5f, 0x08, 0x9e, 0x41, 0x76, 0xf1, 0xbc, 0x7b, 0x17, 0xfc, 0xca, 0x2e, 0x25, 0x84, 0xa7,
+	0xe4, 0xb1, 0x6e, 0x4d, 0x37, 0x52, 0x4b, 0x15, 0xfb, 0x42, 0x51, 0xdc, 0xc8, 0x18, 0xf7, 0xea,
+	0xef, 0x3a, 0xdd, 0x6f, 0xe0, 0xe5, 0xbf, 0x55, 0xb4, 0x4a, 0xe0, 0x19, 0x82, 0xa3, 0x2a, 0x81,
+	0xbf, 0x7d, 0x6f, 0x79, 0x09, 0xad, 0x1c, 0xbe, 0xf5, 0x47, 0x1d, 0xda, 0xc5, 0x0c, 0xa3, 0xf4,
+	0xaf, 0x8d, 0xea, 0xe8, 0x84, 0x0e, 0x97, 0x93, 0x84, 0x65, 0xfb, 0xb4, 0x7e, 0x41, 0x9f, 0x6e,
+	0x82, 0x4f, 0xc5, 0x28, 0xa6, 0x02, 0x8f, 0x13, 0x12, 0xeb, 0x12, 0xb4, 0x23, 0xa0, 0x62, 0xcf,
+	0x22, 0xea, 0x9d, 0x42, 0x67, 0x19, 0xe3, 0x24, 0x1e, 0x9d, 0x7b, 0xe3, 0x78, 0xd1, 0x9a, 0xc5,
+	0x6d, 0x44, 0xe2, 0x7f, 0xcd, 0xf9, 0xce, 0xe1, 0x93, 0x67, 0x1b, 0xb5, 0x5f, 0x9e, 0x6d, 0x38,
+	0xdf, 0xfe, 0xb6, 0x51, 0x83, 0x37, 0x26, 0x2c, 0xbd, 0x04, 0xef, 0xce, 0xda, 0xb0, 0x20, 0x3e,
+	0x52, 0x0f, 0x61, 0xf1, 0x79, 0xbb, 0x78, 0xc4, 0x8f, 0x5b, 0xfa, 0x69, 0xfc, 0xe6, 0x9f, 0x03,
+	0x00, 0xf7, 0x00, 0xec, 0xf9, 0xf5, 0x0b, 0x00, 0x00,
 }
@@ -2,8 +2,11 @@ package internal
 
 import (
 	"math"
+	"sync"
 
+	common "github.com/bsm/reason/common/hoeffding"
 	"github.com/bsm/reason/core"
+	"github.com/bsm/reason/internal/hoeffding"
 	"github.com/bsm/reason/regression"
 	"github.com/bsm/reason/util"
 )
@@ -27,14 +30,72 @@ func (n *Node) Weight() float64 {
 	return n.Stats.Weight
 }
 
+// Promise returns the potential of a node to improve accuracy through
+// further splits, i.e. the sum of squared errors around the mean.
+func (n *Node) Promise() float64 {
+	if n.Stats.Weight <= 0 {
+		return 0.0
+	}
+	return n.Stats.SumSquares - n.Stats.Sum*n.Stats.Sum/n.Stats.Weight
+}
+
+// Estimated byte sizes of the node structures, excluding the vectors and
+// feature stats they contain.
+const (
+	nodeByteSize      = 3 * hoeffding.WordByteSize
+	leafNodeByteSize  = 6 * hoeffding.WordByteSize
+	splitNodeByteSize = 15 * hoeffding.WordByteSize
+)
+
+// ByteSize returns the estimated byte size of the node, including the
+// node and feature stats.
+func (n *Node) ByteSize() int {
+	size := nodeByteSize + hoeffding.StreamStatsByteSize
+	switch kind := n.Kind.(type) {
+	case *Node_Leaf:
+		size += kind.Leaf.byteSize()
+	case *Node_Split:
+		size += kind.Split.byteSize()
+	}
+	return size
+}
+
+// cloneForReading creates a copy of the node for read-only access.
+func (n *Node) cloneForReading() *Node {
+	stats := *n.Stats
+	clone := &Node{Stats: &stats}
+	switch kind := n.Kind.(type) {
+	case *Node_Leaf:
+		leaf := *kind.Leaf
+		leaf.FeatureStats, leaf.IgnoredFeatures = nil, nil
+		clone.Kind = &Node_Leaf{Leaf: &leaf}
+	case *Node_Split:
+		split := *kind.Split
+		split.FeatureStats = nil
+		split.Subset = append([]int64(nil), split.Subset...)
+		split.Children = *split.Children.clone()
+		split.ChildWeights = *split.ChildWeights.Clone()
+		clone.Kind = &Node_Split{Split: &split}
+	}
+	return clone
+}
+
 // --------------------------------------------------------------------
 
 func (n *SplitNode) childCat(feature *core.Feature, x core.Example) core.Category {
 	switch feature.Kind {
 	case core.Feature_CATEGORICAL:
-		return feature.Category(x)
+		cat := feature.Category(x)
+		if len(n.Subset) == 0 || !core.IsCat(cat) {
+			return cat
+		} else if hoeffding.SubsetContains(n.Subset, cat) {
+			return 0
+		}
+		return 1
 	case core.Feature_NUMERICAL:
-		if feature.Number(x) < n.Pivot {
+		if num := feature.Number(x); !core.IsNum(num) {
+			return core.NoCategory
+		} else if num < n.Pivot {
 			return 0
 		}
 		return 1
@@ -43,6 +104,120 @@ func (n *SplitNode) childCat(feature *core.Feature, x core.Example) core.Categor
 	}
 }
 
+// childIndex returns the index of the child example x is routed to. Examples
+// with missing values are routed to the default child. Returns -1 if x
+// cannot be routed.
+func (n *SplitNode) childIndex(feature *core.Feature, x core.Example) int {
+	if cat := n.childCat(feature, x); core.IsCat(cat) {
+		return int(cat)
+	}
+	return int(n.DefaultChild) - 1
+}
+
+// track accumulates the weight of examples routed to each child and
+// updates the default child for missing values. Returns the growth of the
+// estimated byte size.
+func (n *SplitNode) track(feature *core.Feature, x core.Example, weight float64) int {
+	cat := n.childCat(feature, x)
+	if !core.IsCat(cat) {
+		n.MissingWeight += weight
+		return 0
+	}
+
+	return n.addChildWeight(int(cat), weight)
+}
+
+// addChildWeight adds weight to a child and promotes it to the default
+// child if it has become the heaviest. Ties are resolved in favour of the
+// lower index. Returns the growth of the estimated byte size.
+func (n *SplitNode) addChildWeight(nodeIndex int, weight float64) int {
+	growth := hoeffding.AddToVector(&n.ChildWeights, nodeIndex, weight)
+
+	def := int(n.DefaultChild) - 1
+	if def < 0 {
+		n.DefaultChild = int64(nodeIndex) + 1
+		return growth
+	}
+
+	if w, dw := n.ChildWeights.Get(nodeIndex), n.ChildWeights.Get(def); w > dw || (w == dw && nodeIndex < def) {
+		n.DefaultChild = int64(nodeIndex) + 1
+	}
+	return growth
+}
+
+func (n *SplitNode) byteSize() int {
+	return splitNodeByteSize +
+		hoeffding.WordByteSize*(len(n.Subset)+len(n.Children.Dense)) +
+		hoeffding.MapEntryByteSize*len(n.Children.Sparse) +
+		hoeffding.VectorByteSize(&n.ChildWeights) +
+		featureStatsMapByteSize(n.FeatureStats)
+}
+
+func (n *SplitNode) formatCondition(feature *core.Feature, pos int) string {
+	if len(n.Subset) != 0 {
+		return hoeffding.FormatSubsetCondition(feature, pos, n.Subset)
+	}
+	return hoeffding.FormatNodeCondition(feature, pos, n.Pivot)
+}
+
+// Observe observes an example and updates the node stats as well as
+// the feature stats of the split node. Returns the growth of the estimated
+// byte size.
+func (n *SplitNode) Observe(m *core.Model, target *core.Feature, x core.Example, weight float64, self *Node) int {
+	targetVal := target.Number(x)
+	if !core.IsNum(targetVal) {
+		return 0
+	}
+
+	self.Stats.Add(targetVal, weight)
+	featureStats, growth := observeFeatures(n.FeatureStats, m, target, x, targetVal, weight, nil)
+	n.FeatureStats = featureStats
+	return growth
+}
+
+// EvaluateSplit evaluates an alternative split for a given feature,
+// based on the feature stats observed by the split node.
+// Returns nil if a split is not possible.
+func (n *SplitNode) EvaluateSplit(feature string, crit regression.SplitCriterion, binary bool, observer common.NumericObserver, self *Node) *SplitCandidate {
+	return evaluateSplit(n.FeatureStats, feature, crit, binary, observer, self)
+}
+
+// EvaluateSplits evaluates alternative splits for all observed features,
+// using the given number of concurrent workers.
+func (n *SplitNode) EvaluateSplits(crit regression.SplitCriterion, binary bool, observer common.NumericObserver, workers int, self *Node) SplitCandidates {
+	return evaluateSplits(n.FeatureStats, crit, binary, observer, workers, self)
+}
+
+// ObservedWeight returns the weight observed by the feature stats of the
+// split feature, i.e. the weight the split merit is based on.
+func (n *SplitNode) ObservedWeight() float64 {
+	if stats, ok := n.FeatureStats[n.Feature]; ok {
+		return stats.weight()
+	}
+	return 0.0
+}
+
+// Merit calculates the current merit of the split, based on the feature
+// stats observed by the split node.
+func (n *SplitNode) Merit(crit regression.SplitCriterion, self *Node) float64 {
+	stats, ok := n.FeatureStats[n.Feature]
+	if !ok {
+		return 0.0
+	}
+
+	scale := stats.presentFraction(self.Weight())
+	switch kind := stats.Kind.(type) {
+	case *FeatureStats_Numerical_:
+		return scale * crit.Merit(self.Stats, kind.Numerical.PostSplit(n.Pivot))
+	case *FeatureStats_Categorical_:
+		if len(n.Subset) != 0 {
+			return scale * crit.Merit(self.Stats, kind.Categorical.PostSplitSubset(n.Subset))
+		}
+		return scale * crit.Merit(self.Stats, kind.Categorical.PostSplit())
+	}
+	return 0.0
+}
+
 // --------------------------------------------------------------------
 
 // Enable enables the node.
@@ -59,31 +234,97 @@ func (n *LeafNode) Disable() {
 	n.FeatureStats = nil
 }
 
+// IgnoreFeature removes the stats of a feature and stops the leaf from
+// observing it. Returns the growth of the estimated byte size.
+func (n *LeafNode) IgnoreFeature(feature string) int {
+	growth := 0
+	if stats, ok := n.FeatureStats[feature]; ok {
+		growth -= featureStatsEntryByteSize + stats.byteSize()
+		delete(n.FeatureStats, feature)
+	}
+	if !n.isIgnored(feature) {
+		n.IgnoredFeatures = append(n.IgnoredFeatures, feature)
+		growth += 2 * hoeffding.WordByteSize
+	}
+	return growth
+}
+
+func (n *LeafNode) isIgnored(feature string) bool {
+	for _, name := range n.IgnoredFeatures {
+		if name == feature {
+			return true
+		}
+	}
+	return false
+}
+
 // EvaluateSplit evaluates a split for a fiven feature.
 // Returns nil if a split is not possible.
-func (n *LeafNode) EvaluateSplit(feature string, crit regression.SplitCriterion, self *Node) *SplitCandidate {
+func (n *LeafNode) EvaluateSplit(feature string, crit regression.SplitCriterion, binary bool, observer common.NumericObserver, self *Node) *SplitCandidate {
 	if n.IsDisabled {
 		return nil
 	}
+	return evaluateSplit(n.FeatureStats, feature, crit, binary, observer, self)
+}
 
-	if n.FeatureStats == nil {
+// EvaluateSplits evaluates splits for all observed features, using the
+// given number of concurrent workers.
+func (n *LeafNode) EvaluateSplits(crit regression.SplitCriterion, binary bool, observer common.NumericObserver, workers int, self *Node) SplitCandidates {
+	if n.IsDisabled {
 		return nil
 	}
+	return evaluateSplits(n.FeatureStats, crit, binary, observer, workers, self)
+}
+
+// Observe observes an example and updates internal stats. Returns the
+// growth of the estimated byte size.
+func (n *LeafNode) Observe(m *core.Model, target *core.Feature, x core.Example, weight float64, self *Node) int {
+	// Get the target value, skip this example on "no value"
+	targetVal := target.Number(x)
+	if !core.IsNum(targetVal) {
+		return 0
+	}
+
+	// Get example weight and update node stats
+	self.Stats.Add(targetVal, weight)
+
+	// Skip the remaining steps if this node is disabled
+	if n.IsDisabled {
+		return 0
+	}
+
+	featureStats, growth := observeFeatures(n.FeatureStats, m, target, x, targetVal, weight, n.isIgnored)
+	n.FeatureStats = featureStats
+	return growth
+}
+
+func (n *LeafNode) byteSize() int {
+	return leafNodeByteSize +
+		2*hoeffding.WordByteSize*len(n.IgnoredFeatures) +
+		featureStatsMapByteSize(n.FeatureStats)
+}
+
+// --------------------------------------------------------------------
 
-	stats, ok := n.FeatureStats[feature]
+func evaluateSplit(featureStats map[string]*FeatureStats, feature string, crit regression.SplitCriterion, binary bool, observer common.NumericObserver, self *Node) *SplitCandidate {
+	stats, ok := featureStats[feature]
 	if !ok {
 		return nil
 	}
 
+	// Scale merits by the fraction of observations with a feature value
+	scale := stats.presentFraction(self.Weight())
+
 	switch kind := stats.Kind.(type) {
 	case *FeatureStats_Numerical_:
 		var c *SplitCandidate
 		s := kind.Numerical
 		r := crit.Range(self.Stats)
 
-		for _, pivot := range s.PivotPoints() {
-			post := s.PostSplit(pivot)
-			merit := crit.Merit(self.Stats, post)
+		pivots, posts := s.PostSplits(observer)
+		for i, pivot := range pivots {
+			post := posts[i]
+			merit := scale * crit.Merit(self.Stats, post)
 			if c == nil || merit > c.Merit {
 				c = &SplitCandidate{
 					Feature:   feature,
@@ -97,11 +338,30 @@ func (n *LeafNode) EvaluateSplit(feature string, crit regression.SplitCriterion,
 		}
 		return c
 	case *FeatureStats_Categorical_:
-		if s := kind.Categorical; s.Len() > 1 {
+		if s := kind.Categorical; s.Len() > 1 && binary {
+			var c *SplitCandidate
+			r := crit.Range(self.Stats)
+
+			for _, subset := range s.Subsets() {
+				post := s.PostSplitSubset(subset)
+				merit := scale * crit.Merit(self.Stats, post)
+				if c == nil || merit > c.Merit {
+					c = &SplitCandidate{
+						Feature:   feature,
+						Merit:     merit,
+						Range:     r,
+						Subset:    subset,
+						PreSplit:  self.Stats,
+						PostSplit: post,
+					}
+				}
+			}
+			return c
+		} else if s.Len() > 1 {
 			post := s.PostSplit()
 			return &SplitCandidate{
 				Feature:   feature,
-				Merit:     crit.Merit(self.Stats, post),
+				Merit:     scale * crit.Merit(self.Stats, post),
 				Range:     crit.Range(self.Stats),
 				PreSplit:  self.Stats,
 				PostSplit: post,
@@ -111,49 +371,85 @@ func (n *LeafNode) EvaluateSplit(feature string, crit regression.SplitCriterion,
 	return nil
 }
 
-// Observe observes an example and updates internal stats.
-func (n *LeafNode) Observe(m *core.Model, target *core.Feature, x core.Example, weight float64, self *Node) {
-	// Get the target value, skip this example on "no value"
-	targetVal := target.Number(x)
-	if !core.IsNum(targetVal) {
-		return
+// evaluateSplits evaluates split candidates for all features. Feature stats
+// are only read, so candidates can be evaluated concurrently. Candidates are
+// returned in the order of feature iteration, regardless of the number
+// of workers.
+func evaluateSplits(featureStats map[string]*FeatureStats, crit regression.SplitCriterion, binary bool, observer common.NumericObserver, workers int, self *Node) SplitCandidates {
+	if len(featureStats) == 0 {
+		return nil
 	}
 
-	// Get example weight and update node stats
-	self.Stats.Add(targetVal, weight)
+	features := make([]string, 0, len(featureStats))
+	for name := range featureStats {
+		features = append(features, name)
+	}
 
-	// Skip the remaining steps if this node is disabled
-	if n.IsDisabled {
-		return
+	results := make([]*SplitCandidate, len(features))
+	if workers > len(features) {
+		workers = len(features)
 	}
+	if workers > 1 {
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func(w int) {
+				defer wg.Done()
 
+				for i := w; i < len(features); i += workers {
+					results[i] = evaluateSplit(featureStats, features[i], crit, binary, observer, self)
+				}
+			}(w)
+		}
+		wg.Wait()
+	} else {
+		for i, name := range features {
+			results[i] = evaluateSplit(featureStats, name, crit, binary, observer, self)
+		}
+	}
+
+	candidates := make(SplitCandidates, 0, len(results))
+	for _, c := range results {
+		if c != nil {
+			candidates = append(candidates, *c)
+		}
+	}
+	return candidates
+}
+
+func observeFeatures(featureStats map[string]*FeatureStats, m *core.Model, target *core.Feature, x core.Example, targetVal, weight float64, isIgnored func(string) bool) (map[string]*FeatureStats, int) {
 	// Ensure we have stats
-	if n.FeatureStats == nil {
-		n.FeatureStats = make(map[string]*FeatureStats)
+	if featureStats == nil {
+		featureStats = make(map[string]*FeatureStats)
 	}
 
+	growth := 0
+
 	// Update each predictor feature's stats with a target-value, predictor-value
 	// and weight tuple
 	for name, feat := range m.Features {
 		if name == target.Name {
 			continue // skip target, we are only interested in predictors
 		}
+		if isIgnored != nil && isIgnored(name) {
+			continue // skip ignored features
+		}
 
-		stats := n.FeatureStats[feat.Name]
+		stats := featureStats[feat.Name]
 		if stats == nil {
 			stats = new(FeatureStats)
-			n.FeatureStats[feat.Name] = stats
+			featureStats[feat.Name] = stats
+			growth += featureStatsEntryByteSize + stats.byteSize()
 		}
 
-		switch feat.Kind {
-		case core.Feature_CATEGORICAL:
-			if cat := feat.Category(x); core.IsCat(cat) {
-				stats.FetchCategorical().Add(cat, targetVal, weight)
-			}
-		case core.Feature_NUMERICAL:
-			if num := feat.Number(x); core.IsNum(num) {
-				stats.FetchNumerical().Add(num, targetVal, weight)
-			}
+		if stats.Kind != nil {
+			growth += stats.observe(feat, x, targetVal, weight)
+		} else {
+			// estimate uninitialised stats from scratch
+			before := stats.byteSize()
+			stats.observe(feat, x, targetVal, weight)
+			growth += stats.byteSize() - before
 		}
 	}
+	return featureStats, growth
 }
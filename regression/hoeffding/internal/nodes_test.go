@@ -1,6 +1,8 @@
 package internal_test
 
 import (
+	common "github.com/bsm/reason/common/hoeffding"
+	"github.com/bsm/reason/core"
 	"github.com/bsm/reason/regression"
 	"github.com/bsm/reason/regression/hoeffding/internal"
 	"github.com/bsm/reason/testdata"
@@ -38,9 +40,9 @@ var _ = Describe("LeafNode", func() {
 
 	It("should evaluate splits", func() {
 		crit := regression.DefaultSplitCriterion()
-		Expect(subject.EvaluateSplit("unknown", crit, wrapper)).To(BeNil())
+		Expect(subject.EvaluateSplit("unknown", crit, false, common.NumericObserverGaussian, wrapper)).To(BeNil())
 
-		cat := subject.EvaluateSplit("outlook", crit, wrapper)
+		cat := subject.EvaluateSplit("outlook", crit, false, common.NumericObserverGaussian, wrapper)
 		Expect(cat.Feature).To(Equal("outlook"))
 		Expect(cat.Merit).To(BeNumerically("~", 9.14, 0.01))
 		Expect(cat.Range).To(Equal(1.0))
@@ -48,7 +50,13 @@ var _ = Describe("LeafNode", func() {
 		Expect(cat.PreSplit.Weight).To(Equal(14.0))
 		Expect(cat.PostSplit.Len()).To(Equal(3))
 
-		num := subject.EvaluateSplit("humidity", crit, wrapper)
+		bin := subject.EvaluateSplit("outlook", crit, true, common.NumericObserverGaussian, wrapper)
+		Expect(bin.Feature).To(Equal("outlook"))
+		Expect(bin.Merit).To(BeNumerically("~", 14.83, 0.01))
+		Expect(bin.Subset).To(Equal([]int64{0, 2}))
+		Expect(bin.PostSplit.Len()).To(Equal(2))
+
+		num := subject.EvaluateSplit("humidity", crit, false, common.NumericObserverGaussian, wrapper)
 		Expect(num.Feature).To(Equal("humidity"))
 		Expect(num.Merit).To(Equal(0.0))
 		Expect(num.Range).To(Equal(1.0))
@@ -57,6 +65,53 @@ var _ = Describe("LeafNode", func() {
 		Expect(num.PostSplit.Len()).To(Equal(2))
 	})
 
+	It("should evaluate splits concurrently", func() {
+		crit := regression.DefaultSplitCriterion()
+		sequential := subject.EvaluateSplits(crit, true, common.NumericObserverGaussian, 1, wrapper)
+		Expect(sequential).To(HaveLen(4))
+
+		for _, workers := range []int{2, 3, 8} {
+			Expect(subject.EvaluateSplits(crit, true, common.NumericObserverGaussian, workers, wrapper)).To(ConsistOf(sequential))
+		}
+
+		subject.IsDisabled = true
+		Expect(subject.EvaluateSplits(crit, true, common.NumericObserverGaussian, 2, wrapper)).To(BeEmpty())
+	})
+
+	It("should account for missing values", func() {
+		crit := regression.DefaultSplitCriterion()
+		subject.FeatureStats["outlook"].MissingWeight = 7
+
+		cat := subject.EvaluateSplit("outlook", crit, false, common.NumericObserverGaussian, wrapper)
+		Expect(cat.Merit).To(BeNumerically("~", 4.57, 0.01))
+
+		subject.Observe(model, model.Feature("hours"), core.MapExample{"hours": 40.0}, 1.0, wrapper)
+		Expect(subject.FeatureStats["outlook"].MissingWeight).To(Equal(8.0))
+		Expect(subject.FeatureStats["windy"].MissingWeight).To(Equal(1.0))
+	})
+
+	It("should estimate promise and byte size", func() {
+		Expect(wrapper.Promise()).To(BeNumerically("~", 1216.36, 0.01))
+
+		size := wrapper.ByteSize()
+		Expect(size).To(BeNumerically(">", 100))
+		subject.Disable()
+		Expect(wrapper.ByteSize()).To(BeNumerically("<", size/4))
+	})
+
+	It("should ignore features", func() {
+		subject.IgnoreFeature("outlook")
+		subject.IgnoreFeature("outlook")
+		Expect(subject.IgnoredFeatures).To(Equal([]string{"outlook"}))
+		Expect(subject.FeatureStats).To(HaveLen(3))
+		Expect(subject.FeatureStats).NotTo(HaveKey("outlook"))
+
+		subject.Observe(model, model.Feature("hours"), core.MapExample{"outlook": "sunny", "temp": "cool", "hours": 40.0}, 1.0, wrapper)
+		Expect(wrapper.Weight()).To(Equal(15.0))
+		Expect(subject.FeatureStats).To(HaveLen(3))
+		Expect(subject.FeatureStats).NotTo(HaveKey("outlook"))
+	})
+
 	It("should allow to disable/enable", func() {
 		Expect(subject.FeatureStats).To(HaveLen(4))
 		Expect(subject.IsDisabled).To(BeFalse())
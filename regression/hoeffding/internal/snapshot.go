@@ -0,0 +1,204 @@
+package internal
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/bsm/reason/core"
+	"github.com/bsm/reason/internal/hoeffding"
+	"github.com/bsm/reason/internal/protoio"
+	"github.com/bsm/reason/util"
+	"github.com/gogo/protobuf/proto"
+)
+
+// Snapshot creates a flattened snapshot of the tree. Alternate
+// subtrees are not included.
+func (t *Tree) Snapshot() *Snapshot {
+	s := &Snapshot{
+		Model:  proto.Clone(t.Model).(*core.Model),
+		Target: t.Target,
+	}
+	s.add(t, t.Root, make(map[string]int64))
+	s.ChildOffsets = append(s.ChildOffsets, int64(len(s.Children)))
+	s.SubsetOffsets = append(s.SubsetOffsets, int64(len(s.Subsets)))
+	return s
+}
+
+// add appends the node and its subtree in depth-first order
+// and returns the index of the node.
+func (s *Snapshot) add(t *Tree, nodeRef int64, features map[string]int64) int64 {
+	node := t.Get(nodeRef)
+	pos := int64(len(s.Features))
+
+	s.Features = append(s.Features, -1)
+	s.Pivots = append(s.Pivots, 0)
+	s.DefaultChildren = append(s.DefaultChildren, -1)
+	s.ChildOffsets = append(s.ChildOffsets, int64(len(s.Children)))
+	s.SubsetOffsets = append(s.SubsetOffsets, int64(len(s.Subsets)))
+	s.Predictions = append(s.Predictions, *node.Stats)
+
+	split := node.GetSplit()
+	if split == nil {
+		return pos
+	}
+
+	feature, ok := features[split.Feature]
+	if !ok {
+		feature = int64(len(s.FeatureNames))
+		features[split.Feature] = feature
+		s.FeatureNames = append(s.FeatureNames, split.Feature)
+	}
+	s.Features[pos] = feature
+	s.Pivots[pos] = split.Pivot
+	s.DefaultChildren[pos] = split.DefaultChild - 1
+	s.Subsets = append(s.Subsets, split.Subset...)
+
+	// reserve child slots, subtrees are appended after their parent
+	numChildren := 0
+	split.Children.ForEach(func(i int, _ int64) bool {
+		if i >= numChildren {
+			numChildren = i + 1
+		}
+		return true
+	})
+
+	offset := len(s.Children)
+	for i := 0; i < numChildren; i++ {
+		s.Children = append(s.Children, -1)
+	}
+	for i := 0; i < numChildren; i++ {
+		if childRef := split.Children.GetRef(i); t.Get(childRef) != nil {
+			s.Children[offset+i] = s.add(t, childRef, features)
+		}
+	}
+	return pos
+}
+
+// Validate checks that the flat arrays are consistent with each other, so
+// the snapshot can be traversed safely.
+func (s *Snapshot) Validate() error {
+	n := len(s.Features)
+	if n == 0 {
+		return fmt.Errorf("hoeffding: invalid snapshot")
+	}
+	if len(s.Pivots) != n || len(s.DefaultChildren) != n || len(s.Predictions) != n ||
+		len(s.ChildOffsets) != n+1 || len(s.SubsetOffsets) != n+1 {
+		return fmt.Errorf("hoeffding: invalid snapshot, mismatching number of nodes")
+	}
+	if !validOffsets(s.ChildOffsets, len(s.Children)) {
+		return fmt.Errorf("hoeffding: invalid snapshot, bad child offsets")
+	}
+	if !validOffsets(s.SubsetOffsets, len(s.Subsets)) {
+		return fmt.Errorf("hoeffding: invalid snapshot, bad subset offsets")
+	}
+
+	for pos, fi := range s.Features {
+		if fi < -1 || fi >= int64(len(s.FeatureNames)) {
+			return fmt.Errorf("hoeffding: invalid snapshot, bad feature index %d", fi)
+		}
+		if s.DefaultChildren[pos] < -1 {
+			return fmt.Errorf("hoeffding: invalid snapshot, bad default child %d", s.DefaultChildren[pos])
+		}
+
+		// children are stored after their parent, which also rules out cycles
+		for _, child := range s.Children[s.ChildOffsets[pos]:s.ChildOffsets[pos+1]] {
+			if child != -1 && (child <= int64(pos) || child >= int64(n)) {
+				return fmt.Errorf("hoeffding: invalid snapshot, bad child index %d", child)
+			}
+		}
+	}
+	return nil
+}
+
+// validOffsets returns true if offsets are ascending and within size.
+func validOffsets(offsets []int64, size int) bool {
+	prev := int64(0)
+	for _, offset := range offsets {
+		if offset < prev || offset > int64(size) {
+			return false
+		}
+		prev = offset
+	}
+	return true
+}
+
+// Traverse traverses the snapshot for example x and returns the index of
+// the final node. Features must be resolved in the order of FeatureNames.
+func (s *Snapshot) Traverse(features []*core.Feature, x core.Example) int {
+	pos := 0
+	for {
+		fi := s.Features[pos]
+		if fi < 0 {
+			return pos
+		}
+
+		nodeIndex := s.childIndex(pos, features[fi], x)
+		if nodeIndex < 0 {
+			return pos
+		}
+
+		offset, end := s.ChildOffsets[pos], s.ChildOffsets[pos+1]
+		if int64(nodeIndex) >= end-offset {
+			return pos
+		}
+
+		child := s.Children[offset+int64(nodeIndex)]
+		if child < 0 {
+			return pos
+		}
+		pos = int(child)
+	}
+}
+
+func (s *Snapshot) childIndex(pos int, feature *core.Feature, x core.Example) int {
+	switch feature.Kind {
+	case core.Feature_CATEGORICAL:
+		cat := feature.Category(x)
+		if !core.IsCat(cat) {
+			break
+		}
+
+		subset := s.Subsets[s.SubsetOffsets[pos]:s.SubsetOffsets[pos+1]]
+		if len(subset) == 0 {
+			return int(cat)
+		} else if hoeffding.SubsetContains(subset, cat) {
+			return 0
+		}
+		return 1
+	case core.Feature_NUMERICAL:
+		num := feature.Number(x)
+		if !core.IsNum(num) {
+			break
+		} else if num < s.Pivots[pos] {
+			return 0
+		}
+		return 1
+	}
+	return int(s.DefaultChildren[pos])
+}
+
+// Prediction returns the prediction stats of a node.
+func (s *Snapshot) Prediction(pos int) *util.StreamStats {
+	return &s.Predictions[pos]
+}
+
+// WriteTo writes a snapshot to a Writer.
+func (s *Snapshot) WriteTo(w io.Writer) (int64, error) {
+	data, err := protoio.Marshal(s)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// ReadFrom reads a snapshot from a Reader.
+func (s *Snapshot) ReadFrom(r io.Reader) (int64, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return int64(len(data)), err
+	}
+	return int64(len(data)), proto.Unmarshal(data, s)
+}
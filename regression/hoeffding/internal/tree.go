@@ -4,12 +4,14 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"sort"
 
 	common "github.com/bsm/reason/common/hoeffding"
 	"github.com/bsm/reason/core"
-	internal "github.com/bsm/reason/internal/hoeffding"
+	"github.com/bsm/reason/internal/hoeffding"
 	"github.com/bsm/reason/internal/iocount"
 	"github.com/bsm/reason/internal/protoio"
+	"github.com/bsm/reason/regression"
 	"github.com/bsm/reason/util"
 	"github.com/gogo/protobuf/proto"
 )
@@ -24,6 +26,24 @@ func NewTree(model *core.Model, target string) *Tree {
 	return t
 }
 
+// CloneForReading creates a copy of the tree for read-only access. Feature
+// stats are not copied.
+func (t *Tree) CloneForReading() *Tree {
+	nodes := make([]*Node, len(t.Nodes))
+	for i, node := range t.Nodes {
+		if node != nil {
+			nodes[i] = node.cloneForReading()
+		}
+	}
+
+	return &Tree{
+		Model:  proto.Clone(t.Model).(*core.Model),
+		Target: t.Target,
+		Root:   t.Root,
+		Nodes:  nodes,
+	}
+}
+
 // Get retrieves a node by its reference
 func (t *Tree) Get(nodeRef int64) *Node {
 	pos := int(nodeRef - 1)
@@ -46,6 +66,18 @@ func (t *Tree) Len() int {
 	return len(t.Nodes)
 }
 
+// ByteSize returns the estimated byte size of all registered nodes. It
+// visits all feature stats and is intended to initialise running totals.
+func (t *Tree) ByteSize() int {
+	size := 0
+	for _, node := range t.Nodes {
+		if node != nil {
+			size += node.ByteSize()
+		}
+	}
+	return size
+}
+
 // Add adds a new leaf node
 func (t *Tree) Add(stats *util.StreamStats) int64 {
 	if stats == nil {
@@ -59,31 +91,53 @@ func (t *Tree) Add(stats *util.StreamStats) int64 {
 	return int64(len(t.Nodes))
 }
 
-// Split splits an existing leaf node
-func (t *Tree) Split(leafRef int64, feature string, pre *util.StreamStats, post *util.StreamStatsDistribution, pivot float64) {
-	if orig := t.Get(leafRef); orig == nil || orig.GetLeaf() == nil {
-		return
+// Split splits an existing leaf node. Returns the growth of the estimated
+// byte size.
+func (t *Tree) Split(leafRef int64, feature string, pre *util.StreamStats, post *util.StreamStatsDistribution, pivot float64) int {
+	orig := t.Get(leafRef)
+	if orig == nil || orig.GetLeaf() == nil {
+		return 0
 	}
 
 	split := &SplitNode{
-		Feature: feature,
-		Pivot:   pivot,
+		Feature:          feature,
+		Pivot:            pivot,
+		WeightAtLastEval: pre.Weight,
 	}
 
-	post.ForEach(func(i int, stats *util.StreamStats) bool {
-		split.Children.SetRef(i, t.Add(stats))
+	// add children in index order, to keep node references reproducible
+	indices := make([]int, 0, post.Len())
+	post.ForEach(func(i int, _ *util.StreamStats) bool {
+		indices = append(indices, i)
 		return true
 	})
+	sort.Ints(indices)
+
+	// copy child stats, post-split distributions may be retained with the
+	// feature stats of the split node
+	growth := -orig.ByteSize()
+	for _, i := range indices {
+		stats := *post.Get(i)
+		childRef := t.Add(&stats)
+		split.Children.SetRef(i, childRef)
+		split.addChildWeight(i, stats.Weight)
+		growth += t.Get(childRef).ByteSize()
+	}
 
-	kind := &Node_Split{Split: split}
-	t.Set(leafRef, &Node{Kind: kind, Stats: pre})
+	node := &Node{Kind: &Node_Split{Split: split}, Stats: pre}
+	t.Set(leafRef, node)
+	return growth + node.ByteSize()
 }
 
-// Traverse traverses the tree starting at the given node ID
-func (t *Tree) Traverse(x core.Example, nodeRef int64, parent *Node, parentIndex int, forEach func(*Node)) (*Node, int64, *Node, int) {
+// Traverse traverses the tree starting at the given node ID. It returns the
+// node reached, its ID, its parent and its index within the parent, as well
+// as its depth relative to the starting node, which is at depth 1. When the
+// matching child of a split node does not exist, the returned node is nil and
+// the depth is that of the missing child.
+func (t *Tree) Traverse(x core.Example, nodeRef int64, parent *Node, parentIndex int, forEach func(*Node)) (*Node, int64, *Node, int, int) {
 	node := t.Get(nodeRef)
 	if node == nil {
-		return node, nodeRef, parent, parentIndex
+		return node, nodeRef, parent, parentIndex, 1
 	}
 	if forEach != nil {
 		forEach(node)
@@ -92,14 +146,15 @@ func (t *Tree) Traverse(x core.Example, nodeRef int64, parent *Node, parentIndex
 	if split := node.GetSplit(); split != nil {
 		feature := t.Model.Feature(split.Feature)
 
-		if nodeIndex := int(split.childCat(feature, x)); nodeIndex > -1 {
+		if nodeIndex := split.childIndex(feature, x); nodeIndex > -1 {
 			if childRef := split.Children.GetRef(nodeIndex); childRef > 0 {
-				return t.Traverse(x, childRef, node, nodeIndex, forEach)
+				node, nodeRef, parent, parentIndex, depth := t.Traverse(x, childRef, node, nodeIndex, forEach)
+				return node, nodeRef, parent, parentIndex, depth + 1
 			}
-			return nil, nodeRef, node, nodeIndex
+			return nil, nodeRef, node, nodeIndex, 2
 		}
 	}
-	return node, nodeRef, parent, parentIndex
+	return node, nodeRef, parent, parentIndex, 1
 }
 
 // Prune prunes the leaves of a node recursively
@@ -151,9 +206,9 @@ func (t *Tree) WriteText(w io.Writer, nodeRef int64, indent, name string) (nw in
 		}
 
 		subIndent := indent + "\t"
-		split.Children.ForEach(func(i int, childRef int64) bool {
+		split.Children.ForEachOrdered(func(i int, childRef int64) bool {
 			var nn int64
-			nn, err = t.WriteText(w, childRef, subIndent, internal.FormatNodeCondition(feat, i, split.Pivot))
+			nn, err = t.WriteText(w, childRef, subIndent, split.formatCondition(feat, i))
 			nw += nn
 			return err == nil
 		})
@@ -187,7 +242,7 @@ func (t *Tree) WriteDOT(w io.Writer, nodeRef int64, name, label string) (nw int6
 			return
 		}
 
-		split.Children.ForEach(func(i int, childRef int64) bool {
+		split.Children.ForEachOrdered(func(i int, childRef int64) bool {
 			subName := fmt.Sprintf("%s_%d", name, i)
 
 			n, err = fmt.Fprintf(w, "  %s -> %s;\n", name, subName)
@@ -197,7 +252,7 @@ func (t *Tree) WriteDOT(w io.Writer, nodeRef int64, name, label string) (nw int6
 			}
 
 			var nn int64
-			nn, err = t.WriteDOT(w, childRef, subName, internal.FormatNodeCondition(feat, i, split.Pivot)+`\n`)
+			nn, err = t.WriteDOT(w, childRef, subName, split.formatCondition(feat, i)+`\n`)
 			nw += nn
 			return err == nil
 		})
@@ -208,14 +263,214 @@ func (t *Tree) WriteDOT(w io.Writer, nodeRef int64, name, label string) (nw int6
 	return
 }
 
-// FilterLeaves finds all leaf-nodes and appends them to dst
+// FilterLeaves finds all reachable leaf-nodes and appends them to dst
 func (t *Tree) FilterLeaves(dst []*Node) []*Node {
-	for _, n := range t.Nodes {
-		switch n.GetKind().(type) {
-		case *Node_Leaf:
-			dst = append(dst, n)
+	return t.filterLeaves(t.Root, dst)
+}
+
+func (t *Tree) filterLeaves(nodeRef int64, dst []*Node) []*Node {
+	node := t.Get(nodeRef)
+	if node == nil {
+		return dst
+	}
+
+	switch kind := node.GetKind().(type) {
+	case *Node_Leaf:
+		dst = append(dst, node)
+	case *Node_Split:
+		kind.Split.Children.ForEachOrdered(func(_ int, childRef int64) bool {
+			dst = t.filterLeaves(childRef, dst)
+			return true
+		})
+	}
+	return dst
+}
+
+// Path traverses the tree for example x, starting at the given node ID, and
+// appends the references of all visited nodes to dst.
+func (t *Tree) Path(x core.Example, nodeRef int64, dst []int64) []int64 {
+	for node := t.Get(nodeRef); node != nil; node = t.Get(nodeRef) {
+		dst = append(dst, nodeRef)
+
+		split := node.GetSplit()
+		if split == nil {
+			break
+		}
+
+		nodeIndex := split.childIndex(t.Model.Feature(split.Feature), x)
+		if nodeIndex < 0 {
+			break
+		}
+		nodeRef = split.Children.GetRef(nodeIndex)
+	}
+	return dst
+}
+
+// Leaf traverses the tree for example x, starting at the given node ID,
+// and returns the ID and the depth of the last node reached.
+func (t *Tree) Leaf(x core.Example, nodeRef int64) (int64, int) {
+	depth := 0
+	for node := t.Get(nodeRef); node != nil; node = t.Get(nodeRef) {
+		depth++
+
+		split := node.GetSplit()
+		if split == nil {
+			break
+		}
+
+		nodeIndex := split.childIndex(t.Model.Feature(split.Feature), x)
+		if nodeIndex < 0 {
+			break
+		}
+
+		childRef := split.Children.GetRef(nodeIndex)
+		if t.Get(childRef) == nil {
+			break
+		}
+		nodeRef = childRef
+	}
+	return nodeRef, depth
+}
+
+// Explain traverses the tree for example x, starting at the given node ID,
+// and calls fn for every visited node with a description of the decision.
+// It returns the reason why the traversal has stopped.
+func (t *Tree) Explain(x core.Example, nodeRef int64, fn func(*Node, *common.DecisionStep)) common.StopReason {
+	for node := t.Get(nodeRef); node != nil; node = t.Get(nodeRef) {
+		step := &common.DecisionStep{Weight: node.Weight()}
+
+		split := node.GetSplit()
+		if split == nil {
+			fn(node, step)
+			return common.StopLeaf
+		}
+
+		feature := t.Model.Feature(split.Feature)
+		step.Feature = split.Feature
+		step.Value = x.GetExampleValue(split.Feature)
+		step.Missing = !core.IsCat(split.childCat(feature, x))
+
+		nodeIndex := split.childIndex(feature, x)
+		if nodeIndex < 0 {
+			fn(node, step)
+			return common.StopMissingValue
+		}
+
+		step.Condition = split.formatCondition(feature, nodeIndex)
+		fn(node, step)
+
+		nodeRef = split.Children.GetRef(nodeIndex)
+	}
+	return common.StopMissingChild
+}
+
+// Track passes example x along its path, starting at the given node ID, and
+// accumulates the weights of examples routed by each split node, including
+// the weight of examples with missing values. Returns the growth of the
+// estimated byte size.
+func (t *Tree) Track(x core.Example, nodeRef int64, weight float64) int {
+	growth := 0
+	for node := t.Get(nodeRef); node != nil; node = t.Get(nodeRef) {
+		split := node.GetSplit()
+		if split == nil {
+			break
+		}
+
+		feature := t.Model.Feature(split.Feature)
+		growth += split.track(feature, x, weight)
+
+		nodeIndex := split.childIndex(feature, x)
+		if nodeIndex < 0 {
+			break
+		}
+		nodeRef = split.Children.GetRef(nodeIndex)
+	}
+	return growth
+}
+
+// Discard disables all leaves of the subtree at the given node ID. Returns
+// the reduction of the estimated byte size.
+func (t *Tree) Discard(nodeRef int64) int {
+	node := t.Get(nodeRef)
+	if node == nil {
+		return 0
+	}
+
+	reduction := node.ByteSize()
+	switch kind := node.GetKind().(type) {
+	case *Node_Leaf:
+		kind.Leaf.Disable()
+	case *Node_Split:
+		kind.Split.FeatureStats = nil
+		kind.Split.Children.ForEach(func(_ int, childRef int64) bool {
+			reduction += t.Discard(childRef)
+			return true
+		})
+	}
+	return reduction - node.ByteSize()
+}
+
+// Revert turns a split node back into a leaf, re-using the feature stats
+// observed by the split node and discarding its subtrees. Returns the growth
+// of the estimated byte size.
+func (t *Tree) Revert(nodeRef int64) int {
+	node := t.Get(nodeRef)
+	if node == nil {
+		return 0
+	}
+
+	split := node.GetSplit()
+	if split == nil {
+		return 0
+	}
+
+	before := node.ByteSize()
+	featureStats := split.FeatureStats
+	split.FeatureStats = nil
+	reduction := t.Discard(nodeRef)
+
+	leaf := &LeafNode{FeatureStats: featureStats, WeightAtLastEval: node.Weight()}
+	node.Kind = &Node_Leaf{Leaf: leaf}
+	return node.ByteSize() - before - reduction
+}
+
+// Compact rebuilds the node registry without the nodes which are no
+// longer reachable from the root, i.e. subtrees discarded by split
+// re-evaluation. Node references are re-assigned in depth-first order.
+// Returns the number of removed nodes.
+func (t *Tree) Compact() int {
+	refs := make([]int64, len(t.Nodes)+1)
+	nodes := t.collect(t.Root, refs, make([]*Node, 0, len(t.Nodes)))
+
+	for _, node := range nodes {
+		if split := node.GetSplit(); split != nil {
+			split.Children.remap(refs)
 		}
 	}
+
+	removed := len(t.Nodes) - len(nodes)
+	t.Root = lookupRef(refs, t.Root)
+	t.Nodes = nodes
+	return removed
+}
+
+// collect appends all reachable nodes of a subtree to dst in depth-first
+// order and records their new references.
+func (t *Tree) collect(nodeRef int64, refs []int64, dst []*Node) []*Node {
+	node := t.Get(nodeRef)
+	if node == nil || refs[nodeRef] != 0 {
+		return dst
+	}
+
+	dst = append(dst, node)
+	refs[nodeRef] = int64(len(dst))
+
+	if split := node.GetSplit(); split != nil {
+		split.Children.ForEachOrdered(func(_ int, childRef int64) bool {
+			dst = t.collect(childRef, refs, dst)
+			return true
+		})
+	}
 	return dst
 }
 
@@ -245,6 +500,71 @@ func (t *Tree) Accumulate(nodeRef int64, depth int, info *common.TreeInfo) {
 	}
 }
 
+// AccumulateImportances collects the weighted impurity decreases of all
+// reachable split nodes and returns the aggregated stats of the subtree.
+// As split node stats are not updated after the split, decreases are
+// calculated from the aggregated stats of the child subtrees.
+func (t *Tree) AccumulateImportances(nodeRef int64, depth int, crit regression.SplitCriterion, acc common.ImportanceAccumulator) *util.StreamStats {
+	node := t.Get(nodeRef)
+	if node == nil {
+		return nil
+	}
+
+	split := node.GetSplit()
+	if split == nil {
+		return node.Stats
+	}
+
+	pre := new(util.StreamStats)
+	post := new(util.StreamStatsDistribution)
+	split.Children.ForEach(func(i int, childRef int64) bool {
+		if stats := t.AccumulateImportances(childRef, depth+1, crit, acc); stats != nil {
+			pre.Merge(stats)
+			post.Merge(i, stats)
+		}
+		return true
+	})
+	acc.Add(split.Feature, pre.Weight*crit.Merit(pre, post), depth)
+	return pre
+}
+
+// Contributions calculates additive feature contributions (SHAP values)
+// for example x, starting at the given node ID. Node weights are used as cover.
+func (t *Tree) Contributions(x core.Example, nodeRef int64) *common.Contributions {
+	return hoeffding.TreeSHAP(t.shapNode(x, nodeRef))
+}
+
+// shapNode converts the subtree at the given node ID into a SHAP node,
+// routing example x. Leaves output the mean target value.
+func (t *Tree) shapNode(x core.Example, nodeRef int64) *hoeffding.SHAPNode {
+	node := t.Get(nodeRef)
+	if node == nil {
+		return nil
+	}
+
+	split := node.GetSplit()
+	if split == nil {
+		res := &hoeffding.SHAPNode{Cover: node.Weight()}
+		if node.Weight() > 0 {
+			res.Values = []float64{node.Stats.Mean()}
+		}
+		return res
+	}
+
+	res := &hoeffding.SHAPNode{Feature: split.Feature, Hot: -1}
+	nodeIndex := split.childIndex(t.Model.Feature(split.Feature), x)
+	split.Children.ForEach(func(i int, childRef int64) bool {
+		if child := t.shapNode(x, childRef); child != nil {
+			if i == nodeIndex {
+				res.Hot = len(res.Children)
+			}
+			res.Children = append(res.Children, child)
+		}
+		return true
+	})
+	return res
+}
+
 // WriteTo writes a tree to a Writer.
 func (t *Tree) WriteTo(w io.Writer) (int64, error) {
 	wc := &iocount.Writer{W: w}
@@ -0,0 +1,74 @@
+package hoeffding
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/bsm/reason/core"
+	"github.com/bsm/reason/regression"
+	"github.com/bsm/reason/regression/hoeffding/internal"
+)
+
+// Snapshot is an immutable, flattened copy of a tree, optimised for
+// inference. Snapshots do not contain any training stats and are safe
+// for concurrent use.
+type Snapshot struct {
+	snap     *internal.Snapshot
+	features []*core.Feature
+}
+
+// LoadSnapshot loads a snapshot from a reader.
+func LoadSnapshot(r io.Reader) (*Snapshot, error) {
+	snap := new(internal.Snapshot)
+	if _, err := snap.ReadFrom(r); err != nil {
+		return nil, err
+	}
+	return newSnapshot(snap)
+}
+
+func newSnapshot(snap *internal.Snapshot) (*Snapshot, error) {
+	if snap.Model == nil {
+		return nil, fmt.Errorf("hoeffding: invalid snapshot")
+	}
+	if err := snap.Validate(); err != nil {
+		return nil, err
+	}
+
+	features := make([]*core.Feature, 0, len(snap.FeatureNames))
+	for _, name := range snap.FeatureNames {
+		feat := snap.Model.Feature(name)
+		if feat == nil {
+			return nil, fmt.Errorf("hoeffding: unknown feature %q", name)
+		}
+
+		// snapshots are immutable, unknown values must not expand the vocabulary
+		if feat.Strategy == core.Feature_EXPANDABLE {
+			clone := *feat
+			clone.Strategy = core.Feature_VOCABULARY
+			feat = &clone
+		}
+		features = append(features, feat)
+	}
+	return &Snapshot{snap: snap, features: features}, nil
+}
+
+// Snapshot creates a snapshot of the current tree.
+func (t *Tree) Snapshot() (*Snapshot, error) {
+	tree, release := t.acquire()
+	snap := tree.Snapshot()
+	release()
+
+	return newSnapshot(snap)
+}
+
+// Predict traverses the snapshot for the given example x and returns
+// the prediction of the final node.
+func (s *Snapshot) Predict(x core.Example) regression.Prediction {
+	pos := s.snap.Traverse(s.features, x)
+	return regression.Prediction{StreamStats: *s.snap.Prediction(pos)}
+}
+
+// WriteTo writes a snapshot to a Writer.
+func (s *Snapshot) WriteTo(w io.Writer) (int64, error) {
+	return s.snap.WriteTo(w)
+}
@@ -2,17 +2,44 @@ package hoeffding_test
 
 import (
 	"bytes"
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"testing"
 
 	common "github.com/bsm/reason/common/hoeffding"
 	"github.com/bsm/reason/core"
 	"github.com/bsm/reason/regression"
 	"github.com/bsm/reason/regression/hoeffding"
+	"github.com/bsm/reason/regression/hoeffding/internal"
 	"github.com/bsm/reason/testdata"
+	"github.com/gogo/protobuf/proto"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 )
 
+type traceRecorder []*common.Trace
+
+func (r *traceRecorder) Trace(e *common.Trace) { *r = append(*r, e) }
+
+func (r traceRecorder) First(kind common.TraceKind) *common.Trace {
+	for _, e := range r {
+		if e.Kind == kind {
+			return e
+		}
+	}
+	return nil
+}
+
+// withoutByteSize strips the estimated byte size from tree info, it is
+// covered by dedicated specs.
+func withoutByteSize(info *common.TreeInfo) *common.TreeInfo {
+	info.ByteSize = 0
+	return info
+}
+
 var _ = Describe("Tree", func() {
 
 	var train = func(n int) (*hoeffding.Tree, *core.Model, []core.Example) {
@@ -38,7 +65,7 @@ var _ = Describe("Tree", func() {
 		}
 
 		t1, _, examples := train(3000)
-		Expect(t1.Info()).To(Equal(&common.TreeInfo{NumNodes: 626, NumLearning: 625, MaxDepth: 2}))
+		Expect(withoutByteSize(t1.Info())).To(Equal(&common.TreeInfo{NumNodes: 626, NumLearning: 625, MaxDepth: 2}))
 		Expect(t1.Predict(nil, examples[4001]).Best().Mean()).To(BeNumerically("~", 0.260, 0.001))
 
 		b1 := new(bytes.Buffer)
@@ -46,13 +73,108 @@ var _ = Describe("Tree", func() {
 
 		t2, err := hoeffding.Load(b1, c)
 		Expect(err).NotTo(HaveOccurred())
-		Expect(t2.Info()).To(Equal(&common.TreeInfo{NumNodes: 626, NumLearning: 625, MaxDepth: 2}))
+		Expect(withoutByteSize(t2.Info())).To(Equal(&common.TreeInfo{NumNodes: 626, NumLearning: 625, MaxDepth: 2}))
 		Expect(t2.Predict(nil, examples[4001]).Best().Mean()).To(BeNumerically("~", 0.260, 0.001))
 	})
 
+	It("should snapshot", func() {
+		tree, _, examples := train(3000)
+
+		snap, err := tree.Snapshot()
+		Expect(err).NotTo(HaveOccurred())
+
+		b := new(bytes.Buffer)
+		Expect(snap.WriteTo(b)).To(Equal(int64(b.Len())))
+
+		loaded, err := hoeffding.LoadSnapshot(b)
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, x := range examples[3000:] {
+			expected := *tree.Predict(nil, x).Best()
+			Expect(snap.Predict(x)).To(Equal(expected))
+			Expect(loaded.Predict(x)).To(Equal(expected))
+		}
+
+		x := examples[4001]
+		Expect(testing.AllocsPerRun(100, func() { snap.Predict(x) })).To(BeZero())
+
+		_, err = hoeffding.LoadSnapshot(new(bytes.Buffer))
+		Expect(err).To(MatchError("hoeffding: invalid snapshot"))
+
+		// corrupted snapshots are rejected at load time
+		data := new(bytes.Buffer)
+		Expect(snap.WriteTo(data)).To(Equal(int64(data.Len())))
+		for _, corrupt := range []func(*internal.Snapshot){
+			func(s *internal.Snapshot) { s.Pivots = s.Pivots[1:] },
+			func(s *internal.Snapshot) { s.SubsetOffsets = s.SubsetOffsets[1:] },
+			func(s *internal.Snapshot) { s.ChildOffsets[1] = int64(len(s.Children) + 1) },
+			func(s *internal.Snapshot) { s.Children[0] = int64(len(s.Features)) },
+			func(s *internal.Snapshot) { s.Children[0] = 0 },
+			func(s *internal.Snapshot) { s.Features[0] = int64(len(s.FeatureNames)) },
+		} {
+			raw := new(internal.Snapshot)
+			Expect(proto.Unmarshal(data.Bytes(), raw)).To(Succeed())
+			corrupt(raw)
+
+			b := new(bytes.Buffer)
+			Expect(raw.WriteTo(b)).To(Equal(int64(b.Len())))
+			_, err = hoeffding.LoadSnapshot(b)
+			Expect(err).To(HaveOccurred())
+		}
+	})
+
+	It("should publish copy-on-write", func() {
+		stream, model, err := testdata.OpenRegression("../../testdata")
+		Expect(err).NotTo(HaveOccurred())
+		defer stream.Close()
+
+		examples, err := stream.ReadN(1000)
+		Expect(err).NotTo(HaveOccurred())
+
+		tree, err := hoeffding.New(model, "target", &hoeffding.Config{
+			Config: common.Config{GracePeriod: 50, PublishPeriod: 100},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, x := range examples[:99] {
+			tree.Train(x, 1.0)
+		}
+		Expect(withoutByteSize(tree.Info())).To(Equal(&common.TreeInfo{NumNodes: 1, NumLearning: 1, MaxDepth: 1}))
+		Expect(tree.Predict(nil, examples[0]).Best().Weight).To(Equal(0.0))
+
+		tree.Train(examples[99], 1.0)
+		Expect(tree.Predict(nil, examples[0]).Best().Weight).To(Equal(100.0))
+
+		for _, x := range examples[100:150] {
+			tree.Train(x, 1.0)
+		}
+		Expect(tree.Predict(nil, examples[0]).Best().Weight).To(Equal(100.0))
+
+		tree.Publish()
+		Expect(tree.Predict(nil, examples[0]).Best().Weight).To(Equal(150.0))
+
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+
+				for _, x := range examples {
+					tree.Predict(nil, x)
+					tree.Info()
+				}
+			}()
+		}
+		for _, x := range examples {
+			tree.Train(x, 1.0)
+		}
+		wg.Wait()
+	})
+
 	It("should prune", func() {
 		t, _, _ := train(3000)
-		Expect(t.Info()).To(Equal(&common.TreeInfo{
+		Expect(withoutByteSize(t.Info())).To(Equal(&common.TreeInfo{
 			NumNodes:    626,
 			NumLearning: 625,
 			NumDisabled: 0,
@@ -60,7 +182,7 @@ var _ = Describe("Tree", func() {
 		}))
 
 		t.Prune(10)
-		Expect(t.Info()).To(Equal(&common.TreeInfo{
+		Expect(withoutByteSize(t.Info())).To(Equal(&common.TreeInfo{
 			NumNodes:    626,
 			NumLearning: 10,
 			NumDisabled: 615,
@@ -68,6 +190,86 @@ var _ = Describe("Tree", func() {
 		}))
 	})
 
+	It("should evaluate splits concurrently", func() {
+		stream, model, err := testdata.OpenRegression("../../testdata")
+		Expect(err).NotTo(HaveOccurred())
+		defer stream.Close()
+
+		examples, err := stream.ReadN(3000)
+		Expect(err).NotTo(HaveOccurred())
+
+		var dumps []string
+		for _, workers := range []int{1, 4} {
+			tree, err := hoeffding.New(model, "target", &hoeffding.Config{
+				Config: common.Config{SplitWorkers: workers},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, x := range examples {
+				tree.Train(x, 1.0)
+			}
+
+			b := new(bytes.Buffer)
+			Expect(tree.WriteText(b)).To(Equal(int64(b.Len())))
+			dumps = append(dumps, b.String())
+		}
+		Expect(dumps[0]).To(ContainSubstring("\tc1 = #4"))
+		Expect(dumps[1]).To(Equal(dumps[0]))
+	})
+
+	It("should train deterministically", func() {
+		stream, model, err := testdata.OpenRegression("../../testdata")
+		Expect(err).NotTo(HaveOccurred())
+		defer stream.Close()
+
+		examples, err := stream.ReadN(5000)
+		Expect(err).NotTo(HaveOccurred())
+
+		var dumps [][]byte
+		for i := 0; i < 2; i++ {
+			tree, err := hoeffding.New(model, "target", &hoeffding.Config{
+				Config: common.Config{GracePeriod: 50, Deterministic: true, SplitWorkers: 4},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, x := range examples {
+				tree.Train(x, 1.0)
+			}
+
+			b := new(bytes.Buffer)
+			Expect(tree.WriteTo(b)).To(Equal(int64(b.Len())))
+			dumps = append(dumps, b.Bytes())
+		}
+		Expect(dumps[1]).To(Equal(dumps[0]))
+	})
+
+	It("should break ties deterministically", func() {
+		model := core.NewModel(
+			core.NewNumericalFeature("a"),
+			core.NewNumericalFeature("b"),
+			core.NewNumericalFeature("target"),
+		)
+		rnd := rand.New(rand.NewSource(1))
+
+		examples := make([]core.Example, 0, 5000)
+		for i := 0; i < 5000; i++ {
+			v := rnd.Float64()
+			examples = append(examples, core.MapExample{"a": v, "b": v, "target": v*10 + rnd.NormFloat64()})
+		}
+
+		for i := 0; i < 10; i++ {
+			tree, err := hoeffding.New(model, "target", &hoeffding.Config{
+				Config: common.Config{GracePeriod: 50, Deterministic: true},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, x := range examples {
+				tree.Train(x, 1.0)
+			}
+			Expect(tree.Explain(examples[0]).Steps[0].Feature).To(Equal("a"))
+		}
+	})
+
 	It("should write TXT", func() {
 		t, _, _ := train(3000)
 
@@ -90,10 +292,750 @@ var _ = Describe("Tree", func() {
 		Expect(s).To(ContainSubstring(`N_4 [label="c1 = #4\nweight: 4"];`))
 	})
 
+	It("should trace", func() {
+		model := core.NewModel(
+			core.NewCategoricalFeature("a", []string{"x", "y"}),
+			core.NewNumericalFeature("target"),
+		)
+		rnd := rand.New(rand.NewSource(1))
+		tracer := new(traceRecorder)
+		tree, err := hoeffding.New(model, "target", &hoeffding.Config{
+			Config: common.Config{GracePeriod: 50, PrunePeriod: 100, MaxLearningNodes: 1, Tracer: tracer},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tree.Traces()).To(BeNil())
+
+		for i := 0; i < 1000; i++ {
+			x := core.MapExample{"a": "x", "target": rnd.NormFloat64()}
+			if rnd.Intn(2) == 0 {
+				x["a"] = "y"
+				x["target"] = 10 + rnd.NormFloat64()
+			}
+			tree.Train(x, 1.0)
+		}
+
+		kinds := make(map[common.TraceKind]int)
+		for _, e := range *tracer {
+			kinds[e.Kind]++
+		}
+		Expect(kinds).To(HaveLen(3))
+		Expect(kinds[common.TraceSplitAttempt]).To(Equal(11))
+		Expect(kinds[common.TraceSplit]).To(Equal(1))
+		Expect(kinds[common.TracePrune]).To(BeNumerically(">", 0))
+
+		split := tracer.First(common.TraceSplit)
+		Expect(split).NotTo(BeNil())
+		Expect(split.NodeRef).To(Equal(int64(1)))
+		Expect(split.Feature).To(Equal("a"))
+
+		prune := tracer.First(common.TracePrune)
+		Expect(prune).NotTo(BeNil())
+		Expect(prune.NumDeactivated).To(Equal(1))
+	})
+
+	It("should re-evaluate splits", func() {
+		model := core.NewModel(
+			core.NewCategoricalFeature("a", []string{"x", "y"}),
+			core.NewCategoricalFeature("b", []string{"x", "y"}),
+			core.NewNumericalFeature("target"),
+		)
+		rnd := rand.New(rand.NewSource(1))
+		stream := func(n int, concept string) []core.Example {
+			examples := make([]core.Example, 0, n)
+			for i := 0; i < n; i++ {
+				x := core.MapExample{"a": "x", "b": "x"}
+				if rnd.Intn(2) == 0 {
+					x["a"] = "y"
+				}
+				if rnd.Intn(2) == 0 {
+					x["b"] = "y"
+				}
+
+				target := rnd.NormFloat64()
+				if x[concept] == "x" {
+					target += 10
+				}
+				x["target"] = target
+				examples = append(examples, x)
+			}
+			return examples
+		}
+
+		tree, err := hoeffding.New(model, "target", &hoeffding.Config{
+			Config: common.Config{GracePeriod: 50, ReevaluateSplits: true},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, x := range stream(1000, "a") {
+			tree.Train(x, 1.0)
+		}
+
+		b := new(bytes.Buffer)
+		Expect(tree.WriteText(b)).To(Equal(int64(b.Len())))
+		Expect(b.String()).To(ContainSubstring("\n\ta = x"))
+
+		var restructured *common.SplitAttemptInfo
+		for _, x := range stream(3000, "b") {
+			if info := tree.Train(x, 1.0); info != nil && info.Reevaluation && info.Success && restructured == nil {
+				restructured = info
+			}
+		}
+		Expect(restructured).NotTo(BeNil())
+		Expect(restructured.Candidates[0].Feature).To(Equal("b"))
+
+		b.Reset()
+		Expect(tree.WriteText(b)).To(Equal(int64(b.Len())))
+		Expect(b.String()).To(ContainSubstring("\n\tb = x"))
+		Expect(b.String()).NotTo(ContainSubstring("\n\ta = x"))
+	})
+
+	It("should apply growth constraints to re-evaluated splits", func() {
+		model := core.NewModel(
+			core.NewCategoricalFeature("a", []string{"x", "y"}),
+			core.NewCategoricalFeature("b", []string{"x", "y"}),
+			core.NewNumericalFeature("target"),
+		)
+		rnd := rand.New(rand.NewSource(1))
+		stream := func(n int, concept string) []core.Example {
+			examples := make([]core.Example, 0, n)
+			for i := 0; i < n; i++ {
+				x := core.MapExample{"a": "x", "b": "x"}
+				if rnd.Intn(2) == 0 {
+					x["a"] = "y"
+				}
+				if rnd.Intn(2) == 0 {
+					x["b"] = "y"
+				}
+
+				target := rnd.NormFloat64()
+				if x[concept] == "x" {
+					target += 10
+				}
+				x["target"] = target
+				examples = append(examples, x)
+			}
+			return examples
+		}
+
+		tree, err := hoeffding.New(model, "target", &hoeffding.Config{
+			Config: common.Config{GracePeriod: 50, ReevaluateSplits: true, MinMeritGain: 10},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, x := range stream(1000, "a") {
+			tree.Train(x, 1.0)
+		}
+
+		b := new(bytes.Buffer)
+		Expect(tree.WriteText(b)).To(Equal(int64(b.Len())))
+		Expect(b.String()).To(ContainSubstring("\n\ta = x"))
+
+		var rejected *common.SplitAttemptInfo
+		for _, x := range stream(3000, "b") {
+			if info := tree.Train(x, 1.0); info != nil && info.Reevaluation && info.Rejection != common.RejectNone && rejected == nil {
+				rejected = info
+			}
+		}
+		Expect(rejected).NotTo(BeNil())
+		Expect(rejected.Success).To(BeFalse())
+		Expect(rejected.Rejection).To(Equal(common.RejectMinMeritGain))
+		Expect(rejected.Candidates[0].Feature).To(Equal("b"))
+		Expect(rejected.Candidates[0].Merit).To(BeNumerically("<", 10))
+		Expect(rejected.MeritGain).To(BeNumerically(">", rejected.HoeffdingBound))
+	})
+
+	It("should compact", func() {
+		model := core.NewModel(
+			core.NewCategoricalFeature("a", []string{"x", "y"}),
+			core.NewCategoricalFeature("b", []string{"x", "y"}),
+			core.NewNumericalFeature("target"),
+		)
+		rnd := rand.New(rand.NewSource(1))
+		stream := func(n int, concept string) []core.Example {
+			examples := make([]core.Example, 0, n)
+			for i := 0; i < n; i++ {
+				x := core.MapExample{"a": "x", "b": "x"}
+				if rnd.Intn(2) == 0 {
+					x["a"] = "y"
+				}
+				if rnd.Intn(2) == 0 {
+					x["b"] = "y"
+				}
+
+				target := rnd.NormFloat64()
+				if x[concept] == "x" {
+					target += 10
+				}
+				x["target"] = target
+				examples = append(examples, x)
+			}
+			return examples
+		}
+
+		tree, err := hoeffding.New(model, "target", &hoeffding.Config{
+			Config: common.Config{GracePeriod: 50, ReevaluateSplits: true},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, x := range stream(1000, "a") {
+			tree.Train(x, 1.0)
+		}
+		for _, x := range stream(3000, "b") {
+			tree.Train(x, 1.0)
+		}
+
+		info := tree.Info()
+		Expect(withoutByteSize(tree.Info())).To(Equal(&common.TreeInfo{NumNodes: 7, NumLearning: 4, MaxDepth: 3}))
+
+		examples := stream(100, "b")
+		predictions := make([]regression.Predictions, 0, len(examples))
+		for _, x := range examples {
+			predictions = append(predictions, tree.Predict(nil, x))
+		}
+
+		b1 := new(bytes.Buffer)
+		Expect(tree.WriteTo(b1)).To(Equal(int64(b1.Len())))
+		Expect(b1.Len()).To(Equal(1663))
+
+		Expect(tree.Compact()).To(Equal(6))
+		Expect(tree.Compact()).To(Equal(0))
+		Expect(tree.Info().ByteSize).To(BeNumerically("<", info.ByteSize))
+		Expect(withoutByteSize(tree.Info())).To(Equal(withoutByteSize(info)))
+		for i, x := range examples {
+			Expect(tree.Predict(nil, x)).To(Equal(predictions[i]))
+		}
+
+		b2 := new(bytes.Buffer)
+		Expect(tree.WriteTo(b2)).To(Equal(int64(b2.Len())))
+		Expect(b2.Len()).To(Equal(1301))
+	})
+
+	It("should apply split bounds", func() {
+		model := core.NewModel(
+			core.NewCategoricalFeature("a", []string{"x", "y"}),
+			core.NewNumericalFeature("target"),
+		)
+		firstAttempt := func(bound common.Bound) *common.SplitAttemptInfo {
+			tree, err := hoeffding.New(model, "target", &hoeffding.Config{
+				Config: common.Config{GracePeriod: 50, SplitBound: bound},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			rnd := rand.New(rand.NewSource(1))
+			for {
+				x := core.MapExample{"a": "x", "target": rnd.NormFloat64()}
+				if rnd.Intn(2) == 0 {
+					x["a"] = "y"
+					x["target"] = 10 + rnd.NormFloat64()
+				}
+				if info := tree.Train(x, 1.0); info != nil {
+					return info
+				}
+			}
+		}
+
+		h := firstAttempt(common.HoeffdingBound{})
+		Expect(h.Success).To(BeTrue())
+		Expect(h.HoeffdingBound).To(BeNumerically(">", 0))
+
+		g := firstAttempt(common.GaussianBound{})
+		Expect(g.Success).To(BeTrue())
+		Expect(g.HoeffdingBound / h.HoeffdingBound).To(BeNumerically("~", 0.916, 0.001))
+
+		_, err := hoeffding.New(model, "target", &hoeffding.Config{
+			Config: common.Config{SplitBound: common.McDiarmidGini{}},
+		})
+		Expect(err).To(MatchError(`hoeffding: McDiarmidGini bound is not supported by regression trees`))
+	})
+
+	DescribeTable("should sample feature subspaces",
+		func(config common.Config, expFeatures []string) {
+			model := core.NewModel(
+				core.NewCategoricalFeature("a", []string{"x", "y"}),
+				core.NewCategoricalFeature("b", []string{"x", "y"}),
+				core.NewCategoricalFeature("c", []string{"x", "y"}),
+				core.NewNumericalFeature("target"),
+			)
+			config.GracePeriod = 50
+			tree, err := hoeffding.New(model, "target", &hoeffding.Config{Config: config})
+			Expect(err).NotTo(HaveOccurred())
+
+			rnd := rand.New(rand.NewSource(1))
+			for i := 0; i < 5000; i++ {
+				x := core.MapExample{"a": "x", "b": "x", "c": "x"}
+				target := rnd.NormFloat64()
+				for _, name := range []string{"a", "b", "c"} {
+					if rnd.Intn(2) == 0 {
+						x[name] = "y"
+					}
+				}
+				if x["b"] == "x" {
+					target += 10
+				}
+				x["target"] = target
+				tree.Train(x, 1.0)
+			}
+
+			var features []string
+			for _, fi := range tree.FeatureImportances() {
+				features = append(features, fi.Feature)
+			}
+			Expect(features).To(Equal(expFeatures))
+		},
+
+		Entry("all features", common.Config{}, []string{"b"}),
+		Entry("one feature", common.Config{FeatureSubspace: 1, Seed: 1}, []string{"b", "a", "c"}),
+		Entry("one feature (reseeded)", common.Config{FeatureSubspace: 1, Seed: 2}, []string{"b"}),
+		Entry("two features", common.Config{FeatureSubspace: 2, Seed: 4}, []string{"b"}),
+	)
+
+	DescribeTable("should limit growth",
+		func(config common.Config, expInfo *common.TreeInfo, expRejections map[common.SplitRejection]int) {
+			model := core.NewModel(
+				core.NewCategoricalFeature("a", []string{"x", "y"}),
+				core.NewCategoricalFeature("b", []string{"x", "y"}),
+				core.NewNumericalFeature("target"),
+			)
+			tree, err := hoeffding.New(model, "target", &hoeffding.Config{Config: config})
+			Expect(err).NotTo(HaveOccurred())
+
+			rnd := rand.New(rand.NewSource(1))
+			rejections := make(map[common.SplitRejection]int)
+			for i := 0; i < 5000; i++ {
+				x := core.MapExample{"a": "x", "b": "x"}
+				target := rnd.NormFloat64()
+				if rnd.Intn(2) == 0 {
+					x["a"] = "y"
+				}
+				if rnd.Intn(2) == 0 {
+					x["b"] = "y"
+				}
+				if x["a"] == "x" && x["b"] == "x" {
+					target += 10
+				}
+				x["target"] = target
+
+				if info := tree.Train(x, 1.0); info != nil && info.Rejection != common.RejectNone {
+					rejections[info.Rejection]++
+				}
+			}
+			Expect(withoutByteSize(tree.Info())).To(Equal(expInfo))
+			Expect(rejections).To(Equal(expRejections))
+		},
+
+		Entry("unlimited", common.Config{GracePeriod: 50},
+			&common.TreeInfo{NumNodes: 5, NumLearning: 3, MaxDepth: 3},
+			map[common.SplitRejection]int{}),
+		Entry("max depth", common.Config{GracePeriod: 50, MaxDepth: 2},
+			&common.TreeInfo{NumNodes: 3, NumLearning: 2, MaxDepth: 2},
+			map[common.SplitRejection]int{common.RejectMaxDepth: 98}),
+		Entry("min leaf weight", common.Config{GracePeriod: 50, MinLeafWeight: 1500},
+			&common.TreeInfo{NumNodes: 3, NumLearning: 2, MaxDepth: 2},
+			map[common.SplitRejection]int{common.RejectMinLeafWeight: 76}),
+		Entry("min merit gain", common.Config{GracePeriod: 50, MinMeritGain: 15},
+			&common.TreeInfo{NumNodes: 1, NumLearning: 1, MaxDepth: 1},
+			map[common.SplitRejection]int{common.RejectMinMeritGain: 93}),
+	)
+
+	It("should predict leaf identifiers", func() {
+		model := core.NewModel(
+			core.NewCategoricalFeature("a", []string{"x", "y"}),
+			core.NewCategoricalFeature("b", []string{"x", "y"}),
+			core.NewNumericalFeature("target"),
+		)
+		rnd := rand.New(rand.NewSource(1))
+		tree, err := hoeffding.New(model, "target", &hoeffding.Config{
+			Config: common.Config{GracePeriod: 50, LeafBuckets: 4},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		ref, depth := tree.PredictLeaf(core.MapExample{"a": "x"})
+		Expect(ref).To(Equal(int64(1)))
+		Expect(depth).To(Equal(1))
+
+		for i := 0; i < 1000; i++ {
+			x := core.MapExample{"a": "x", "b": "x", "target": rnd.NormFloat64()}
+			if rnd.Intn(2) == 0 {
+				x["a"] = "y"
+				x["target"] = 10 + rnd.NormFloat64()
+			}
+			if rnd.Intn(2) == 0 {
+				x["b"] = "y"
+			}
+			tree.Train(x, 1.0)
+		}
+		Expect(withoutByteSize(tree.Info())).To(Equal(&common.TreeInfo{NumNodes: 3, NumLearning: 2, MaxDepth: 2}))
+
+		refX, depth := tree.PredictLeaf(core.MapExample{"a": "x"})
+		Expect(depth).To(Equal(2))
+		refY, depth := tree.PredictLeaf(core.MapExample{"a": "y", "b": "x"})
+		Expect(depth).To(Equal(2))
+		Expect(refX).NotTo(Equal(refY))
+
+		feat := tree.LeafFeature("leaf")
+		Expect(feat.NumCategories()).To(Equal(4))
+		Expect(feat.Category(core.MapExample{"leaf": refY})).To(Equal(core.Category(refY)))
+
+		small, err := hoeffding.New(model, "target", &hoeffding.Config{
+			Config: common.Config{LeafBuckets: 1},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		ref, depth = small.PredictLeaf(core.MapExample{"a": "x"})
+		Expect(ref).To(Equal(int64(-1)))
+		Expect(depth).To(Equal(1))
+		Expect(small.LeafFeature("leaf").Category(core.MapExample{"leaf": ref})).To(Equal(core.NoCategory))
+	})
+
+	DescribeTable("should prune by promise",
+		func(pruneByPromise bool, expInfo *common.TreeInfo) {
+			model := core.NewModel(
+				core.NewCategoricalFeature("a", []string{"x", "y"}),
+				core.NewCategoricalFeature("b", []string{"x", "y"}),
+				core.NewNumericalFeature("target"),
+			)
+			rnd := rand.New(rand.NewSource(1))
+			stream := func(n int) []core.Example {
+				examples := make([]core.Example, 0, n)
+				for i := 0; i < n; i++ {
+					x := core.MapExample{"a": "x", "b": "x", "target": 20 + rnd.NormFloat64()*0.1}
+					if rnd.Intn(2) == 0 {
+						x["b"] = "y"
+					}
+					if rnd.Intn(4) == 0 {
+						x["a"] = "y"
+						x["target"] = rnd.NormFloat64()
+						if x["b"] == "x" {
+							x["target"] = 10 + rnd.NormFloat64()
+						}
+					}
+					examples = append(examples, x)
+				}
+				return examples
+			}
+
+			tree, err := hoeffding.New(model, "target", &hoeffding.Config{
+				Config: common.Config{GracePeriod: 50, PruneByPromise: pruneByPromise},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, x := range stream(150) {
+				tree.Train(x, 1.0)
+			}
+			Expect(withoutByteSize(tree.Info())).To(Equal(&common.TreeInfo{NumNodes: 3, NumLearning: 2, MaxDepth: 2}))
+
+			tree.Prune(1)
+			for _, x := range stream(2000) {
+				tree.Train(x, 1.0)
+			}
+			Expect(withoutByteSize(tree.Info())).To(Equal(expInfo))
+		},
+
+		Entry("by weight", false, &common.TreeInfo{NumNodes: 3, NumLearning: 1, NumDisabled: 1, MaxDepth: 2}),
+		Entry("by promise", true, &common.TreeInfo{NumNodes: 5, NumLearning: 2, NumDisabled: 1, MaxDepth: 3}),
+	)
+
+	It("should remove poor features", func() {
+		model := core.NewModel(
+			core.NewCategoricalFeature("a", []string{"x", "y"}),
+			core.NewCategoricalFeature("b", []string{"x", "y"}),
+			core.NewCategoricalFeature("c", []string{"x", "y"}),
+			core.NewNumericalFeature("target"),
+		)
+
+		train := func(removePoorFeatures bool) *common.TreeInfo {
+			rnd := rand.New(rand.NewSource(1))
+			tree, err := hoeffding.New(model, "target", &hoeffding.Config{
+				Config: common.Config{GracePeriod: 50, RemovePoorFeatures: removePoorFeatures},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			for i := 0; i < 2000; i++ {
+				x := core.MapExample{"a": "x", "c": "x", "target": rnd.NormFloat64()}
+				if rnd.Intn(2) == 0 {
+					x["a"] = "y"
+					x["target"] = 10 + rnd.NormFloat64()
+				}
+				if rnd.Intn(2) == 0 {
+					x["c"] = "y"
+				}
+				x["b"] = x["a"]
+				tree.Train(x, 1.0)
+			}
+			return tree.Info()
+		}
+
+		Expect(train(false)).To(Equal(&common.TreeInfo{NumNodes: 1, NumLearning: 1, MaxDepth: 1, ByteSize: 672}))
+		Expect(train(true)).To(Equal(&common.TreeInfo{NumNodes: 1, NumLearning: 1, MaxDepth: 1, ByteSize: 496}))
+	})
+
+	It("should explain", func() {
+		model := core.NewModel(
+			core.NewCategoricalFeature("a", []string{"x", "y"}),
+			core.NewNumericalFeature("target"),
+		)
+
+		rnd := rand.New(rand.NewSource(1))
+		tree, err := hoeffding.New(model, "target", &hoeffding.Config{
+			Config: common.Config{GracePeriod: 50},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		for i := 0; i < 1000; i++ {
+			x := core.MapExample{"a": "x", "target": rnd.NormFloat64()}
+			if rnd.Intn(2) == 0 {
+				x["a"] = "y"
+				x["target"] = 10 + rnd.NormFloat64()
+			}
+			tree.Train(x, 1.0)
+		}
+
+		x := core.MapExample{"a": "y"}
+		exp := tree.Explain(x)
+		Expect(exp.Stop).To(Equal(common.StopLeaf))
+		Expect(exp.Steps).To(HaveLen(2))
+		Expect(exp.Steps[0].DecisionStep).To(Equal(common.DecisionStep{Weight: 50, Feature: "a", Condition: "a = y", Value: "y"}))
+		Expect(exp.Steps[1].DecisionStep).To(Equal(common.DecisionStep{Weight: 510}))
+
+		predictions := tree.Predict(nil, x)
+		Expect(exp.Steps[0].Prediction).To(Equal(predictions[0]))
+		Expect(exp.Steps[1].Prediction).To(Equal(predictions[1]))
+		Expect(exp.Steps[1].Prediction.Mean()).To(BeNumerically("~", 10.0, 0.1))
+
+		exp = tree.Explain(core.MapExample{})
+		Expect(exp.Stop).To(Equal(common.StopLeaf))
+		Expect(exp.Steps).To(HaveLen(2))
+		Expect(exp.Steps[0].Missing).To(BeTrue())
+	})
+
+	It("should calculate contributions", func() {
+		model := core.NewModel(
+			core.NewCategoricalFeature("a", []string{"x", "y"}),
+			core.NewNumericalFeature("b"),
+			core.NewNumericalFeature("target"),
+		)
+
+		rnd := rand.New(rand.NewSource(1))
+		tree, err := hoeffding.New(model, "target", &hoeffding.Config{
+			Config: common.Config{GracePeriod: 50},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		for i := 0; i < 5000; i++ {
+			x := core.MapExample{"a": "x", "b": rnd.Float64()}
+			target := x["b"].(float64) + rnd.NormFloat64()*0.1
+			if rnd.Intn(2) == 0 {
+				x["a"] = "y"
+				target += 10
+			}
+			x["target"] = target
+			tree.Train(x, 1.0)
+		}
+
+		x := core.MapExample{"a": "y", "b": 0.9}
+		res := tree.Contributions(x)
+		Expect(res.Features).To(HaveLen(2))
+		Expect(res.Expected[0]).To(BeNumerically("~", 5.5, 0.2))
+		Expect(res.Features["a"][0]).To(BeNumerically("~", 5.0, 0.2))
+		Expect(res.Features["b"][0]).To(BeNumerically(">", 0))
+		Expect(res.Output[0]).To(BeNumerically("~", tree.Predict(nil, x).Best().Mean(), 1e-9))
+		Expect(res.Expected[0] + res.Features["a"][0] + res.Features["b"][0]).To(BeNumerically("~", res.Output[0], 1e-9))
+	})
+
+	It("should calculate feature importances", func() {
+		model := core.NewModel(
+			core.NewCategoricalFeature("a", []string{"x", "y"}),
+			core.NewNumericalFeature("b"),
+			core.NewNumericalFeature("c"),
+			core.NewNumericalFeature("target"),
+		)
+
+		rnd := rand.New(rand.NewSource(1))
+		tree, err := hoeffding.New(model, "target", &hoeffding.Config{
+			Config: common.Config{GracePeriod: 50, IncludeFeatureImportances: true},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tree.FeatureImportances()).To(BeEmpty())
+
+		for i := 0; i < 5000; i++ {
+			x := core.MapExample{"a": "x", "b": rnd.Float64(), "c": rnd.Float64()}
+			target := x["b"].(float64) + rnd.NormFloat64()*0.1
+			if rnd.Intn(2) == 0 {
+				x["a"] = "y"
+				target += 10
+			}
+			x["target"] = target
+			tree.Train(x, 1.0)
+		}
+
+		fi := tree.FeatureImportances()
+		Expect(fi).To(HaveLen(2))
+		Expect(fi[0].Feature).To(Equal("a"))
+		Expect(fi[0].Importance).To(BeNumerically("~", 0.997, 0.001))
+		Expect(fi[0].NumSplits).To(Equal(1))
+		Expect(fi[0].AvgDepth).To(Equal(1.0))
+		Expect(fi[1].Feature).To(Equal("b"))
+		Expect(fi[1].Importance).To(BeNumerically("~", 0.003, 0.001))
+		Expect(fi[1].NumSplits).To(Equal(2))
+		Expect(fi[1].AvgDepth).To(Equal(2.0))
+		Expect(fi.Get("c")).To(BeNil())
+
+		Expect(withoutByteSize(tree.Info())).To(Equal(&common.TreeInfo{NumNodes: 7, NumLearning: 4, MaxDepth: 3, FeatureImportances: fi}))
+	})
+
+	DescribeTable("should limit byte size",
+		func(maxByteSize int, expInfo *common.TreeInfo) {
+			model := core.NewModel(
+				core.NewCategoricalFeature("c", []string{"v0", "v1", "v2", "v3", "v4", "v5", "v6", "v7"}),
+				core.NewNumericalFeature("n"),
+				core.NewNumericalFeature("target"),
+			)
+			rnd := rand.New(rand.NewSource(1))
+
+			tree, err := hoeffding.New(model, "target", &hoeffding.Config{
+				Config: common.Config{GracePeriod: 50, PrunePeriod: 1000, MaxByteSize: maxByteSize},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			for i := 0; i < 10000; i++ {
+				c := rnd.Intn(8)
+				target := rnd.NormFloat64()
+				if c%2 == 0 {
+					target += 10
+				}
+				tree.Train(core.MapExample{"c": fmt.Sprintf("v%d", c), "n": rnd.Float64(), "target": target}, 1.0)
+			}
+			Expect(tree.Info()).To(Equal(expInfo))
+		},
+
+		Entry("unbounded", 1<<30, &common.TreeInfo{NumNodes: 11, NumLearning: 9, MaxDepth: 3, ByteSize: 241520}),
+		Entry("bounded", 100000, &common.TreeInfo{NumNodes: 11, NumLearning: 3, NumDisabled: 6, MaxDepth: 3, ByteSize: 92864}),
+	)
+
+	DescribeTable("should track byte sizes",
+		func(config common.Config) {
+			model := core.NewModel(
+				core.NewCategoricalFeature("a", []string{"x", "y"}),
+				core.NewCategoricalFeature("b", []string{"x", "y", "z"}),
+				core.NewNumericalFeature("n"),
+				core.NewNumericalFeature("target"),
+			)
+			rnd := rand.New(rand.NewSource(1))
+
+			tree, err := hoeffding.New(model, "target", &hoeffding.Config{Config: config})
+			Expect(err).NotTo(HaveOccurred())
+
+			for i := 0; i < 6000; i++ {
+				x := core.MapExample{"a": "x", "b": fmt.Sprintf("%c", 'x'+rnd.Intn(3)), "n": rnd.Float64(), "target": rnd.NormFloat64()}
+				if rnd.Intn(2) == 0 {
+					x["a"] = "y"
+				}
+				if (i < 3000) == (x["a"] == "x") {
+					x["target"] = 10 + rnd.NormFloat64()
+				}
+				tree.Train(x, 1.0)
+			}
+
+			// the running estimate must match a full estimate of the loaded tree
+			buf := new(bytes.Buffer)
+			_, err = tree.WriteTo(buf)
+			Expect(err).NotTo(HaveOccurred())
+
+			loaded, err := hoeffding.Load(buf, &hoeffding.Config{Config: config})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tree.Info().ByteSize).To(BeNumerically(">", 0))
+			Expect(tree.Info().ByteSize).To(Equal(loaded.Info().ByteSize))
+		},
+
+		Entry("default", common.Config{GracePeriod: 50}),
+		Entry("re-evaluated", common.Config{GracePeriod: 50, ReevaluateSplits: true}),
+		Entry("bounded", common.Config{GracePeriod: 50, PrunePeriod: 500, MaxByteSize: 50000}),
+		Entry("poor features removed", common.Config{GracePeriod: 50, RemovePoorFeatures: true}),
+	)
+
+	DescribeTable("should split categorical features",
+		func(binary bool, expInfo *common.TreeInfo, expText string) {
+			model := core.NewModel(
+				core.NewCategoricalFeature("c", []string{"v0", "v1", "v2", "v3", "v4", "v5", "v6", "v7"}),
+				core.NewNumericalFeature("target"),
+			)
+			rnd := rand.New(rand.NewSource(1))
+			stream := func(n int) []core.Example {
+				examples := make([]core.Example, 0, n)
+				for i := 0; i < n; i++ {
+					c := rnd.Intn(8)
+					target := rnd.NormFloat64()
+					if c < 3 {
+						target += 10
+					}
+					examples = append(examples, core.MapExample{"c": fmt.Sprintf("v%d", c), "target": target})
+				}
+				return examples
+			}
+
+			tree, err := hoeffding.New(model, "target", &hoeffding.Config{
+				Config: common.Config{GracePeriod: 100, BinaryCategoricalSplits: binary},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, x := range stream(5000) {
+				tree.Train(x, 1.0)
+			}
+			Expect(withoutByteSize(tree.Info())).To(Equal(expInfo))
+
+			b := new(bytes.Buffer)
+			Expect(tree.WriteText(b)).To(Equal(int64(b.Len())))
+			Expect(b.String()).To(ContainSubstring(expText))
+		},
+
+		Entry("multi-way", false, &common.TreeInfo{NumNodes: 9, NumLearning: 8, MaxDepth: 2}, "\n\tc = v0"),
+		Entry("binary", true, &common.TreeInfo{NumNodes: 3, NumLearning: 2, MaxDepth: 2}, "\n\tc in [v3, v4, v5, v6, v7]"),
+	)
+
+	DescribeTable("should observe skewed numerical features",
+		func(observer common.NumericObserver, expR2 float64) {
+			model := core.NewModel(
+				core.NewNumericalFeature("v"),
+				core.NewNumericalFeature("target"),
+			)
+			rnd := rand.New(rand.NewSource(1))
+			stream := func(n int) []core.Example {
+				examples := make([]core.Example, 0, n)
+				for i := 0; i < n; i++ {
+					v := math.Exp(rnd.NormFloat64() * 3)
+					target := rnd.NormFloat64()
+					if v >= 0.5 {
+						target += 10
+					}
+					examples = append(examples, core.MapExample{"v": v, "target": target})
+				}
+				return examples
+			}
+
+			tree, err := hoeffding.New(model, "target", &hoeffding.Config{
+				Config: common.Config{GracePeriod: 100, NumericObserver: observer},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, x := range stream(3000) {
+				tree.Train(x, 1.0)
+			}
+
+			eval := regression.NewEvaluator()
+			for _, x := range stream(1000) {
+				eval.Record(tree.Predict(nil, x).Best().Mean(), model.Feature("target").Number(x), 1.0)
+			}
+			Expect(eval.R2()).To(BeNumerically("~", expR2, 0.001))
+		},
+
+		Entry("gaussian", common.NumericObserverGaussian, 0.947),
+		Entry("E-BST", common.NumericObserverEBST, 0.956),
+		Entry("quantile sketch", common.NumericObserverQuantileSketch, 0.909),
+	)
+
 	DescribeTable("should train & predict",
 		func(n int, expInfo *common.TreeInfo, exp *testdata.RegressionScore) {
 			tree, model, examples := train(n)
-			Expect(tree.Info()).To(Equal(expInfo))
+			Expect(withoutByteSize(tree.Info())).To(Equal(expInfo))
 
 			eval := regression.NewEvaluator()
 			for _, x := range examples[n:] {
@@ -0,0 +1,155 @@
+package util
+
+import "math"
+
+const (
+	adwinMaxBuckets   = 5
+	adwinMinWinLength = 5
+	adwinClock        = 32
+)
+
+// NewADWIN inits a new change detector with a confidence delta.
+// Default: 0.002
+func NewADWIN(delta float64) *ADWIN {
+	if delta <= 0 {
+		delta = 0.002
+	}
+	return &ADWIN{Delta: delta}
+}
+
+// Add adds a value to the window and returns true if change
+// has been detected.
+func (w *ADWIN) Add(value float64) bool {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return false
+	}
+
+	w.insert(value)
+	w.Ticks++
+
+	if w.Ticks%adwinClock == 0 && w.Width > adwinMinWinLength {
+		return w.detectChange()
+	}
+	return false
+}
+
+// Mean returns the mean of the values in the window.
+func (w *ADWIN) Mean() float64 {
+	if w.Width == 0 {
+		return 0.0
+	}
+	return w.Total / float64(w.Width)
+}
+
+func (w *ADWIN) insert(value float64) {
+	w.Width++
+
+	if len(w.Rows) == 0 {
+		w.Rows = append(w.Rows, ADWIN_Row{})
+	}
+	w.Rows[0].Buckets = append(w.Rows[0].Buckets, ADWIN_Bucket{Total: value})
+
+	if w.Width > 1 {
+		n := float64(w.Width)
+		d := value - w.Total/(n-1)
+		w.Variance += (n - 1) * d * d / n
+	}
+	w.Total += value
+	w.compress()
+}
+
+func (w *ADWIN) compress() {
+	for i := 0; i < len(w.Rows); i++ {
+		row := &w.Rows[i]
+		if len(row.Buckets) <= adwinMaxBuckets {
+			break
+		}
+
+		if i+1 == len(w.Rows) {
+			w.Rows = append(w.Rows, ADWIN_Row{})
+			row = &w.Rows[i]
+		}
+
+		b1, b2 := row.Buckets[0], row.Buckets[1]
+		n := adwinBucketSize(i)
+		u1, u2 := b1.Total/n, b2.Total/n
+		w.Rows[i+1].Buckets = append(w.Rows[i+1].Buckets, ADWIN_Bucket{
+			Total:    b1.Total + b2.Total,
+			Variance: b1.Variance + b2.Variance + n*n*(u1-u2)*(u1-u2)/(n+n),
+		})
+		row.Buckets = append(row.Buckets[:0], row.Buckets[2:]...)
+	}
+}
+
+func (w *ADWIN) detectChange() bool {
+	change := false
+
+	for reduce := true; reduce; {
+		reduce = false
+
+		n0, n1 := 0.0, float64(w.Width)
+		u0, u1 := 0.0, w.Total
+
+	scan:
+		for i := len(w.Rows) - 1; i > -1; i-- {
+			size := adwinBucketSize(i)
+			buckets := w.Rows[i].Buckets
+
+			for k := range buckets {
+				n0 += size
+				n1 -= size
+				u0 += buckets[k].Total
+				u1 -= buckets[k].Total
+
+				if i == 0 && k == len(buckets)-1 {
+					break scan
+				}
+
+				if n1 >= adwinMinWinLength && n0 >= adwinMinWinLength && w.isCut(n0, n1, u0/n0-u1/n1) {
+					reduce = true
+					change = true
+					if w.Width > 0 {
+						w.removeLast()
+					}
+					break scan
+				}
+			}
+		}
+	}
+	return change
+}
+
+func (w *ADWIN) isCut(n0, n1, diff float64) bool {
+	n := float64(w.Width)
+	dd := math.Log(2 * math.Log(n) / w.Delta)
+	v := w.Variance / n
+	m := 1/(n0-adwinMinWinLength+1) + 1/(n1-adwinMinWinLength+1)
+	eps := math.Sqrt(2*m*v*dd) + 2.0/3.0*dd*m
+	return math.Abs(diff) > eps
+}
+
+func (w *ADWIN) removeLast() {
+	last := len(w.Rows) - 1
+	row := &w.Rows[last]
+	bucket := row.Buckets[0]
+
+	n1 := adwinBucketSize(last)
+	w.Width -= int64(n1)
+	w.Total -= bucket.Total
+
+	if n := float64(w.Width); n > 0 {
+		u1 := bucket.Total / n1
+		d := u1 - w.Total/n
+		w.Variance -= bucket.Variance + n1*n*d*d/(n1+n)
+	} else {
+		w.Variance = 0
+	}
+
+	if row.Buckets = row.Buckets[1:]; len(row.Buckets) == 0 {
+		w.Rows = w.Rows[:last]
+	}
+}
+
+func adwinBucketSize(row int) float64 {
+	return math.Pow(2, float64(row))
+}
@@ -0,0 +1,91 @@
+package util_test
+
+import (
+	"math/rand"
+
+	"github.com/bsm/reason/util"
+	"github.com/gogo/protobuf/proto"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ADWIN", func() {
+	var subject *util.ADWIN
+
+	BeforeEach(func() {
+		subject = util.NewADWIN(0)
+	})
+
+	It("should init", func() {
+		Expect(subject.Delta).To(Equal(0.002))
+		Expect(subject.Width).To(Equal(int64(0)))
+		Expect(subject.Mean()).To(Equal(0.0))
+	})
+
+	It("should maintain buckets", func() {
+		for i := 0; i < 100; i++ {
+			Expect(subject.Add(float64(i % 2))).To(BeFalse())
+		}
+		Expect(subject.Width).To(Equal(int64(100)))
+		Expect(subject.Total).To(Equal(50.0))
+		Expect(subject.Mean()).To(Equal(0.5))
+		Expect(subject.Variance / 100).To(BeNumerically("~", 0.25, 0.001))
+		Expect(len(subject.Rows)).To(BeNumerically("<", 7))
+		for _, row := range subject.Rows {
+			Expect(len(row.Buckets)).To(BeNumerically("<=", 5))
+		}
+	})
+
+	It("should not detect change in stationary streams", func() {
+		rnd := rand.New(rand.NewSource(1))
+		changes := 0
+		for i := 0; i < 5000; i++ {
+			if subject.Add(rnd.NormFloat64()*0.1 + 0.5) {
+				changes++
+			}
+		}
+		Expect(changes).To(Equal(0))
+		Expect(subject.Width).To(Equal(int64(5000)))
+		Expect(subject.Mean()).To(BeNumerically("~", 0.5, 0.01))
+	})
+
+	It("should detect change", func() {
+		rnd := rand.New(rand.NewSource(1))
+		for i := 0; i < 1000; i++ {
+			if rnd.Float64() < 0.2 {
+				subject.Add(1)
+			} else {
+				subject.Add(0)
+			}
+		}
+		Expect(subject.Mean()).To(BeNumerically("~", 0.2, 0.05))
+
+		detected := -1
+		for i := 0; i < 1000; i++ {
+			v := 0.0
+			if rnd.Float64() < 0.8 {
+				v = 1.0
+			}
+			if subject.Add(v) && detected < 0 {
+				detected = i
+			}
+		}
+		Expect(detected).To(BeNumerically(">", -1))
+		Expect(detected).To(BeNumerically("<", 100))
+		Expect(subject.Width).To(BeNumerically("<", 1200))
+		Expect(subject.Mean()).To(BeNumerically("~", 0.8, 0.05))
+	})
+
+	It("should marshal", func() {
+		for i := 0; i < 100; i++ {
+			subject.Add(float64(i % 3))
+		}
+
+		data, err := proto.Marshal(subject)
+		Expect(err).NotTo(HaveOccurred())
+
+		restored := new(util.ADWIN)
+		Expect(proto.Unmarshal(data, restored)).To(Succeed())
+		Expect(restored).To(Equal(subject))
+	})
+})
@@ -0,0 +1,182 @@
+package util
+
+import (
+	"sort"
+
+	"github.com/gogo/protobuf/proto"
+)
+
+// The table marshaler of gogo/protobuf cannot encode repeated non-nullable
+// messages that embed a single pointer, which is how dense distributions
+// are generated. Distributions therefore marshal themselves, sparse entries
+// are encoded in the order of their keys.
+
+const (
+	denseTag     = 1<<3 | proto.WireBytes
+	sparseTag    = 2<<3 | proto.WireBytes
+	sparseCapTag = 3<<3 | proto.WireVarint
+
+	embeddedTag   = 1<<3 | proto.WireBytes
+	entryKeyTag   = 1<<3 | proto.WireVarint
+	entryValueTag = 2<<3 | proto.WireBytes
+)
+
+// Size implements proto.Sizer.
+func (x *VectorDistribution) Size() int {
+	n := 0
+	for _, d := range x.Dense {
+		n += denseSize(d.Vector != nil, d.Vector)
+	}
+	for k, vv := range x.Sparse {
+		n += entrySize(k, vv != nil, vv)
+	}
+	return n + sparseCapSize(x.SparseCap)
+}
+
+// Marshal implements proto.Marshaler.
+func (x *VectorDistribution) Marshal() ([]byte, error) {
+	b := newBuffer(x.Size())
+	for _, d := range x.Dense {
+		if err := appendDense(b, d.Vector != nil, d.Vector); err != nil {
+			return nil, err
+		}
+	}
+	keys := make([]int64, 0, len(x.Sparse))
+	for k := range x.Sparse {
+		keys = append(keys, k)
+	}
+	sortKeys(keys)
+	for _, k := range keys {
+		vv := x.Sparse[k]
+		if err := appendEntry(b, k, vv != nil, vv); err != nil {
+			return nil, err
+		}
+	}
+	if err := appendSparseCap(b, x.SparseCap); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// Size implements proto.Sizer.
+func (x *StreamStatsDistribution) Size() int {
+	n := 0
+	for _, d := range x.Dense {
+		n += denseSize(d.StreamStats != nil, d.StreamStats)
+	}
+	for k, s := range x.Sparse {
+		n += entrySize(k, s != nil, s)
+	}
+	return n + sparseCapSize(x.SparseCap)
+}
+
+// Marshal implements proto.Marshaler.
+func (x *StreamStatsDistribution) Marshal() ([]byte, error) {
+	b := newBuffer(x.Size())
+	for _, d := range x.Dense {
+		if err := appendDense(b, d.StreamStats != nil, d.StreamStats); err != nil {
+			return nil, err
+		}
+	}
+	keys := make([]int64, 0, len(x.Sparse))
+	for k := range x.Sparse {
+		keys = append(keys, k)
+	}
+	sortKeys(keys)
+	for _, k := range keys {
+		s := x.Sparse[k]
+		if err := appendEntry(b, k, s != nil, s); err != nil {
+			return nil, err
+		}
+	}
+	if err := appendSparseCap(b, x.SparseCap); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// --------------------------------------------------------------------
+
+func newBuffer(size int) *proto.Buffer {
+	b := proto.NewBuffer(make([]byte, 0, size))
+	b.SetDeterministic(true)
+	return b
+}
+
+func sortKeys(keys []int64) {
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+}
+
+// fieldSize returns the size of a length-delimited field with a
+// single-byte tag.
+func fieldSize(n int) int {
+	return 1 + proto.SizeVarint(uint64(n)) + n
+}
+
+func messageSize(ok bool, m proto.Message) int {
+	if !ok {
+		return 0
+	}
+	return fieldSize(proto.Size(m))
+}
+
+func denseSize(ok bool, m proto.Message) int {
+	return fieldSize(messageSize(ok, m))
+}
+
+func entrySize(key int64, ok bool, m proto.Message) int {
+	return fieldSize(1 + proto.SizeVarint(uint64(key)) + messageSize(ok, m))
+}
+
+func sparseCapSize(n int64) int {
+	if n == 0 {
+		return 0
+	}
+	return 1 + proto.SizeVarint(uint64(n))
+}
+
+func appendMessage(b *proto.Buffer, tag uint64, ok bool, m proto.Message) error {
+	if !ok {
+		return nil
+	}
+	if err := b.EncodeVarint(tag); err != nil {
+		return err
+	}
+	return b.EncodeMessage(m)
+}
+
+func appendDense(b *proto.Buffer, ok bool, m proto.Message) error {
+	if err := b.EncodeVarint(denseTag); err != nil {
+		return err
+	}
+	if err := b.EncodeVarint(uint64(messageSize(ok, m))); err != nil {
+		return err
+	}
+	return appendMessage(b, embeddedTag, ok, m)
+}
+
+func appendEntry(b *proto.Buffer, key int64, ok bool, m proto.Message) error {
+	if err := b.EncodeVarint(sparseTag); err != nil {
+		return err
+	}
+	if err := b.EncodeVarint(uint64(1 + proto.SizeVarint(uint64(key)) + messageSize(ok, m))); err != nil {
+		return err
+	}
+	if err := b.EncodeVarint(entryKeyTag); err != nil {
+		return err
+	}
+	if err := b.EncodeVarint(uint64(key)); err != nil {
+		return err
+	}
+	return appendMessage(b, entryValueTag, ok, m)
+}
+
+func appendSparseCap(b *proto.Buffer, n int64) error {
+	if n == 0 {
+		return nil
+	}
+	if err := b.EncodeVarint(sparseCapTag); err != nil {
+		return err
+	}
+	return b.EncodeVarint(uint64(n))
+}
@@ -19,6 +19,13 @@ func (s *StreamStats) Add(value, weight float64) {
 	s.SumSquares += wv * value
 }
 
+// Merge merges the values of another series.
+func (s *StreamStats) Merge(other *StreamStats) {
+	s.Weight += other.Weight
+	s.Sum += other.Sum
+	s.SumSquares += other.SumSquares
+}
+
 // IsZero returns true if there are no values in the series
 func (s *StreamStats) IsZero() bool { return s.Weight <= 0 }
 
@@ -100,6 +107,23 @@ func (x *StreamStatsDistribution) Add(index int, value, weight float64) {
 	x.fetchSparse(int64(index)).Add(value, weight)
 }
 
+// Merge merges a series into the series at index.
+func (x *StreamStatsDistribution) Merge(index int, s *StreamStats) {
+	if index < 0 {
+		return
+	}
+
+	if x.Dense != nil {
+		x.fetchDense(index).Merge(s)
+		return
+	}
+
+	if x.Sparse == nil {
+		x.Sparse = make(map[int64]*StreamStats)
+	}
+	x.fetchSparse(int64(index)).Merge(s)
+}
+
 // Len returns the number of elements in the distribution.
 func (x *StreamStatsDistribution) Len() int {
 	if x.Dense != nil {
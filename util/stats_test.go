@@ -36,6 +36,15 @@ var _ = Describe("StreamStats", func() {
 		Expect(blank.Sum).To(Equal(0.0))
 	})
 
+	It("should merge", func() {
+		other := new(util.StreamStats)
+		other.Add(2.2, 2)
+		subject.Merge(other)
+		Expect(subject.Weight).To(Equal(11.0))
+		Expect(subject.Sum).To(BeNumerically("~", 53.9, 0.001))
+		Expect(subject.Mean()).To(BeNumerically("~", 4.9, 0.001))
+	})
+
 	It("should calc mean", func() {
 		Expect(subject.Mean()).To(Equal(5.5))
 		subject.Add(8.8, 8)
@@ -115,6 +124,16 @@ var _ = Describe("StreamStatsDistribution", func() {
 		Expect(dense.Get(7)).NotTo(BeNil())
 	})
 
+	It("should merge", func() {
+		sparse.Merge(0, sparse.Get(1))
+		Expect(sparse.Get(0).Weight).To(Equal(9.0))
+		Expect(sparse.Get(0).Sum).To(BeNumerically("~", 49.5, 0.001))
+		Expect(sparse.Get(0).SumSquares).To(BeNumerically("~", 344.85, 0.001))
+
+		dense.Merge(7, dense.Get(1))
+		Expect(dense.Get(7)).To(Equal(dense.Get(1)))
+	})
+
 	It("should have len", func() {
 		Expect(sparse.Len()).To(Equal(2))
 		Expect(dense.Len()).To(Equal(2))
@@ -134,6 +153,21 @@ var _ = Describe("StreamStatsDistribution", func() {
 		Expect(dense.Get(-1)).To(BeNil())
 	})
 
+	It("should marshal", func() {
+		sparse.Add(7, 12.12, 1)
+		dense.Add(7, 12.12, 1)
+
+		for _, x := range []*util.StreamStatsDistribution{sparse, dense} {
+			data, err := proto.Marshal(x)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(data).To(HaveLen(x.Size()))
+
+			decoded := new(util.StreamStatsDistribution)
+			Expect(proto.Unmarshal(data, decoded)).To(Succeed())
+			Expect(decoded).To(Equal(x))
+		}
+	})
+
 	It("should convert to dense", func() {
 		Expect(sparse.Dense).To(BeNil())
 		Expect(sparse.Sparse).To(HaveLen(2))
@@ -12,6 +12,7 @@ It has these top-level messages:
 	StreamStatsDistribution
 	Vector
 	VectorDistribution
+	ADWIN
 */
 package util
 
@@ -106,6 +107,51 @@ func (m *VectorDistribution_Dense) String() string            { return proto.Com
 func (*VectorDistribution_Dense) ProtoMessage()               {}
 func (*VectorDistribution_Dense) Descriptor() ([]byte, []int) { return fileDescriptorUtil, []int{3, 0} }
 
+// ADWIN is an adaptive sliding window algorithm for detecting
+// change in a stream of values. Observations are summarised
+// in exponential histograms of buckets.
+type ADWIN struct {
+	// The confidence value.
+	Delta float64 `protobuf:"fixed64,1,opt,name=delta,proto3" json:"delta,omitempty"`
+	// Bucket rows, where rows[i] contains buckets summarising 2^i values.
+	Rows []ADWIN_Row `protobuf:"bytes,2,rep,name=rows" json:"rows"`
+	// The width of the window.
+	Width int64 `protobuf:"varint,3,opt,name=width,proto3" json:"width,omitempty"`
+	// The sum of values in the window.
+	Total float64 `protobuf:"fixed64,4,opt,name=total,proto3" json:"total,omitempty"`
+	// The variance of values in the window.
+	Variance float64 `protobuf:"fixed64,5,opt,name=variance,proto3" json:"variance,omitempty"`
+	// The number of values observed.
+	Ticks int64 `protobuf:"varint,6,opt,name=ticks,proto3" json:"ticks,omitempty"`
+}
+
+func (m *ADWIN) Reset()                    { *m = ADWIN{} }
+func (m *ADWIN) String() string            { return proto.CompactTextString(m) }
+func (*ADWIN) ProtoMessage()               {}
+func (*ADWIN) Descriptor() ([]byte, []int) { return fileDescriptorUtil, []int{4} }
+
+type ADWIN_Bucket struct {
+	// The sum of values in the bucket.
+	Total float64 `protobuf:"fixed64,1,opt,name=total,proto3" json:"total,omitempty"`
+	// The variance of values in the bucket.
+	Variance float64 `protobuf:"fixed64,2,opt,name=variance,proto3" json:"variance,omitempty"`
+}
+
+func (m *ADWIN_Bucket) Reset()                    { *m = ADWIN_Bucket{} }
+func (m *ADWIN_Bucket) String() string            { return proto.CompactTextString(m) }
+func (*ADWIN_Bucket) ProtoMessage()               {}
+func (*ADWIN_Bucket) Descriptor() ([]byte, []int) { return fileDescriptorUtil, []int{4, 0} }
+
+type ADWIN_Row struct {
+	// Buckets, oldest first.
+	Buckets []ADWIN_Bucket `protobuf:"bytes,1,rep,name=buckets" json:"buckets"`
+}
+
+func (m *ADWIN_Row) Reset()                    { *m = ADWIN_Row{} }
+func (m *ADWIN_Row) String() string            { return proto.CompactTextString(m) }
+func (*ADWIN_Row) ProtoMessage()               {}
+func (*ADWIN_Row) Descriptor() ([]byte, []int) { return fileDescriptorUtil, []int{4, 1} }
+
 func init() {
 	proto.RegisterType((*StreamStats)(nil), "blacksquaremedia.reason.util.StreamStats")
 	proto.RegisterType((*StreamStatsDistribution)(nil), "blacksquaremedia.reason.util.StreamStatsDistribution")
@@ -113,40 +159,51 @@ func init() {
 	proto.RegisterType((*Vector)(nil), "blacksquaremedia.reason.util.Vector")
 	proto.RegisterType((*VectorDistribution)(nil), "blacksquaremedia.reason.util.VectorDistribution")
 	proto.RegisterType((*VectorDistribution_Dense)(nil), "blacksquaremedia.reason.util.VectorDistribution.Dense")
+	proto.RegisterType((*ADWIN)(nil), "blacksquaremedia.reason.util.ADWIN")
+	proto.RegisterType((*ADWIN_Bucket)(nil), "blacksquaremedia.reason.util.ADWIN.Bucket")
+	proto.RegisterType((*ADWIN_Row)(nil), "blacksquaremedia.reason.util.ADWIN.Row")
 }
 
 func init() { proto.RegisterFile("util/util.proto", fileDescriptorUtil) }
 
 var fileDescriptorUtil = []byte{
-	// 479 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x94, 0xdd, 0x6a, 0x13, 0x41,
-	0x14, 0xc7, 0x33, 0xd9, 0x64, 0xc1, 0xb3, 0x17, 0x96, 0x41, 0x74, 0x89, 0x9a, 0x94, 0xe0, 0x45,
-	0xbd, 0x70, 0x23, 0x15, 0x44, 0x5b, 0x41, 0x5c, 0x5b, 0x10, 0x04, 0x91, 0x89, 0xdf, 0x37, 0x61,
-	0x76, 0x33, 0xa6, 0x43, 0xb3, 0x99, 0x38, 0x1f, 0x95, 0x3e, 0x83, 0x37, 0x3e, 0x83, 0x0f, 0x23,
-	0xbd, 0xf4, 0xd2, 0xab, 0x42, 0xf1, 0x45, 0x64, 0x66, 0xb6, 0xb0, 0x29, 0xb4, 0xd9, 0xe4, 0x26,
-	0x9c, 0x99, 0xec, 0xf9, 0x9d, 0x73, 0xfe, 0xe7, 0xcf, 0xc0, 0x75, 0xa3, 0xf9, 0x74, 0x60, 0x7f,
-	0x92, 0xb9, 0x14, 0x5a, 0xe0, 0x3b, 0xd9, 0x94, 0xe6, 0x87, 0xea, 0x9b, 0xa1, 0x92, 0x15, 0x6c,
-	0xcc, 0x69, 0x22, 0x19, 0x55, 0x62, 0x96, 0xd8, 0x6f, 0x3a, 0x0f, 0x26, 0x5c, 0x1f, 0x98, 0x2c,
-	0xc9, 0x45, 0x31, 0x98, 0x88, 0x89, 0x18, 0xb8, 0xa4, 0xcc, 0x7c, 0x75, 0x27, 0x77, 0x70, 0x91,
-	0x87, 0xf5, 0x3f, 0x41, 0x34, 0xd4, 0x92, 0xd1, 0x62, 0xa8, 0xa9, 0x56, 0xf8, 0x26, 0x84, 0xdf,
-	0x19, 0x9f, 0x1c, 0xe8, 0x18, 0x6d, 0xa2, 0x2d, 0x44, 0xca, 0x13, 0xde, 0x80, 0x40, 0x99, 0x22,
-	0x6e, 0xba, 0x4b, 0x1b, 0xe2, 0x1e, 0x44, 0xca, 0x14, 0x23, 0xdf, 0x86, 0x8a, 0x03, 0xf7, 0x0f,
-	0x28, 0x53, 0x0c, 0xfd, 0x4d, 0xff, 0x57, 0x00, 0xb7, 0x2a, 0xe8, 0x3d, 0xae, 0xb4, 0xe4, 0x99,
-	0xd1, 0x5c, 0xcc, 0xf0, 0x47, 0x68, 0x8f, 0xd9, 0x4c, 0xb1, 0x18, 0x6d, 0x06, 0x5b, 0xd1, 0xf6,
-	0x6e, 0x72, 0xd5, 0x48, 0xc9, 0x25, 0x94, 0x64, 0xcf, 0x22, 0xd2, 0xd6, 0xc9, 0x69, 0xaf, 0x41,
-	0x3c, 0x0f, 0x7f, 0x86, 0x50, 0xcd, 0xa9, 0x54, 0x2c, 0x6e, 0x3a, 0xf2, 0x8b, 0xf5, 0xc8, 0x43,
-	0xc7, 0xd8, 0x9f, 0x69, 0x79, 0x4c, 0x4a, 0x20, 0xbe, 0x0b, 0xe0, 0xa3, 0x51, 0x4e, 0xe7, 0x6e,
-	0xde, 0x80, 0x5c, 0xf3, 0x37, 0x2f, 0xe9, 0xbc, 0xf3, 0x06, 0xda, 0xae, 0x1f, 0xbc, 0x0f, 0x6d,
-	0x65, 0x81, 0x4e, 0xc1, 0x68, 0xfb, 0x7e, 0xed, 0x0e, 0xd2, 0xd6, 0x9f, 0xd3, 0x1e, 0x22, 0x3e,
-	0xbb, 0x33, 0x86, 0xa8, 0xd2, 0x85, 0x5d, 0xc0, 0x21, 0x3b, 0x76, 0xcc, 0x80, 0xd8, 0x10, 0x3f,
-	0x87, 0xf6, 0x11, 0x9d, 0x1a, 0xe6, 0x96, 0xb2, 0x4a, 0x1d, 0xe2, 0xf3, 0x76, 0x9a, 0x4f, 0x50,
-	0xff, 0x37, 0x82, 0xf0, 0x03, 0xcb, 0xb5, 0x90, 0x38, 0xae, 0xee, 0x04, 0xa5, 0xcd, 0x0d, 0x74,
-	0x2e, 0xea, 0xab, 0x0b, 0xa2, 0x3e, 0xbc, 0xba, 0x94, 0xe7, 0xad, 0xa3, 0xe1, 0xd3, 0x65, 0x33,
-	0xdf, 0xa8, 0xce, 0x8c, 0xaa, 0x83, 0xfc, 0x08, 0x00, 0xfb, 0xc2, 0x0b, 0x46, 0x23, 0x8b, 0x46,
-	0x7b, 0x5c, 0xa7, 0xf3, 0x65, 0x1e, 0x7b, 0x77, 0x41, 0x8e, 0x67, 0x2b, 0x43, 0xd7, 0x90, 0xe6,
-	0xf5, 0xb9, 0xbd, 0x52, 0x08, 0x8f, 0x1c, 0xb1, 0xf4, 0xd7, 0xbd, 0x3a, 0xd5, 0x4b, 0x6b, 0x95,
-	0x99, 0x9d, 0xd1, 0x32, 0x9d, 0x77, 0x16, 0xbd, 0x55, 0xab, 0x46, 0x65, 0x1b, 0xe9, 0xee, 0xc9,
-	0x59, 0xb7, 0xf1, 0xf7, 0xac, 0x8b, 0x7e, 0xfe, 0xeb, 0x36, 0xe0, 0x76, 0x2e, 0x8a, 0xcb, 0x08,
-	0x29, 0xbc, 0xd7, 0x7c, 0xfa, 0xd6, 0xbe, 0x45, 0xea, 0x4b, 0xcb, 0xe2, 0xb2, 0xd0, 0xbd, 0x4c,
-	0x8f, 0xfe, 0x07, 0x00, 0x00, 0xff, 0xff, 0x32, 0x81, 0xc8, 0x2d, 0xf9, 0x04, 0x00, 0x00,
+	// 595 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x94, 0xdd, 0x8a, 0xd3, 0x40,
+	0x14, 0xc7, 0x77, 0x92, 0x26, 0xea, 0xe9, 0x85, 0xcb, 0x20, 0x1a, 0xa2, 0xee, 0x2e, 0x8b, 0xe0,
+	0x2a, 0x98, 0xca, 0x0a, 0xa2, 0x5d, 0x41, 0x1a, 0xbb, 0xe0, 0x07, 0x2c, 0x9a, 0xaa, 0xab, 0xde,
+	0x94, 0x49, 0x3a, 0xb6, 0xa1, 0x49, 0xa7, 0x66, 0x26, 0x2d, 0xfb, 0x0c, 0xde, 0xf8, 0x0c, 0x3e,
+	0x8c, 0xec, 0x8d, 0xe0, 0xa5, 0x57, 0x0b, 0x8b, 0x2f, 0x22, 0x33, 0x93, 0x6a, 0xba, 0xd0, 0x8f,
+	0xed, 0x4d, 0x99, 0x33, 0xe9, 0xf9, 0x9d, 0x73, 0xfe, 0xe7, 0xcf, 0xc0, 0xe5, 0x5c, 0xc4, 0x49,
+	0x4d, 0xfe, 0x78, 0xc3, 0x8c, 0x09, 0x86, 0x6f, 0x84, 0x09, 0x89, 0xfa, 0xfc, 0x4b, 0x4e, 0x32,
+	0x9a, 0xd2, 0x4e, 0x4c, 0xbc, 0x8c, 0x12, 0xce, 0x06, 0x9e, 0xfc, 0x8f, 0x7b, 0xaf, 0x1b, 0x8b,
+	0x5e, 0x1e, 0x7a, 0x11, 0x4b, 0x6b, 0x5d, 0xd6, 0x65, 0x35, 0x95, 0x14, 0xe6, 0x9f, 0x55, 0xa4,
+	0x02, 0x75, 0xd2, 0xb0, 0xed, 0x0f, 0x50, 0x6d, 0x89, 0x8c, 0x92, 0xb4, 0x25, 0x88, 0xe0, 0xf8,
+	0x2a, 0xd8, 0x63, 0x1a, 0x77, 0x7b, 0xc2, 0x41, 0x5b, 0x68, 0x07, 0x05, 0x45, 0x84, 0xd7, 0xc1,
+	0xe4, 0x79, 0xea, 0x18, 0xea, 0x52, 0x1e, 0xf1, 0x26, 0x54, 0x79, 0x9e, 0xb6, 0x75, 0x1b, 0xdc,
+	0x31, 0xd5, 0x17, 0xe0, 0x79, 0xda, 0xd2, 0x37, 0xdb, 0xdf, 0x4d, 0xb8, 0x56, 0x42, 0x37, 0x63,
+	0x2e, 0xb2, 0x38, 0xcc, 0x45, 0xcc, 0x06, 0xf8, 0x10, 0xac, 0x0e, 0x1d, 0x70, 0xea, 0xa0, 0x2d,
+	0x73, 0xa7, 0xba, 0xbb, 0xe7, 0xcd, 0x1b, 0xc9, 0x9b, 0x41, 0xf1, 0x9a, 0x12, 0xe1, 0x57, 0x8e,
+	0x4f, 0x36, 0xd7, 0x02, 0xcd, 0xc3, 0x1f, 0xc1, 0xe6, 0x43, 0x92, 0x71, 0xea, 0x18, 0x8a, 0xdc,
+	0x58, 0x8d, 0xdc, 0x52, 0x8c, 0xfd, 0x81, 0xc8, 0x8e, 0x82, 0x02, 0x88, 0x6f, 0x02, 0xe8, 0x53,
+	0x3b, 0x22, 0x43, 0x35, 0xaf, 0x19, 0x5c, 0xd2, 0x37, 0xcf, 0xc8, 0xd0, 0x3d, 0x00, 0x4b, 0xf5,
+	0x83, 0xf7, 0xc1, 0xe2, 0x12, 0xa8, 0x14, 0xac, 0xee, 0xde, 0x59, 0xba, 0x03, 0xbf, 0xf2, 0xeb,
+	0x64, 0x13, 0x05, 0x3a, 0xdb, 0xed, 0x40, 0xb5, 0xd4, 0x85, 0x5c, 0x40, 0x9f, 0x1e, 0x29, 0xa6,
+	0x19, 0xc8, 0x23, 0x7e, 0x0a, 0xd6, 0x88, 0x24, 0x39, 0x75, 0x8c, 0x73, 0xd6, 0x09, 0x74, 0x5e,
+	0xdd, 0x78, 0x84, 0xb6, 0x7f, 0x20, 0xb0, 0xdf, 0xd3, 0x48, 0xb0, 0x0c, 0x3b, 0xe5, 0x9d, 0x20,
+	0xdf, 0x58, 0x47, 0x13, 0x51, 0x9f, 0x9f, 0x11, 0xf5, 0xfe, 0xfc, 0x52, 0x9a, 0xb7, 0x8a, 0x86,
+	0x8f, 0x17, 0xcd, 0x7c, 0xa5, 0x3c, 0x33, 0x2a, 0x0f, 0xf2, 0xd5, 0x04, 0xac, 0x0b, 0x4f, 0x19,
+	0x2d, 0x98, 0x36, 0xda, 0xc3, 0x65, 0x3a, 0x5f, 0xe4, 0xb1, 0xb7, 0x67, 0xe4, 0x78, 0x72, 0x6e,
+	0xe8, 0x0a, 0xd2, 0xbc, 0x9a, 0xd8, 0xcb, 0x07, 0x7b, 0xa4, 0x88, 0x85, 0xbf, 0x6e, 0x2d, 0x53,
+	0xbd, 0xb0, 0x56, 0x91, 0xe9, 0xb6, 0x17, 0xe9, 0x5c, 0x9f, 0xf6, 0xd6, 0x52, 0x35, 0xca, 0xdb,
+	0xf8, 0x69, 0x80, 0xd5, 0x68, 0x1e, 0xbe, 0x38, 0x90, 0x1b, 0xeb, 0xd0, 0x44, 0x90, 0xe2, 0x3d,
+	0xd1, 0x01, 0x6e, 0x40, 0x25, 0x63, 0x63, 0x5e, 0x08, 0x78, 0x7b, 0x3e, 0x5e, 0x81, 0xbc, 0x80,
+	0x8d, 0x8b, 0x35, 0xa8, 0x54, 0x09, 0x1e, 0xc7, 0x1d, 0xd1, 0x2b, 0xa4, 0xd2, 0x81, 0xbc, 0x15,
+	0x4c, 0x90, 0xc4, 0xa9, 0xe8, 0x72, 0x2a, 0xc0, 0x2e, 0x5c, 0x1c, 0x91, 0x2c, 0x26, 0x83, 0x88,
+	0x3a, 0x96, 0xfa, 0xf0, 0x2f, 0x56, 0x19, 0x71, 0xd4, 0xe7, 0x8e, 0xad, 0x39, 0x2a, 0x70, 0xeb,
+	0x60, 0xfb, 0x79, 0xd4, 0xa7, 0xe2, 0x3f, 0x11, 0xcd, 0x22, 0x1a, 0xd3, 0x44, 0xf7, 0x0d, 0x98,
+	0x01, 0x1b, 0xe3, 0x97, 0x70, 0x21, 0x54, 0x08, 0x5e, 0x98, 0xef, 0xee, 0x32, 0x63, 0xea, 0xaa,
+	0xc5, 0xa4, 0x13, 0x80, 0xbf, 0x77, 0x7c, 0xba, 0xb1, 0xf6, 0xfb, 0x74, 0x03, 0x7d, 0xfb, 0xb3,
+	0xb1, 0x06, 0xd7, 0x23, 0x96, 0xce, 0x62, 0xf9, 0xf0, 0x4e, 0xc4, 0xc9, 0x6b, 0xf9, 0xb6, 0xf3,
+	0x4f, 0x15, 0x09, 0x0e, 0x6d, 0xf5, 0xd2, 0x3f, 0xf8, 0x3b, 0x00, 0xf1, 0x4a, 0x3a, 0x5c, 0x49,
+	0x06, 0x00, 0x00,
 }
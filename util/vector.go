@@ -47,10 +47,11 @@ func (vv *Vector) Min() (pos int, weight float64) {
 }
 
 // Max returns the position od the maximum weight in the vector (with value).
+// Ties are resolved in favour of the lowest position.
 func (vv *Vector) Max() (pos int, weight float64) {
 	pos = -1
 	vv.ForEach(func(i int, w float64) bool {
-		if w > weight {
+		if w > weight || (w == weight && w > 0 && i < pos) {
 			pos = i
 			weight = w
 		}
@@ -111,6 +111,13 @@ var _ = Describe("Vector", func() {
 		i, w = blank.Max()
 		Expect(i).To(Equal(-1))
 		Expect(w).To(Equal(0.0))
+
+		tied := &util.Vector{Sparse: map[int64]float64{7: 3, 2: 3, 5: 3, 9: 1}}
+		for n := 0; n < 20; n++ {
+			i, w = tied.Max()
+			Expect(i).To(Equal(2))
+			Expect(w).To(Equal(3.0))
+		}
 	})
 
 	It("should calculate mean", func() {
@@ -218,6 +225,21 @@ var _ = Describe("VectorDistribution", func() {
 		Expect(dense.Get(-1)).To(BeNil())
 	})
 
+	It("should marshal", func() {
+		sparse.Add(7, 12, 1)
+		dense.Add(7, 12, 1)
+
+		for _, x := range []*util.VectorDistribution{sparse, dense} {
+			data, err := proto.Marshal(x)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(data).To(HaveLen(x.Size()))
+
+			decoded := new(util.VectorDistribution)
+			Expect(proto.Unmarshal(data, decoded)).To(Succeed())
+			Expect(decoded).To(Equal(x))
+		}
+	})
+
 	It("should convert to dense", func() {
 		Expect(sparse.Dense).To(BeNil())
 		Expect(sparse.Sparse).To(HaveLen(2))